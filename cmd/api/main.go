@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/che1nov/Pr-reviewer-assignment-service/config"
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/app"
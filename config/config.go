@@ -1,6 +1,10 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type Config struct {
 	LogLevel    string
@@ -8,15 +12,113 @@ type Config struct {
 	AdminToken  string
 	UserToken   string
 	DatabaseURL string
+
+	// AssignmentStrategy способ выбора ревьюеров: random, history, weighted или round_robin.
+	AssignmentStrategy string
+	// PairingHistoryWindow сколько последних PR автора учитывает стратегия history.
+	PairingHistoryWindow int
+	// PreferWorkingHours сначала выбирает ревьюеров, у которых сейчас рабочее время.
+	PreferWorkingHours bool
+
+	// JobWorkers число обработчиков фоновых задач.
+	JobWorkers int
+	// JobTimeout сколько может выполняться одна фоновая задача.
+	JobTimeout time.Duration
+	// JobPollInterval как часто обработчик проверяет пустую очередь.
+	JobPollInterval time.Duration
+
+	// OutboxPollInterval как часто релей проверяет outbox, когда доставлять нечего.
+	OutboxPollInterval time.Duration
+	// OutboxBatchSize сколько событий релей забирает за раз.
+	OutboxBatchSize int
+
+	// WebhookPollInterval как часто отправитель вебхуков проверяет очередь доставок.
+	WebhookPollInterval time.Duration
+	// WebhookBatchSize сколько доставок отправитель забирает за раз.
+	WebhookBatchSize int
+	// WebhookTimeout сколько ждать ответа получателя вебхука.
+	WebhookTimeout time.Duration
+
+	// GitHubWebhookSecret секрет, которым GitHub подписывает вебхуки.
+	GitHubWebhookSecret string
+	// GitHubAPIURL адрес REST API GitHub; для GitHub Enterprise или локальной заглушки.
+	GitHubAPIURL string
+	// GitHubToken токен для запроса ревью; без него ревьюеры в GitHub не отправляются.
+	GitHubToken string
+
+	// GitLabWebhookToken секретный токен, который GitLab передаёт в X-Gitlab-Token.
+	GitLabWebhookToken string
+
+	// ChatProvider чат для личных уведомлений: slack или mattermost.
+	ChatProvider string
+	// ChatWebhookURL входящий вебхук чата; без него уведомления в чат не отправляются.
+	ChatWebhookURL string
+
+	// SMTPHost почтовый сервер для уведомлений; без него письма не отправляются.
+	SMTPHost string
+	// SMTPPort порт почтового сервера; STARTTLS включается, если сервер его поддерживает.
+	SMTPPort int
+	// SMTPUsername и SMTPPassword для авторизации PLAIN; без имени письма отправляются без неё.
+	SMTPUsername string
+	SMTPPassword string
+	// SMTPFrom адрес отправителя писем.
+	SMTPFrom string
+	// SMTPLocale язык шаблонов писем: ru или en.
+	SMTPLocale string
+
+	// ReviewOverdueAfter через сколько после назначения ревьюеру напоминают о ревью.
+	ReviewOverdueAfter time.Duration
+	// ReviewOverdueCheckInterval как часто искать просроченные ревью.
+	ReviewOverdueCheckInterval time.Duration
+
+	// EventStreamPollInterval как часто поток событий проверяет журнал на новые события.
+	EventStreamPollInterval time.Duration
+
+	// DigestHour в котором часу по времени пользователя отправляется дайджест ревью.
+	DigestHour int
+	// DigestCheckInterval как часто проверять, кому пора отправить дайджест.
+	DigestCheckInterval time.Duration
+
+	// AbsenceCheckInterval как часто снимать закончившиеся отсутствия.
+	AbsenceCheckInterval time.Duration
 }
 
 func Load() Config {
 	return Config{
-		LogLevel:    os.Getenv("LOG_LEVEL"),
-		HTTPPort:    fallback(os.Getenv("HTTP_PORT"), "8080"),
-		AdminToken:  os.Getenv("ADMIN_TOKEN"),
-		UserToken:   os.Getenv("USER_TOKEN"),
-		DatabaseURL: os.Getenv("DATABASE_URL"),
+		LogLevel:                   os.Getenv("LOG_LEVEL"),
+		HTTPPort:                   fallback(os.Getenv("HTTP_PORT"), "8080"),
+		AdminToken:                 os.Getenv("ADMIN_TOKEN"),
+		UserToken:                  os.Getenv("USER_TOKEN"),
+		DatabaseURL:                os.Getenv("DATABASE_URL"),
+		AssignmentStrategy:         fallback(os.Getenv("ASSIGNMENT_STRATEGY"), "random"),
+		PairingHistoryWindow:       fallbackInt(os.Getenv("PAIRING_HISTORY_WINDOW"), 10),
+		PreferWorkingHours:         fallbackBool(os.Getenv("ASSIGNMENT_PREFER_WORKING_HOURS"), false),
+		JobWorkers:                 fallbackInt(os.Getenv("JOB_WORKERS"), 2),
+		JobTimeout:                 fallbackDuration(os.Getenv("JOB_TIMEOUT"), 5*time.Minute),
+		JobPollInterval:            fallbackDuration(os.Getenv("JOB_POLL_INTERVAL"), time.Second),
+		OutboxPollInterval:         fallbackDuration(os.Getenv("OUTBOX_POLL_INTERVAL"), time.Second),
+		OutboxBatchSize:            fallbackInt(os.Getenv("OUTBOX_BATCH_SIZE"), 100),
+		WebhookPollInterval:        fallbackDuration(os.Getenv("WEBHOOK_POLL_INTERVAL"), time.Second),
+		WebhookBatchSize:           fallbackInt(os.Getenv("WEBHOOK_BATCH_SIZE"), 50),
+		WebhookTimeout:             fallbackDuration(os.Getenv("WEBHOOK_TIMEOUT"), 10*time.Second),
+		GitHubWebhookSecret:        os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitHubAPIURL:               fallback(os.Getenv("GITHUB_API_URL"), "https://api.github.com"),
+		GitHubToken:                os.Getenv("GITHUB_TOKEN"),
+		GitLabWebhookToken:         os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+		ChatProvider:               fallback(os.Getenv("CHAT_PROVIDER"), "slack"),
+		ChatWebhookURL:             os.Getenv("CHAT_WEBHOOK_URL"),
+		SMTPHost:                   os.Getenv("SMTP_HOST"),
+		SMTPPort:                   fallbackInt(os.Getenv("SMTP_PORT"), 587),
+		SMTPUsername:               os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:               os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:                   fallback(os.Getenv("SMTP_FROM"), "pr-reviewer@localhost"),
+		SMTPLocale:                 fallback(os.Getenv("SMTP_LOCALE"), "ru"),
+		ReviewOverdueAfter:         fallbackDuration(os.Getenv("REVIEW_OVERDUE_AFTER"), 48*time.Hour),
+		ReviewOverdueCheckInterval: fallbackDuration(os.Getenv("REVIEW_OVERDUE_CHECK_INTERVAL"), 10*time.Minute),
+		EventStreamPollInterval:    fallbackDuration(os.Getenv("EVENT_STREAM_POLL_INTERVAL"), time.Second),
+		DigestHour:                 fallbackInt(os.Getenv("DIGEST_HOUR"), 9),
+		DigestCheckInterval:        fallbackDuration(os.Getenv("DIGEST_CHECK_INTERVAL"), 5*time.Minute),
+		AbsenceCheckInterval:       fallbackDuration(os.Getenv("ABSENCE_CHECK_INTERVAL"), time.Minute),
 	}
 }
 
@@ -26,3 +128,27 @@ func fallback(value, def string) string {
 	}
 	return value
 }
+
+func fallbackInt(value string, def int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+func fallbackBool(value string, def bool) bool {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return def
+	}
+	return parsed
+}
+
+func fallbackDuration(value string, def time.Duration) time.Duration {
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		return def
+	}
+	return parsed
+}
@@ -0,0 +1,109 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// maxErrorBody сколько тела ответа с ошибкой попадает в текст ошибки
+const maxErrorBody = 1 << 10
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// slackEscaper экранирует символы, которые Slack считает разметкой ссылок и упоминаний
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownEscaper экранирует разметку Markdown в Mattermost
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "~", `\~`, "[", `\[`, "]", `\]`,
+)
+
+// WebhookNotifier отправляет личные сообщения через входящий вебхук чата.
+// Получатель задаётся каналом "@имя", поэтому вебхук должен разрешать переопределение канала.
+type WebhookNotifier struct {
+	provider  string
+	url       string
+	templates *template.Template
+	client    *http.Client
+}
+
+// NewSlackNotifier уведомления через Slack-совместимый входящий вебхук.
+func NewSlackNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return newWebhookNotifier(domain.IdentityProviderSlack, url, slackEscaper.Replace, timeout)
+}
+
+// NewMattermostNotifier уведомления через входящий вебхук Mattermost.
+func NewMattermostNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return newWebhookNotifier(domain.IdentityProviderMattermost, url, markdownEscaper.Replace, timeout)
+}
+
+func newWebhookNotifier(provider, url string, escape func(string) string, timeout time.Duration) *WebhookNotifier {
+	tmpl := template.Must(template.New(provider).
+		Funcs(template.FuncMap{"escape": escape, "hours": hours, "days": days}).
+		ParseFS(templates, "templates/"+provider+".tmpl"))
+
+	return &WebhookNotifier{
+		provider:  provider,
+		url:       url,
+		templates: tmpl,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) Provider() string {
+	return n.provider
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, handle string, notification domain.Notification) error {
+	var text bytes.Buffer
+	if err := n.templates.ExecuteTemplate(&text, notification.Kind, notification); err != nil {
+		return fmt.Errorf("шаблон уведомления %q: %w", notification.Kind, err)
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"channel": "@" + strings.TrimPrefix(handle, "@"),
+		"text":    text.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s ответил %d: %s", n.provider, resp.StatusCode, bytes.TrimSpace(message))
+	}
+	return nil
+}
+
+// hours сколько полных часов в d
+func hours(d time.Duration) int {
+	return int(d / time.Hour)
+}
+
+// days сколько полных суток в d
+func days(d time.Duration) int {
+	return int(d / (24 * time.Hour))
+}
@@ -0,0 +1,140 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	notification := domain.Notification{
+		Kind:          domain.NotificationAssigned,
+		UserID:        "u1",
+		PullRequestID: "acme/api#42",
+		Title:         "Fix <@channel> *bold* & [link]",
+		AuthorName:    "Alice_Smith",
+	}
+
+	tests := []struct {
+		name        string
+		newNotifier func(url string, timeout time.Duration) *WebhookNotifier
+		provider    string
+		handle      string
+		wantChannel string
+		wantText    string
+	}{
+		{
+			name:        "slack escapes mentions and links",
+			newNotifier: NewSlackNotifier,
+			provider:    domain.IdentityProviderSlack,
+			handle:      "bob",
+			wantChannel: "@bob",
+			wantText:    "Вас назначили ревьюером *Fix &lt;@channel&gt; *bold* &amp; [link]* (acme/api#42), автор Alice_Smith.",
+		},
+		{
+			name:        "mattermost escapes markdown",
+			newNotifier: NewMattermostNotifier,
+			provider:    domain.IdentityProviderMattermost,
+			handle:      "@bob",
+			wantChannel: "@bob",
+			wantText:    "Вас назначили ревьюером **Fix <@channel> \\*bold\\* & \\[link\\]** (`acme/api#42`), автор Alice\\_Smith.",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			type received struct {
+				contentType string
+				payload     map[string]any
+			}
+			requests := make(chan received, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&payload)
+				requests <- received{contentType: r.Header.Get("Content-Type"), payload: payload}
+				_, _ = w.Write([]byte("ok"))
+			}))
+			defer server.Close()
+
+			notifier := tt.newNotifier(server.URL, time.Second)
+			if notifier.Provider() != tt.provider {
+				t.Fatalf("expected provider %q, got %q", tt.provider, notifier.Provider())
+			}
+			if err := notifier.Notify(ctx, tt.handle, notification); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := <-requests
+			if got.contentType != "application/json" {
+				t.Fatalf("expected json, got %q", got.contentType)
+			}
+			if len(got.payload) != 2 {
+				t.Fatalf("expected only channel and text, got %v", got.payload)
+			}
+			if got.payload["channel"] != tt.wantChannel {
+				t.Fatalf("expected channel %q, got %v", tt.wantChannel, got.payload["channel"])
+			}
+			if got.payload["text"] != tt.wantText {
+				t.Fatalf("expected text\n%q\ngot\n%q", tt.wantText, got.payload["text"])
+			}
+		})
+	}
+
+	t.Run("every kind has a template", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer server.Close()
+
+		kinds := []domain.Notification{
+			{Kind: domain.NotificationAssigned, Title: "T", PullRequestID: "p", AuthorName: "A"},
+			{Kind: domain.NotificationReassigned, Title: "T", PullRequestID: "p", AuthorName: "A", OtherReviewerName: "B"},
+			{Kind: domain.NotificationUnassigned, Title: "T", PullRequestID: "p"},
+			{Kind: domain.NotificationOverdue, Title: "T", PullRequestID: "p", AuthorName: "A", Waiting: 50 * time.Hour},
+			{Kind: domain.NotificationDigest, Digest: []domain.DigestGroup{{Age: "fresh", Items: []domain.DigestItem{{Title: "T"}}}}},
+		}
+		for _, newNotifier := range []func(string, time.Duration) *WebhookNotifier{NewSlackNotifier, NewMattermostNotifier} {
+			notifier := newNotifier(server.URL, time.Second)
+			for _, n := range kinds {
+				if err := notifier.Notify(ctx, "bob", n); err != nil {
+					t.Fatalf("%s %s: %v", notifier.Provider(), n.Kind, err)
+				}
+			}
+		}
+	})
+
+	t.Run("unknown kind is an error", func(t *testing.T) {
+		t.Parallel()
+
+		if err := NewSlackNotifier("http://127.0.0.1:0", time.Second).Notify(ctx, "bob", domain.Notification{Kind: "unknown"}); err == nil {
+			t.Fatal("expected template error")
+		}
+	})
+
+	t.Run("non-2xx response is a failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("channel_not_found"))
+		}))
+		defer server.Close()
+
+		err := NewMattermostNotifier(server.URL, time.Second).Notify(ctx, "bob", notification)
+		if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "channel_not_found") {
+			t.Fatalf("expected error with status and body, got %v", err)
+		}
+	})
+}
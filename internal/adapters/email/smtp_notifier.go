@@ -0,0 +1,204 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Config параметры SMTP-сервера. Без Username письма отправляются без авторизации.
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// Locale каталог шаблонов в templates, например ru или en
+	Locale  string
+	Timeout time.Duration
+}
+
+// SMTPNotifier отправляет уведомления письмами с текстовой и HTML-версией.
+// Если сервер поддерживает STARTTLS, соединение шифруется до авторизации.
+type SMTPNotifier struct {
+	cfg Config
+	// sender адрес из From для конверта письма
+	sender string
+	text   *texttemplate.Template
+	html   *htmltemplate.Template
+}
+
+// NewSMTPNotifier загружает шаблоны локали; неизвестная локаль - ошибка конфигурации.
+func NewSMTPNotifier(cfg Config) (*SMTPNotifier, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("адрес отправителя %q: %w", cfg.From, err)
+	}
+
+	funcs := map[string]any{"hours": hours, "days": days}
+
+	text, err := texttemplate.New("text").Funcs(funcs).ParseFS(templates, "templates/"+cfg.Locale+"/text.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("шаблоны писем для локали %q: %w", cfg.Locale, err)
+	}
+	html, err := htmltemplate.New("html").Funcs(funcs).ParseFS(templates, "templates/"+cfg.Locale+"/html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("шаблоны писем для локали %q: %w", cfg.Locale, err)
+	}
+
+	return &SMTPNotifier{
+		cfg:    cfg,
+		sender: from.Address,
+		text:   text,
+		html:   html,
+	}, nil
+}
+
+func (n *SMTPNotifier) Provider() string {
+	return domain.IdentityProviderEmail
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, address string, notification domain.Notification) error {
+	message, err := n.message(address, notification)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, address, message)
+}
+
+// message собирает письмо multipart/alternative: сначала текст, потом HTML.
+func (n *SMTPNotifier) message(to string, notification domain.Notification) ([]byte, error) {
+	var subject, text, html bytes.Buffer
+	if err := n.text.ExecuteTemplate(&subject, notification.Kind+".subject", notification); err != nil {
+		return nil, fmt.Errorf("шаблон письма %q: %w", notification.Kind, err)
+	}
+	if err := n.text.ExecuteTemplate(&text, notification.Kind, notification); err != nil {
+		return nil, fmt.Errorf("шаблон письма %q: %w", notification.Kind, err)
+	}
+	if err := n.html.ExecuteTemplate(&html, notification.Kind, notification); err != nil {
+		return nil, fmt.Errorf("шаблон письма %q: %w", notification.Kind, err)
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=UTF-8", content: text.Bytes()},
+		{contentType: "text/html; charset=UTF-8", content: html.Bytes()},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err := encoder.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	headers := [][2]string{
+		{"From", n.cfg.From},
+		{"To", to},
+		{"Subject", mime.QEncoding.Encode("UTF-8", strings.TrimSpace(subject.String()))},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
+
+func (n *SMTPNotifier) send(ctx context.Context, to string, message []byte) error {
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	dialer := net.Dialer{Timeout: n.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp не принимает ctx, поэтому весь диалог ограничен таймаутом соединения
+	if err := conn.SetDeadline(time.Now().Add(n.cfg.Timeout)); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.sender); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// hours сколько полных часов в d
+func hours(d time.Duration) int {
+	return int(d / time.Hour)
+}
+
+// days сколько полных суток в d
+func days(d time.Duration) int {
+	return int(d / (24 * time.Hour))
+}
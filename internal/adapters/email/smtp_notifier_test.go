@@ -0,0 +1,298 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	notification := domain.Notification{
+		Kind:              domain.NotificationReassigned,
+		UserID:            "u1",
+		PullRequestID:     "acme/api#42",
+		Title:             "Fix <script> & friends",
+		AuthorName:        "Alice",
+		OtherReviewerName: "Bob",
+	}
+
+	tests := []struct {
+		locale      string
+		wantSubject string
+		wantText    string
+		wantHTML    string
+	}{
+		{
+			locale:      "en",
+			wantSubject: "Review: Fix <script> & friends",
+			wantText:    `You have been assigned to review pull request "Fix <script> & friends" (acme/api#42) by Alice, replacing Bob.`,
+			wantHTML:    "Fix &lt;script&gt; &amp; friends",
+		},
+		{
+			locale:      "ru",
+			wantSubject: "Ревью: Fix <script> & friends",
+			wantText:    "Вас назначили ревьюером pull request «Fix <script> & friends» (acme/api#42) вместо Bob, автор Alice.",
+			wantHTML:    "Fix &lt;script&gt; &amp; friends",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.locale, func(t *testing.T) {
+			t.Parallel()
+
+			server := newFakeSMTPServer(t)
+			defer server.Close()
+
+			notifier := newTestNotifier(t, server, tt.locale)
+			if err := notifier.Notify(ctx, "bob@acme.dev", notification); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sent := <-server.mails
+			if sent.from != "reviews@acme.dev" || sent.to != "bob@acme.dev" {
+				t.Fatalf("unexpected envelope from %q to %q", sent.from, sent.to)
+			}
+
+			message, err := mail.ReadMessage(strings.NewReader(sent.data))
+			if err != nil {
+				t.Fatalf("read message: %v", err)
+			}
+			if got := message.Header.Get("To"); got != "bob@acme.dev" {
+				t.Fatalf("expected To bob@acme.dev, got %q", got)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			if err != nil || subject != tt.wantSubject {
+				t.Fatalf("expected subject %q, got %q, %v", tt.wantSubject, subject, err)
+			}
+			if raw := message.Header.Get("Subject"); tt.locale == "ru" && !strings.HasPrefix(raw, "=?UTF-8?q?") {
+				t.Fatalf("expected Q-encoded subject, got %q", raw)
+			}
+
+			mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+			if err != nil || mediaType != "multipart/alternative" {
+				t.Fatalf("expected multipart/alternative, got %q, %v", mediaType, err)
+			}
+
+			parts := readParts(t, multipart.NewReader(message.Body, params["boundary"]))
+			if len(parts) != 2 {
+				t.Fatalf("expected text and html parts, got %d", len(parts))
+			}
+			if parts[0].contentType != "text/plain; charset=UTF-8" || parts[1].contentType != "text/html; charset=UTF-8" {
+				t.Fatalf("expected text then html, got %q, %q", parts[0].contentType, parts[1].contentType)
+			}
+			for _, part := range parts {
+				if part.encoding != "quoted-printable" {
+					t.Fatalf("expected quoted-printable, got %q", part.encoding)
+				}
+			}
+			if !strings.Contains(parts[0].body, tt.wantText) {
+				t.Fatalf("text part %q does not contain %q", parts[0].body, tt.wantText)
+			}
+			if !strings.Contains(parts[1].body, tt.wantHTML) || strings.Contains(parts[1].body, "<script>") {
+				t.Fatalf("expected escaped title in html part, got %q", parts[1].body)
+			}
+			if tt.locale == "ru" && !strings.Contains(parts[0].raw, "=D0") {
+				t.Fatalf("expected cyrillic to be quoted-printable encoded, got %q", parts[0].raw)
+			}
+		})
+	}
+
+	t.Run("digest uses locale templates", func(t *testing.T) {
+		t.Parallel()
+
+		server := newFakeSMTPServer(t)
+		defer server.Close()
+
+		digest := domain.Notification{
+			Kind:   domain.NotificationDigest,
+			UserID: "u1",
+			Digest: []domain.DigestGroup{{
+				Age:   "stale",
+				Items: []domain.DigestItem{{PullRequestID: "acme/api#7", Title: "Old", AuthorName: "Alice", Age: 80 * time.Hour}},
+			}},
+		}
+		if err := newTestNotifier(t, server, "en").Notify(ctx, "bob@acme.dev", digest); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		message, err := mail.ReadMessage(strings.NewReader((<-server.mails).data))
+		if err != nil {
+			t.Fatalf("read message: %v", err)
+		}
+		_, params, _ := mime.ParseMediaType(message.Header.Get("Content-Type"))
+		parts := readParts(t, multipart.NewReader(message.Body, params["boundary"]))
+		if !strings.Contains(parts[1].body, "More than three days") || !strings.Contains(parts[1].body, "3d") {
+			t.Fatalf("expected english digest group and age, got %q", parts[1].body)
+		}
+	})
+
+	t.Run("unknown locale is a configuration error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewSMTPNotifier(Config{From: "reviews@acme.dev", Locale: "de"}); err == nil {
+			t.Fatal("expected error for unknown locale")
+		}
+	})
+
+	t.Run("rejected recipient is a failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := newFakeSMTPServer(t)
+		server.rejectRcpt = true
+		defer server.Close()
+
+		if err := newTestNotifier(t, server, "en").Notify(ctx, "nobody@acme.dev", notification); err == nil {
+			t.Fatal("expected error for rejected recipient")
+		}
+	})
+}
+
+func newTestNotifier(t *testing.T, server *fakeSMTPServer, locale string) *SMTPNotifier {
+	t.Helper()
+
+	notifier, err := NewSMTPNotifier(Config{
+		Host:    "127.0.0.1",
+		Port:    server.listener.Addr().(*net.TCPAddr).Port,
+		From:    "Reviews <reviews@acme.dev>",
+		Locale:  locale,
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	return notifier
+}
+
+// mailPart часть письма: raw - как пришла, body - после декодирования quoted-printable.
+type mailPart struct {
+	contentType string
+	encoding    string
+	raw         string
+	body        string
+}
+
+func readParts(t *testing.T, reader *multipart.Reader) []mailPart {
+	t.Helper()
+
+	var parts []mailPart
+	for {
+		// NextRawPart не декодирует quoted-printable сам, так проверяется и заголовок, и кодирование
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		raw, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part body: %v", err)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(raw)))
+		if err != nil {
+			t.Fatalf("decode part body: %v", err)
+		}
+		parts = append(parts, mailPart{
+			contentType: part.Header.Get("Content-Type"),
+			encoding:    part.Header.Get("Content-Transfer-Encoding"),
+			raw:         string(raw),
+			body:        string(body),
+		})
+	}
+}
+
+// sentMail конверт и текст письма, принятого fakeSMTPServer.
+type sentMail struct {
+	from string
+	to   string
+	data string
+}
+
+// fakeSMTPServer минимальный SMTP-сервер без TLS и авторизации.
+type fakeSMTPServer struct {
+	listener   net.Listener
+	mails      chan sentMail
+	rejectRcpt bool
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: listener, mails: make(chan sentMail, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	_ = text.PrintfLine("220 localhost fake SMTP")
+
+	var mail sentMail
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, ":")
+		switch strings.ToUpper(strings.Fields(command + " ")[0]) {
+		case "EHLO", "HELO":
+			_ = text.PrintfLine("250 localhost")
+		case "MAIL":
+			mail.from = strings.Trim(arg, "<> ")
+			_ = text.PrintfLine("250 ok")
+		case "RCPT":
+			if s.rejectRcpt {
+				_ = text.PrintfLine("550 no such user")
+				continue
+			}
+			mail.to = strings.Trim(arg, "<> ")
+			_ = text.PrintfLine("250 ok")
+		case "DATA":
+			_ = text.PrintfLine("354 end with <CRLF>.<CRLF>")
+			data, err := text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = string(data)
+			s.mails <- mail
+			_ = text.PrintfLine("250 queued")
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *fakeSMTPServer) Close() {
+	_ = s.listener.Close()
+}
@@ -0,0 +1,28 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// LogPublisher пишет события в лог; публикатор по умолчанию, пока не подключены внешние.
+type LogPublisher struct {
+	log *slog.Logger
+}
+
+func NewLogPublisher(log *slog.Logger) *LogPublisher {
+	return &LogPublisher{log: log}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	p.log.InfoContext(ctx, "доменное событие",
+		"id", message.ID,
+		"event_type", message.EventType,
+		"aggregate_id", message.AggregateID,
+		"actor", message.Actor,
+		"payload", string(message.Payload),
+	)
+	return nil
+}
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// maxErrorBody сколько тела ответа с ошибкой попадает в текст ошибки
+const maxErrorBody = 1 << 10
+
+// ReviewerPublisher запрашивает ревью через REST API GitHub.
+// Базовый URL настраивается, чтобы ходить в GitHub Enterprise или в локальную заглушку.
+type ReviewerPublisher struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewReviewerPublisher(baseURL, token string, timeout time.Duration) *ReviewerPublisher {
+	return &ReviewerPublisher{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// RequestReviewers POST /repos/{owner}/{repo}/pulls/{number}/requested_reviewers
+func (p *ReviewerPublisher) RequestReviewers(ctx context.Context, repository string, number int, logins []string) error {
+	return p.send(ctx, http.MethodPost, repository, number, logins)
+}
+
+// RemoveReviewRequests DELETE /repos/{owner}/{repo}/pulls/{number}/requested_reviewers
+func (p *ReviewerPublisher) RemoveReviewRequests(ctx context.Context, repository string, number int, logins []string) error {
+	return p.send(ctx, http.MethodDelete, repository, number, logins)
+}
+
+func (p *ReviewerPublisher) send(ctx context.Context, method, repository string, number int, logins []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", p.baseURL, repository, number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("GitHub ответил %d на %s %s: %s", resp.StatusCode, method, url, bytes.TrimSpace(message))
+	if retryable(resp) {
+		return err
+	}
+	// 422 - логин не соавтор репозитория или автор PR, 404 - нет PR или доступа
+	return fmt.Errorf("%w: %w", domain.ErrReviewRequestRejected, err)
+}
+
+// retryable ошибки сервера, лимиты запросов и таймауты имеет смысл повторить
+func retryable(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode >= 500,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusRequestTimeout:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
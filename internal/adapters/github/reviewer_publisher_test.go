@@ -0,0 +1,142 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+func TestReviewerPublisher_Send(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("requests and removes reviewers", func(t *testing.T) {
+		t.Parallel()
+
+		type received struct {
+			method, path, auth, accept, version string
+			reviewers                           []string
+		}
+		requests := make(chan received, 2)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Reviewers []string `json:"reviewers"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			requests <- received{
+				method:    r.Method,
+				path:      r.URL.Path,
+				auth:      r.Header.Get("Authorization"),
+				accept:    r.Header.Get("Accept"),
+				version:   r.Header.Get("X-GitHub-Api-Version"),
+				reviewers: body.Reviewers,
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		// завершающий слэш в базовом URL не даёт двойного слэша в пути
+		publisher := NewReviewerPublisher(server.URL+"/", "token", time.Second)
+		if err := publisher.RequestReviewers(ctx, "acme/api", 42, []string{"alice", "bob"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := publisher.RemoveReviewRequests(ctx, "acme/api", 42, []string{"carol"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, want := range []received{
+			{method: http.MethodPost, reviewers: []string{"alice", "bob"}},
+			{method: http.MethodDelete, reviewers: []string{"carol"}},
+		} {
+			got := <-requests
+			if got.method != want.method || !slices.Equal(got.reviewers, want.reviewers) {
+				t.Fatalf("expected %s %v, got %s %v", want.method, want.reviewers, got.method, got.reviewers)
+			}
+			if got.path != "/repos/acme/api/pulls/42/requested_reviewers" {
+				t.Fatalf("unexpected path %q", got.path)
+			}
+			if got.auth != "Bearer token" || got.accept != "application/vnd.github+json" || got.version == "" {
+				t.Fatalf("unexpected headers: auth %q, accept %q, version %q", got.auth, got.accept, got.version)
+			}
+		}
+	})
+
+	tests := []struct {
+		name         string
+		status       int
+		header       map[string]string
+		wantErr      bool
+		wantRejected bool
+	}{
+		{name: "201 is success", status: http.StatusCreated},
+		{name: "200 is success", status: http.StatusOK},
+		{name: "500 is retryable", status: http.StatusInternalServerError, wantErr: true},
+		{name: "502 is retryable", status: http.StatusBadGateway, wantErr: true},
+		{name: "503 is retryable", status: http.StatusServiceUnavailable, wantErr: true},
+		{name: "429 is retryable", status: http.StatusTooManyRequests, wantErr: true},
+		{name: "408 is retryable", status: http.StatusRequestTimeout, wantErr: true},
+		{
+			name:    "403 with exhausted rate limit is retryable",
+			status:  http.StatusForbidden,
+			header:  map[string]string{"X-RateLimit-Remaining": "0"},
+			wantErr: true,
+		},
+		{
+			name:         "403 with rate limit left is rejected",
+			status:       http.StatusForbidden,
+			header:       map[string]string{"X-RateLimit-Remaining": "12"},
+			wantErr:      true,
+			wantRejected: true,
+		},
+		{name: "403 without rate limit header is rejected", status: http.StatusForbidden, wantErr: true, wantRejected: true},
+		{name: "401 is rejected", status: http.StatusUnauthorized, wantErr: true, wantRejected: true},
+		{name: "404 is rejected", status: http.StatusNotFound, wantErr: true, wantRejected: true},
+		{name: "422 is rejected", status: http.StatusUnprocessableEntity, wantErr: true, wantRejected: true},
+		{name: "400 is rejected", status: http.StatusBadRequest, wantErr: true, wantRejected: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for name, value := range tt.header {
+					w.Header().Set(name, value)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"message":"Reviews may only be requested from collaborators."}`))
+			}))
+			defer server.Close()
+
+			err := NewReviewerPublisher(server.URL, "token", time.Second).RequestReviewers(ctx, "acme/api", 42, []string{"alice"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := errors.Is(err, domain.ErrReviewRequestRejected); got != tt.wantRejected {
+				t.Fatalf("expected rejected %v, got %v", tt.wantRejected, err)
+			}
+		})
+	}
+
+	t.Run("unreachable API is retryable", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		err := NewReviewerPublisher(url, "token", time.Second).RequestReviewers(ctx, "acme/api", 42, []string{"alice"})
+		if err == nil || errors.Is(err, domain.ErrReviewRequestRejected) {
+			t.Fatalf("expected retryable connection error, got %v", err)
+		}
+	})
+}
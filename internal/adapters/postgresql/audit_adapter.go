@@ -0,0 +1,107 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type AuditAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewAuditAdapter(db *sqlx.DB, log *slog.Logger) *AuditAdapter {
+	return &AuditAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+type auditEventRow struct {
+	ID         int64     `db:"id"`
+	Actor      string    `db:"actor"`
+	Action     string    `db:"action"`
+	EntityType string    `db:"entity_type"`
+	EntityID   string    `db:"entity_id"`
+	Before     []byte    `db:"before"`
+	After      []byte    `db:"after"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// CreateAuditEvent добавляет запись в журнал; в транзакции use case пишется вместе с изменением.
+func (a *AuditAdapter) CreateAuditEvent(ctx context.Context, event domain.AuditEvent) error {
+	const query = `
+		INSERT INTO audit_events (actor, action, entity_type, entity_id, before, after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		event.Actor, event.Action, event.EntityType, event.EntityID, event.Before, event.After, event.CreatedAt,
+	); err != nil {
+		a.log.ErrorContext(ctx, "ошибка записи события аудита", "action", event.Action, "entity_id", event.EntityID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListAuditEvents возвращает события по фильтру, новые первыми.
+func (a *AuditAdapter) ListAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	conditions := make([]string, 0, 5)
+	args := make([]any, 0, 6)
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.EntityType != "" {
+		where("entity_type = $%d", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		where("entity_id = $%d", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		where("actor = $%d", filter.Actor)
+	}
+	if filter.From != nil {
+		where("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $%d", *filter.To)
+	}
+
+	query := `SELECT id, actor, action, entity_type, entity_id, before, after, created_at FROM audit_events`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args))
+
+	var rows []auditEventRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения журнала аудита", "error", err)
+		return nil, err
+	}
+
+	events := make([]domain.AuditEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, domain.AuditEvent{
+			ID:         row.ID,
+			Actor:      row.Actor,
+			Action:     row.Action,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Before:     row.Before,
+			After:      row.After,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+	return events, nil
+}
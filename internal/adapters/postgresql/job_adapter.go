@@ -0,0 +1,154 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type JobAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewJobAdapter(db *sqlx.DB, log *slog.Logger) *JobAdapter {
+	return &JobAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+const jobColumns = `id, type, actor, status, payload, result, error, done, total, attempts, created_at, started_at, heartbeat_at, finished_at`
+
+type jobRow struct {
+	ID          string     `db:"id"`
+	Type        string     `db:"type"`
+	Actor       string     `db:"actor"`
+	Status      string     `db:"status"`
+	Payload     []byte     `db:"payload"`
+	Result      []byte     `db:"result"`
+	Error       string     `db:"error"`
+	Done        int        `db:"done"`
+	Total       int        `db:"total"`
+	Attempts    int        `db:"attempts"`
+	CreatedAt   time.Time  `db:"created_at"`
+	StartedAt   *time.Time `db:"started_at"`
+	HeartbeatAt *time.Time `db:"heartbeat_at"`
+	FinishedAt  *time.Time `db:"finished_at"`
+}
+
+func (r jobRow) toDomain() domain.Job {
+	return domain.Job{
+		ID:          r.ID,
+		Type:        r.Type,
+		Actor:       r.Actor,
+		Status:      r.Status,
+		Payload:     r.Payload,
+		Result:      r.Result,
+		Error:       r.Error,
+		Done:        r.Done,
+		Total:       r.Total,
+		Attempts:    r.Attempts,
+		CreatedAt:   r.CreatedAt,
+		StartedAt:   r.StartedAt,
+		HeartbeatAt: r.HeartbeatAt,
+		FinishedAt:  r.FinishedAt,
+	}
+}
+
+// CreateJob ставит задачу в очередь.
+func (a *JobAdapter) CreateJob(ctx context.Context, job domain.Job) error {
+	const query = `
+		INSERT INTO jobs (id, type, actor, status, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, job.ID, job.Type, job.Actor, job.Status, job.Payload, job.CreatedAt); err != nil {
+		a.log.ErrorContext(ctx, "ошибка создания задачи", "job_id", job.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetJob получает задачу по идентификатору.
+func (a *JobAdapter) GetJob(ctx context.Context, id string) (domain.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+
+	var row jobRow
+	if err := conn(ctx, a.db).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Job{}, domain.ErrJobNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка получения задачи", "job_id", id, "error", err)
+		return domain.Job{}, err
+	}
+
+	return row.toDomain(), nil
+}
+
+// ClaimJob забирает самую старую ожидающую задачу или RUNNING, чей heartbeat
+// старше staleBefore (обработчик упал или сервис перезапустили).
+// SKIP LOCKED не даёт двум обработчикам взять одну задачу.
+func (a *JobAdapter) ClaimJob(ctx context.Context, now, staleBefore time.Time) (domain.Job, bool, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'RUNNING',
+		    attempts = attempts + 1,
+		    started_at = COALESCE(started_at, $1),
+		    heartbeat_at = $1
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = 'PENDING' OR (status = 'RUNNING' AND heartbeat_at < $2)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	var row jobRow
+	if err := conn(ctx, a.db).GetContext(ctx, &row, query, now, staleBefore); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Job{}, false, nil
+		}
+		a.log.ErrorContext(ctx, "ошибка захвата задачи", "error", err)
+		return domain.Job{}, false, err
+	}
+
+	return row.toDomain(), true, nil
+}
+
+// UpdateJob сохраняет прогресс и итог задачи. Запись проходит, только пока
+// задачу не перехватили: attempts совпадает с тем, что выдал ClaimJob.
+func (a *JobAdapter) UpdateJob(ctx context.Context, job domain.Job) error {
+	const query = `
+		UPDATE jobs
+		SET status = $3, result = $4, error = $5, done = $6, total = $7, heartbeat_at = $8, finished_at = $9
+		WHERE id = $1 AND attempts = $2
+	`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query,
+		job.ID, job.Attempts, job.Status, job.Result, job.Error, job.Done, job.Total, job.HeartbeatAt, job.FinishedAt,
+	)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка обновления задачи", "job_id", job.ID, "error", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения результата обновления задачи", "job_id", job.ID, "error", err)
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrJobLeaseLost
+	}
+
+	return nil
+}
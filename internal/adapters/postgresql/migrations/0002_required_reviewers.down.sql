@@ -0,0 +1,2 @@
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS is_required;
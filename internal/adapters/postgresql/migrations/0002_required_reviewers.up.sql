@@ -0,0 +1,2 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS is_required BOOLEAN NOT NULL DEFAULT FALSE;
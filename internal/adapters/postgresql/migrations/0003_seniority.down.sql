@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS require_senior_reviewer;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS level;
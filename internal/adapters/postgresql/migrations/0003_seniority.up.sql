@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS level TEXT NOT NULL DEFAULT 'middle';
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS require_senior_reviewer BOOLEAN NOT NULL DEFAULT FALSE;
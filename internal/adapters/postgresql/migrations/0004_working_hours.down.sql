@@ -0,0 +1,4 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS work_end_hour,
+    DROP COLUMN IF EXISTS work_start_hour,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS work_start_hour SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS work_end_hour SMALLINT NOT NULL DEFAULT 24;
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS team_rotations (
+    team_name TEXT PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    last_user_id TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS pull_request_history (
+    id BIGSERIAL PRIMARY KEY,
+    pr_id TEXT NOT NULL,
+    event TEXT NOT NULL,
+    reviewer_id TEXT,
+    previous_reviewer_id TEXT,
+    reason TEXT,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_history_pr ON pull_request_history (pr_id, created_at, id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_identities (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    handle TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    UNIQUE (user_id, provider)
+);
+
+-- логины GitHub и GitLab, как и email, не различают регистр
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_handle ON user_identities (provider, lower(handle));
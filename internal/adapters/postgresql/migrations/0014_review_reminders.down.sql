@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_request_history_reviewer;
+DROP TABLE IF EXISTS review_reminders;
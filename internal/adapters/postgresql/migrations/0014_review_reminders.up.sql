@@ -0,0 +1,11 @@
+-- отправленные напоминания о просроченном ревью; assigned_at в ключе, чтобы
+-- повторное назначение того же ревьюера получило своё напоминание
+CREATE TABLE IF NOT EXISTS review_reminders (
+    pr_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    assigned_at TIMESTAMPTZ NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (pr_id, reviewer_id, assigned_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_history_reviewer ON pull_request_history (pr_id, reviewer_id, created_at);
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS digest_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- отправленные дайджесты; local_date - дата в часовом поясе пользователя
+CREATE TABLE IF NOT EXISTS review_digests (
+    user_id TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    local_date DATE NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, local_date)
+);
@@ -0,0 +1,3 @@
+-- запланированное отсутствие [absent_from, absent_until), например отпуск
+ALTER TABLE users ADD COLUMN IF NOT EXISTS absent_from TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS absent_until TIMESTAMPTZ;
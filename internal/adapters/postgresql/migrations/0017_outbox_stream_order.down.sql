@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_stream;
+ALTER TABLE outbox DROP COLUMN IF EXISTS tx_id;
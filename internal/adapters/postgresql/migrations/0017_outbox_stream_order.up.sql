@@ -0,0 +1,6 @@
+-- транзакция, записавшая сообщение. Поток событий читает outbox в порядке (tx_id, id) и только
+-- сообщения уже завершённых транзакций, поэтому событие, закоммиченное позже сообщения
+-- с большим id, не пропускается
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS tx_id XID8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS idx_outbox_stream ON outbox (tx_id, id);
@@ -0,0 +1,7 @@
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS tx_id XID8 NOT NULL DEFAULT pg_current_xact_id();
+CREATE INDEX IF NOT EXISTS idx_outbox_stream ON outbox (tx_id, id);
+
+DROP INDEX IF EXISTS idx_outbox_unsequenced;
+DROP INDEX IF EXISTS idx_outbox_stream_seq;
+ALTER TABLE outbox DROP COLUMN IF EXISTS stream_seq;
+DROP SEQUENCE IF EXISTS outbox_stream_seq;
@@ -0,0 +1,18 @@
+-- номер сообщения в потоке событий. Выдаётся после коммита, по одному назначающему за раз
+-- (advisory lock), поэтому номера становятся видны строго по возрастанию, и долгая транзакция
+-- задерживает только свои события. Порядок по tx_id из 0017 ждал самую старую открытую транзакцию в БД
+CREATE SEQUENCE IF NOT EXISTS outbox_stream_seq;
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS stream_seq BIGINT;
+
+-- уже записанные сообщения получают номера в прежнем порядке потока
+UPDATE outbox SET stream_seq = ordered.seq
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY tx_id, id) AS seq FROM outbox) ordered
+WHERE outbox.id = ordered.id;
+SELECT setval('outbox_stream_seq', COALESCE((SELECT MAX(stream_seq) FROM outbox), 0) + 1, false);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_stream_seq ON outbox (stream_seq) WHERE stream_seq IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_unsequenced ON outbox (id) WHERE stream_seq IS NULL;
+
+DROP INDEX IF EXISTS idx_outbox_stream;
+ALTER TABLE outbox DROP COLUMN IF EXISTS tx_id;
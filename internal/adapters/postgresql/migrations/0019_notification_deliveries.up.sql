@@ -0,0 +1,9 @@
+-- доставленные уведомления о назначениях: по сообщению outbox, получателю и каналу.
+-- Повтор сообщения досылает только недоставленное
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    message_id BIGINT NOT NULL,
+    user_id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    delivered_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (message_id, user_id, provider)
+);
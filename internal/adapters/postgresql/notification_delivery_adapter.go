@@ -0,0 +1,60 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type NotificationDeliveryAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewNotificationDeliveryAdapter(db *sqlx.DB, log *slog.Logger) *NotificationDeliveryAdapter {
+	return &NotificationDeliveryAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// ListNotificationDeliveries уведомления, уже доставленные по сообщению outbox.
+func (a *NotificationDeliveryAdapter) ListNotificationDeliveries(ctx context.Context, messageID int64) ([]domain.NotificationDelivery, error) {
+	const query = `
+		SELECT message_id, user_id, provider, delivered_at
+		FROM notification_deliveries
+		WHERE message_id = $1
+	`
+
+	var deliveries []domain.NotificationDelivery
+	if err := conn(ctx, a.db).SelectContext(ctx, &deliveries, query, messageID); err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения доставленных уведомлений", "message_id", messageID, "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// CreateNotificationDelivery запоминает доставленное уведомление; повторная отметка ничего не меняет.
+func (a *NotificationDeliveryAdapter) CreateNotificationDelivery(ctx context.Context, delivery domain.NotificationDelivery) error {
+	const query = `
+		INSERT INTO notification_deliveries (message_id, user_id, provider, delivered_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		delivery.MessageID, delivery.UserID, delivery.Provider, delivery.DeliveredAt,
+	); err != nil {
+		a.log.ErrorContext(ctx, "ошибка сохранения доставки уведомления",
+			"message_id", delivery.MessageID,
+			"user_id", delivery.UserID,
+			"provider", delivery.Provider,
+			"error", err,
+		)
+		return err
+	}
+	return nil
+}
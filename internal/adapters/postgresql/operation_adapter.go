@@ -0,0 +1,127 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type OperationAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewOperationAdapter(db *sqlx.DB, log *slog.Logger) *OperationAdapter {
+	return &OperationAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// operationPayload исходное состояние операции, хранится в JSONB.
+type operationPayload struct {
+	UserIDs      []string                     `json:"user_ids"`
+	PullRequests []domain.PullRequestSnapshot `json:"pull_requests"`
+}
+
+type operationRow struct {
+	ID         string     `db:"id"`
+	Type       string     `db:"type"`
+	TeamName   string     `db:"team_name"`
+	Status     string     `db:"status"`
+	Payload    []byte     `db:"payload"`
+	CreatedAt  time.Time  `db:"created_at"`
+	RevertedAt *time.Time `db:"reverted_at"`
+}
+
+// CreateOperation сохраняет операцию.
+func (a *OperationAdapter) CreateOperation(ctx context.Context, operation domain.Operation) error {
+	const query = `
+		INSERT INTO operations (id, type, team_name, status, payload, created_at, reverted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	payload, err := json.Marshal(operationPayload{UserIDs: operation.UserIDs, PullRequests: operation.PullRequests})
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		operation.ID, operation.Type, operation.TeamName, operation.Status, payload, operation.CreatedAt, operation.RevertedAt,
+	); err != nil {
+		a.log.ErrorContext(ctx, "ошибка создания операции", "operation_id", operation.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// LockOperation получает операцию и блокирует её строку до фиксации транзакции use case,
+// поэтому параллельные откаты одной операции выполняются по очереди и второй видит статус REVERTED.
+func (a *OperationAdapter) LockOperation(ctx context.Context, id string) (domain.Operation, error) {
+	const query = `
+		SELECT id, type, team_name, status, payload, created_at, reverted_at
+		FROM operations
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var row operationRow
+	if err := conn(ctx, a.db).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Operation{}, domain.ErrOperationNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка получения операции", "operation_id", id, "error", err)
+		return domain.Operation{}, err
+	}
+
+	var payload operationPayload
+	if err := json.Unmarshal(row.Payload, &payload); err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения состояния операции", "operation_id", id, "error", err)
+		return domain.Operation{}, err
+	}
+
+	return domain.Operation{
+		ID:           row.ID,
+		Type:         row.Type,
+		TeamName:     row.TeamName,
+		Status:       row.Status,
+		UserIDs:      payload.UserIDs,
+		PullRequests: payload.PullRequests,
+		CreatedAt:    row.CreatedAt,
+		RevertedAt:   row.RevertedAt,
+	}, nil
+}
+
+// UpdateOperation обновляет статус операции.
+func (a *OperationAdapter) UpdateOperation(ctx context.Context, operation domain.Operation) error {
+	const query = `
+		UPDATE operations
+		SET status = $2, reverted_at = $3
+		WHERE id = $1
+	`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, operation.ID, operation.Status, operation.RevertedAt)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка обновления операции", "operation_id", operation.ID, "error", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения результата обновления операции", "operation_id", operation.ID, "error", err)
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrOperationNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type OutboxAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewOutboxAdapter(db *sqlx.DB, log *slog.Logger) *OutboxAdapter {
+	return &OutboxAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+const outboxColumns = `id, event_type, aggregate_id, payload, actor, created_at, attempts, next_attempt_at, last_error, delivered_at`
+
+type outboxRow struct {
+	ID            int64          `db:"id"`
+	EventType     string         `db:"event_type"`
+	AggregateID   string         `db:"aggregate_id"`
+	Payload       []byte         `db:"payload"`
+	Actor         string         `db:"actor"`
+	CreatedAt     time.Time      `db:"created_at"`
+	Attempts      int            `db:"attempts"`
+	NextAttemptAt time.Time      `db:"next_attempt_at"`
+	LastError     sql.NullString `db:"last_error"`
+	DeliveredAt   *time.Time     `db:"delivered_at"`
+}
+
+func (r outboxRow) toDomain() domain.OutboxMessage {
+	return domain.OutboxMessage{
+		ID:            r.ID,
+		EventType:     r.EventType,
+		AggregateID:   r.AggregateID,
+		Payload:       r.Payload,
+		Actor:         r.Actor,
+		CreatedAt:     r.CreatedAt,
+		Attempts:      r.Attempts,
+		NextAttemptAt: r.NextAttemptAt,
+		LastError:     r.LastError.String,
+		DeliveredAt:   r.DeliveredAt,
+	}
+}
+
+// AddOutboxMessages пишет события в outbox; в транзакции use case - вместе с изменением.
+func (a *OutboxAdapter) AddOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error {
+	const query = `
+		INSERT INTO outbox (event_type, aggregate_id, payload, actor, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	for _, message := range messages {
+		if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+			message.EventType, message.AggregateID, message.Payload, message.Actor, message.CreatedAt, message.NextAttemptAt,
+		); err != nil {
+			a.log.ErrorContext(ctx, "ошибка записи события в outbox", "event_type", message.EventType, "aggregate_id", message.AggregateID, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimOutboxMessages забирает до limit недоставленных сообщений, чья очередь подошла к now,
+// и откладывает их до leaseUntil: если релей упадёт посреди доставки, сообщения заберут снова.
+// SKIP LOCKED не даёт двум релеям взять одно сообщение.
+func (a *OutboxAdapter) ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error) {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1,
+		    next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE delivered_at IS NULL AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+
+	var rows []outboxRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, now, leaseUntil, limit); err != nil {
+		a.log.ErrorContext(ctx, "ошибка захвата сообщений outbox", "error", err)
+		return nil, err
+	}
+
+	messages := make([]domain.OutboxMessage, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, row.toDomain())
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].ID < messages[j].ID
+	})
+	return messages, nil
+}
+
+// MarkOutboxDelivered отмечает сообщение доставленным.
+func (a *OutboxAdapter) MarkOutboxDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	const query = `UPDATE outbox SET delivered_at = $2, last_error = NULL WHERE id = $1`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, id, deliveredAt); err != nil {
+		a.log.ErrorContext(ctx, "ошибка отметки доставки outbox", "id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// MarkOutboxFailed сохраняет ошибку доставки и время следующей попытки.
+func (a *OutboxAdapter) MarkOutboxFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	const query = `UPDATE outbox SET next_attempt_at = $2, last_error = $3 WHERE id = $1`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, id, nextAttemptAt, lastError); err != nil {
+		a.log.ErrorContext(ctx, "ошибка отметки неудачной доставки outbox", "id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// outboxStreamLock ключ advisory lock, под которым сообщениям выдаются номера потока
+const outboxStreamLock = 7310041
+
+// SequenceOutboxMessages выдаёт номера потока (stream_seq) до limit закоммиченным сообщениям без номера.
+// Назначающие идут по одному под advisory lock, и следующий начинает только после коммита предыдущего,
+// поэтому номера становятся видны по возрастанию: читатель по stream_seq ничего не пропускает.
+func (a *OutboxAdapter) SequenceOutboxMessages(ctx context.Context, limit int) (int, error) {
+	const query = `
+		UPDATE outbox SET stream_seq = nextval('outbox_stream_seq')
+		WHERE id IN (SELECT id FROM outbox WHERE stream_seq IS NULL ORDER BY id LIMIT $1)
+	`
+
+	var sequenced int64
+	err := withTx(ctx, a.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, a.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, outboxStreamLock); err != nil {
+			return err
+		}
+		result, err := conn(ctx, a.db).ExecContext(ctx, query, limit)
+		if err != nil {
+			return err
+		}
+		sequenced, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка нумерации журнала событий", "error", err)
+		return 0, err
+	}
+	return int(sequenced), nil
+}
+
+// ListOutboxMessagesAfter до limit сообщений после afterID и не дальше untilID в порядке потока (stream_seq),
+// доставленные и нет: outbox служит и журналом событий. Сообщения без номера ещё не в потоке.
+// 0 в afterID - с начала журнала, в untilID - без верхней границы.
+// Если afterID нет в потоке, возвращает domain.ErrEventNotFound.
+func (a *OutboxAdapter) ListOutboxMessagesAfter(ctx context.Context, afterID, untilID int64, limit int) ([]domain.OutboxMessage, error) {
+	query := `
+		SELECT ` + outboxColumns + ` FROM outbox
+		WHERE stream_seq > $1 AND ($2 = 0 OR stream_seq <= $2)
+		ORDER BY stream_seq
+		LIMIT $3
+	`
+
+	after, err := a.streamPosition(ctx, afterID)
+	if err != nil {
+		return nil, err
+	}
+	until, err := a.streamPosition(ctx, untilID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []outboxRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, after, until, limit); err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения журнала событий", "after_id", afterID, "error", err)
+		return nil, err
+	}
+
+	messages := make([]domain.OutboxMessage, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, row.toDomain())
+	}
+	return messages, nil
+}
+
+// streamPosition номер потока сообщения id; для 0 - 0.
+func (a *OutboxAdapter) streamPosition(ctx context.Context, id int64) (int64, error) {
+	if id == 0 {
+		return 0, nil
+	}
+
+	const query = `SELECT stream_seq FROM outbox WHERE id = $1 AND stream_seq IS NOT NULL`
+
+	var seq int64
+	if err := conn(ctx, a.db).GetContext(ctx, &seq, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrEventNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка получения позиции события", "id", id, "error", err)
+		return 0, err
+	}
+	return seq, nil
+}
+
+// LastOutboxMessageID id последнего сообщения в порядке потока; 0, если в потоке ещё ничего нет.
+func (a *OutboxAdapter) LastOutboxMessageID(ctx context.Context) (int64, error) {
+	const query = `SELECT id FROM outbox WHERE stream_seq IS NOT NULL ORDER BY stream_seq DESC LIMIT 1`
+
+	var id int64
+	if err := conn(ctx, a.db).GetContext(ctx, &id, query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		a.log.ErrorContext(ctx, "ошибка получения последнего события", "error", err)
+		return 0, err
+	}
+	return id, nil
+}
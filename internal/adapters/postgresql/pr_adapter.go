@@ -29,12 +29,12 @@ func (a *PullRequestAdapter) CreatePullRequest(ctx context.Context, pr domain.Pu
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	if _, err := a.db.ExecContext(ctx, query, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status, pr.CreatedAt, pr.MergedAt); err != nil {
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status, pr.CreatedAt, pr.MergedAt); err != nil {
 		a.log.ErrorContext(ctx, "ошибка создания pull request", "pr_id", pr.ID, "error", err)
 		return err
 	}
 
-	if err := a.replaceReviewers(ctx, pr.ID, pr.Reviewers); err != nil {
+	if err := a.replaceReviewers(ctx, pr); err != nil {
 		return err
 	}
 
@@ -49,7 +49,7 @@ func (a *PullRequestAdapter) ListPullRequests(ctx context.Context) ([]domain.Pul
 		ORDER BY created_at DESC
 	`
 
-	rows, err := a.db.QueryxContext(ctx, query)
+	rows, err := conn(ctx, a.db).QueryxContext(ctx, query)
 	if err != nil {
 		a.log.ErrorContext(ctx, "ошибка получения списка pull request", "error", err)
 		return nil, err
@@ -64,15 +64,13 @@ func (a *PullRequestAdapter) ListPullRequests(ctx context.Context) ([]domain.Pul
 			return nil, err
 		}
 
-		reviewers, err := a.loadReviewers(ctx, pr.ID)
-		if err != nil {
-			return nil, err
-		}
-		pr.Reviewers = reviewers
-
 		result = append(result, pr)
 	}
+	_ = rows.Close()
 
+	if err := a.loadAllReviewers(ctx, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
@@ -84,7 +82,7 @@ func (a *PullRequestAdapter) GetPullRequest(ctx context.Context, id string) (dom
 		WHERE id = $1
 	`
 
-	row := a.db.QueryRowxContext(ctx, query, id)
+	row := conn(ctx, a.db).QueryRowxContext(ctx, query, id)
 	pr, err := scanPullRequest(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -94,11 +92,9 @@ func (a *PullRequestAdapter) GetPullRequest(ctx context.Context, id string) (dom
 		return domain.PullRequest{}, err
 	}
 
-	reviewers, err := a.loadReviewers(ctx, id)
-	if err != nil {
+	if err := a.loadReviewers(ctx, &pr); err != nil {
 		return domain.PullRequest{}, err
 	}
-	pr.Reviewers = reviewers
 
 	return pr, nil
 }
@@ -116,7 +112,7 @@ func (a *PullRequestAdapter) UpdatePullRequest(ctx context.Context, pr domain.Pu
 		WHERE id = $1
 	`
 
-	result, err := a.db.ExecContext(ctx, query, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status, pr.CreatedAt, pr.MergedAt)
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, pr.ID, pr.Title, pr.AuthorID, pr.TeamName, pr.Status, pr.CreatedAt, pr.MergedAt)
 	if err != nil {
 		a.log.ErrorContext(ctx, "ошибка обновления pull request", "pr_id", pr.ID, "error", err)
 		return err
@@ -131,7 +127,7 @@ func (a *PullRequestAdapter) UpdatePullRequest(ctx context.Context, pr domain.Pu
 		return domain.ErrPullRequestNotFound
 	}
 
-	if err := a.replaceReviewers(ctx, pr.ID, pr.Reviewers); err != nil {
+	if err := a.replaceReviewers(ctx, pr); err != nil {
 		return err
 	}
 
@@ -148,7 +144,7 @@ func (a *PullRequestAdapter) ListPullRequestsByReviewer(ctx context.Context, rev
 		ORDER BY pr.created_at DESC
 	`
 
-	rows, err := a.db.QueryxContext(ctx, query, reviewerID)
+	rows, err := conn(ctx, a.db).QueryxContext(ctx, query, reviewerID)
 	if err != nil {
 		a.log.ErrorContext(ctx, "ошибка получения pull request по ревьюеру", "reviewer_id", reviewerID, "error", err)
 		return nil, err
@@ -163,69 +159,157 @@ func (a *PullRequestAdapter) ListPullRequestsByReviewer(ctx context.Context, rev
 			return nil, err
 		}
 
-		reviewers, err := a.loadReviewers(ctx, pr.ID)
+		result = append(result, pr)
+	}
+	_ = rows.Close()
+
+	if err := a.loadAllReviewers(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListOpenPullRequestsByTeam возвращает открытые pull request команды, старые первыми.
+func (a *PullRequestAdapter) ListOpenPullRequestsByTeam(ctx context.Context, teamName string) ([]domain.PullRequest, error) {
+	const query = `
+		SELECT id, title, author_id, team_name, status, created_at, merged_at
+		FROM pull_requests
+		WHERE team_name = $1 AND status = $2
+		ORDER BY created_at
+	`
+
+	rows, err := conn(ctx, a.db).QueryxContext(ctx, query, teamName, domain.PRStatusOpen)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения открытых pull request команды", "team_name", teamName, "error", err)
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []domain.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			a.log.ErrorContext(ctx, "ошибка чтения pull request команды", "team_name", teamName, "error", err)
+			return nil, err
+		}
+
+		result = append(result, pr)
+	}
+	_ = rows.Close()
+
+	if err := a.loadAllReviewers(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListRecentPullRequestsByAuthor возвращает последние limit pull request автора.
+func (a *PullRequestAdapter) ListRecentPullRequestsByAuthor(ctx context.Context, authorID string, limit int) ([]domain.PullRequest, error) {
+	const query = `
+		SELECT id, title, author_id, team_name, status, created_at, merged_at
+		FROM pull_requests
+		WHERE author_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := conn(ctx, a.db).QueryxContext(ctx, query, authorID, limit)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения pull request автора", "author_id", authorID, "error", err)
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []domain.PullRequest
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
 		if err != nil {
+			a.log.ErrorContext(ctx, "ошибка чтения pull request автора", "author_id", authorID, "error", err)
 			return nil, err
 		}
-		pr.Reviewers = reviewers
 
 		result = append(result, pr)
 	}
+	_ = rows.Close()
 
+	if err := a.loadAllReviewers(ctx, result); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-func (a *PullRequestAdapter) loadReviewers(ctx context.Context, prID string) ([]string, error) {
+// loadAllReviewers загружает ревьюеров списка PR. Вызывать после закрытия rows:
+// в транзакции все запросы идут через одно соединение.
+func (a *PullRequestAdapter) loadAllReviewers(ctx context.Context, prs []domain.PullRequest) error {
+	for i := range prs {
+		if err := a.loadReviewers(ctx, &prs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadReviewers загружает ревьюеров pull request, обязательные идут первыми.
+func (a *PullRequestAdapter) loadReviewers(ctx context.Context, pr *domain.PullRequest) error {
 	const query = `
-		SELECT reviewer_id
+		SELECT reviewer_id, is_required
 		FROM pull_request_reviewers
 		WHERE pr_id = $1
-		ORDER BY reviewer_id
+		ORDER BY is_required DESC, reviewer_id
 	`
 
-	rows, err := a.db.QueryxContext(ctx, query, prID)
+	rows, err := conn(ctx, a.db).QueryxContext(ctx, query, pr.ID)
 	if err != nil {
-		a.log.ErrorContext(ctx, "ошибка получения ревьюеров pull request", "pr_id", prID, "error", err)
-		return nil, err
+		a.log.ErrorContext(ctx, "ошибка получения ревьюеров pull request", "pr_id", pr.ID, "error", err)
+		return err
 	}
 	defer func() { _ = rows.Close() }()
 
 	reviewers := make([]string, 0, 2)
+	var required []string
 	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
-			a.log.ErrorContext(ctx, "ошибка чтения ревьюера", "pr_id", prID, "error", err)
-			return nil, err
+		var (
+			reviewerID string
+			isRequired bool
+		)
+		if err := rows.Scan(&reviewerID, &isRequired); err != nil {
+			a.log.ErrorContext(ctx, "ошибка чтения ревьюера", "pr_id", pr.ID, "error", err)
+			return err
 		}
 		reviewers = append(reviewers, reviewerID)
+		if isRequired {
+			required = append(required, reviewerID)
+		}
 	}
 
-	return reviewers, nil
+	pr.Reviewers = reviewers
+	pr.RequiredReviewers = required
+	return nil
 }
 
-func (a *PullRequestAdapter) replaceReviewers(ctx context.Context, prID string, reviewers []string) error {
+func (a *PullRequestAdapter) replaceReviewers(ctx context.Context, pr domain.PullRequest) error {
 	const deleteQuery = `
 		DELETE FROM pull_request_reviewers
 		WHERE pr_id = $1
 	`
 
-	if _, err := a.db.ExecContext(ctx, deleteQuery, prID); err != nil {
-		a.log.ErrorContext(ctx, "ошибка очистки ревьюеров pull request", "pr_id", prID, "error", err)
+	if _, err := conn(ctx, a.db).ExecContext(ctx, deleteQuery, pr.ID); err != nil {
+		a.log.ErrorContext(ctx, "ошибка очистки ревьюеров pull request", "pr_id", pr.ID, "error", err)
 		return err
 	}
 
-	if len(reviewers) == 0 {
+	if len(pr.Reviewers) == 0 {
 		return nil
 	}
 
 	const insertQuery = `
-		INSERT INTO pull_request_reviewers (pr_id, reviewer_id)
-		VALUES ($1, $2)
+		INSERT INTO pull_request_reviewers (pr_id, reviewer_id, is_required)
+		VALUES ($1, $2, $3)
 	`
 
-	for _, reviewerID := range reviewers {
-		if _, err := a.db.ExecContext(ctx, insertQuery, prID, reviewerID); err != nil {
-			a.log.ErrorContext(ctx, "ошибка сохранения ревьюера pull request", "pr_id", prID, "reviewer_id", reviewerID, "error", err)
+	for _, reviewerID := range pr.Reviewers {
+		if _, err := conn(ctx, a.db).ExecContext(ctx, insertQuery, pr.ID, reviewerID, pr.IsRequiredReviewer(reviewerID)); err != nil {
+			a.log.ErrorContext(ctx, "ошибка сохранения ревьюера pull request", "pr_id", pr.ID, "reviewer_id", reviewerID, "error", err)
 			return err
 		}
 	}
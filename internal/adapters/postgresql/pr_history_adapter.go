@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type PullRequestHistoryAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewPullRequestHistoryAdapter(db *sqlx.DB, log *slog.Logger) *PullRequestHistoryAdapter {
+	return &PullRequestHistoryAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+type pullRequestHistoryRow struct {
+	ID                 int64          `db:"id"`
+	PullRequestID      string         `db:"pr_id"`
+	Event              string         `db:"event"`
+	ReviewerID         sql.NullString `db:"reviewer_id"`
+	PreviousReviewerID sql.NullString `db:"previous_reviewer_id"`
+	Reason             sql.NullString `db:"reason"`
+	Actor              string         `db:"actor"`
+	CreatedAt          time.Time      `db:"created_at"`
+}
+
+// AddPullRequestHistory дописывает события в историю PR в переданном порядке.
+func (a *PullRequestHistoryAdapter) AddPullRequestHistory(ctx context.Context, entries []domain.PullRequestHistoryEntry) error {
+	const query = `
+		INSERT INTO pull_request_history (pr_id, event, reviewer_id, previous_reviewer_id, reason, actor, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	for _, entry := range entries {
+		if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+			entry.PullRequestID,
+			entry.Event,
+			nullString(entry.ReviewerID),
+			nullString(entry.PreviousReviewerID),
+			nullString(entry.Reason),
+			entry.Actor,
+			entry.CreatedAt,
+		); err != nil {
+			a.log.ErrorContext(ctx, "ошибка записи истории PR", "pr_id", entry.PullRequestID, "event", entry.Event, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListPullRequestHistory возвращает историю PR от старых событий к новым.
+func (a *PullRequestHistoryAdapter) ListPullRequestHistory(ctx context.Context, prID string) ([]domain.PullRequestHistoryEntry, error) {
+	const query = `
+		SELECT id, pr_id, event, reviewer_id, previous_reviewer_id, reason, actor, created_at
+		FROM pull_request_history
+		WHERE pr_id = $1
+		ORDER BY created_at, id
+	`
+
+	var rows []pullRequestHistoryRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, prID); err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения истории PR", "pr_id", prID, "error", err)
+		return nil, err
+	}
+
+	entries := make([]domain.PullRequestHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, domain.PullRequestHistoryEntry{
+			ID:                 row.ID,
+			PullRequestID:      row.PullRequestID,
+			Event:              row.Event,
+			ReviewerID:         row.ReviewerID.String,
+			PreviousReviewerID: row.PreviousReviewerID.String,
+			Reason:             row.Reason.String,
+			Actor:              row.Actor,
+			CreatedAt:          row.CreatedAt,
+		})
+	}
+	return entries, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,42 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ReviewDigestAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewReviewDigestAdapter(db *sqlx.DB, log *slog.Logger) *ReviewDigestAdapter {
+	return &ReviewDigestAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// MarkDigestSent запоминает дайджест пользователя за локальную дату localDate;
+// false - за эту дату он уже отправлен.
+func (a *ReviewDigestAdapter) MarkDigestSent(ctx context.Context, userID string, localDate, sentAt time.Time) (bool, error) {
+	const query = `
+		INSERT INTO review_digests (user_id, local_date, sent_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, userID, localDate.Format(time.DateOnly), sentAt)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка сохранения дайджеста", "user_id", userID, "error", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type ReviewReminderAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewReviewReminderAdapter(db *sqlx.DB, log *slog.Logger) *ReviewReminderAdapter {
+	return &ReviewReminderAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// ListOverdueReviews назначения на открытые PR, сделанные раньше assignedBefore, без отправленного напоминания.
+// Время назначения берётся из истории PR, а для PR без истории - время создания PR.
+func (a *ReviewReminderAdapter) ListOverdueReviews(ctx context.Context, assignedBefore time.Time, limit int) ([]domain.OverdueReview, error) {
+	const query = `
+		WITH assignments AS (
+			SELECT pr.id AS pr_id, pr.title, pr.author_id, r.reviewer_id,
+				COALESCE((
+					SELECT max(h.created_at)
+					FROM pull_request_history h
+					WHERE h.pr_id = r.pr_id AND h.reviewer_id = r.reviewer_id
+				), pr.created_at) AS assigned_at
+			FROM pull_request_reviewers r
+			JOIN pull_requests pr ON pr.id = r.pr_id
+			WHERE pr.status = $1
+		)
+		SELECT a.pr_id, a.title, a.author_id, a.reviewer_id, a.assigned_at
+		FROM assignments a
+		WHERE a.assigned_at < $2
+			AND NOT EXISTS (
+				SELECT 1 FROM review_reminders m
+				WHERE m.pr_id = a.pr_id AND m.reviewer_id = a.reviewer_id AND m.assigned_at = a.assigned_at
+			)
+		ORDER BY a.assigned_at, a.pr_id, a.reviewer_id
+		LIMIT $3
+	`
+
+	var reviews []domain.OverdueReview
+	if err := conn(ctx, a.db).SelectContext(ctx, &reviews, query, domain.PRStatusOpen, assignedBefore, limit); err != nil {
+		a.log.ErrorContext(ctx, "ошибка поиска просроченных ревью", "error", err)
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// MarkReviewReminded запоминает напоминание; false - его уже отправил другой экземпляр сервиса.
+func (a *ReviewReminderAdapter) MarkReviewReminded(ctx context.Context, review domain.OverdueReview, sentAt time.Time) (bool, error) {
+	const query = `
+		INSERT INTO review_reminders (pr_id, reviewer_id, assigned_at, sent_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING
+	`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, review.PullRequestID, review.ReviewerID, review.AssignedAt, sentAt)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка сохранения напоминания", "pr_id", review.PullRequestID, "reviewer_id", review.ReviewerID, "error", err)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -0,0 +1,87 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RotationAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewRotationAdapter(db *sqlx.DB, log *slog.Logger) *RotationAdapter {
+	return &RotationAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// GetRotation возвращает последнего назначенного в ротации команды.
+func (a *RotationAdapter) GetRotation(ctx context.Context, teamName string) (string, error) {
+	const query = `
+		SELECT last_user_id
+		FROM team_rotations
+		WHERE team_name = $1
+	`
+
+	var lastUserID string
+	if err := conn(ctx, a.db).GetContext(ctx, &lastUserID, query, teamName); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		a.log.ErrorContext(ctx, "ошибка чтения курсора ротации", "team_name", teamName, "error", err)
+		return "", err
+	}
+
+	return lastUserID, nil
+}
+
+// LockRotation возвращает последнего назначенного и блокирует курсор команды.
+// Блокировка держится до фиксации транзакции use case, поэтому параллельные
+// экземпляры сервиса выбирают и сдвигают ротацию строго по очереди.
+func (a *RotationAdapter) LockRotation(ctx context.Context, teamName string) (string, error) {
+	const ensureQuery = `
+		INSERT INTO team_rotations (team_name)
+		VALUES ($1)
+		ON CONFLICT (team_name) DO NOTHING
+	`
+	const selectQuery = `
+		SELECT last_user_id
+		FROM team_rotations
+		WHERE team_name = $1
+		FOR UPDATE
+	`
+
+	tx := conn(ctx, a.db)
+	if _, err := tx.ExecContext(ctx, ensureQuery, teamName); err != nil {
+		a.log.ErrorContext(ctx, "ошибка создания курсора ротации", "team_name", teamName, "error", err)
+		return "", err
+	}
+
+	var lastUserID string
+	if err := tx.GetContext(ctx, &lastUserID, selectQuery, teamName); err != nil {
+		a.log.ErrorContext(ctx, "ошибка блокировки курсора ротации", "team_name", teamName, "error", err)
+		return "", err
+	}
+	return lastUserID, nil
+}
+
+// SetRotation сохраняет последнего назначенного в ротации команды.
+func (a *RotationAdapter) SetRotation(ctx context.Context, teamName, lastUserID string) error {
+	const query = `
+		INSERT INTO team_rotations (team_name, last_user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (team_name) DO UPDATE SET last_user_id = EXCLUDED.last_user_id
+	`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, teamName, lastUserID); err != nil {
+		a.log.ErrorContext(ctx, "ошибка сохранения курсора ротации", "team_name", teamName, "error", err)
+		return err
+	}
+	return nil
+}
@@ -25,11 +25,11 @@ func NewTeamAdapter(db *sqlx.DB, log *slog.Logger) *TeamAdapter {
 // CreateTeam сохраняет команду.
 func (a *TeamAdapter) CreateTeam(ctx context.Context, team domain.Team) error {
 	const query = `
-		INSERT INTO teams (name)
-		VALUES ($1)
+		INSERT INTO teams (name, require_senior_reviewer)
+		VALUES ($1, $2)
 	`
 
-	if _, err := a.db.ExecContext(ctx, query, team.Name); err != nil {
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query, team.Name, team.RequireSeniorReviewer); err != nil {
 		a.log.ErrorContext(ctx, "ошибка создания команды", "team_name", team.Name, "error", err)
 		return err
 	}
@@ -37,6 +37,32 @@ func (a *TeamAdapter) CreateTeam(ctx context.Context, team domain.Team) error {
 	return nil
 }
 
+// UpdateTeam обновляет настройки команды.
+func (a *TeamAdapter) UpdateTeam(ctx context.Context, team domain.Team) error {
+	const query = `
+		UPDATE teams
+		SET require_senior_reviewer = $2
+		WHERE name = $1
+	`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, team.Name, team.RequireSeniorReviewer)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка обновления команды", "team_name", team.Name, "error", err)
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка чтения результата обновления команды", "team_name", team.Name, "error", err)
+		return err
+	}
+	if rows == 0 {
+		return domain.ErrTeamNotFound
+	}
+
+	return nil
+}
+
 // ListTeams возвращает список команд.
 func (a *TeamAdapter) ListTeams(ctx context.Context) ([]domain.Team, error) {
 	const query = `
@@ -46,7 +72,7 @@ func (a *TeamAdapter) ListTeams(ctx context.Context) ([]domain.Team, error) {
 	`
 
 	var names []string
-	if err := a.db.SelectContext(ctx, &names, query); err != nil {
+	if err := conn(ctx, a.db).SelectContext(ctx, &names, query); err != nil {
 		a.log.ErrorContext(ctx, "ошибка получения списка команд", "error", err)
 		return nil, err
 	}
@@ -66,13 +92,16 @@ func (a *TeamAdapter) ListTeams(ctx context.Context) ([]domain.Team, error) {
 // GetTeam возвращает команду по имени.
 func (a *TeamAdapter) GetTeam(ctx context.Context, name string) (domain.Team, error) {
 	const queryTeam = `
-		SELECT name
+		SELECT name, require_senior_reviewer
 		FROM teams
 		WHERE name = $1
 	`
 
-	var teamName string
-	if err := a.db.GetContext(ctx, &teamName, queryTeam, name); err != nil {
+	var row struct {
+		Name                  string `db:"name"`
+		RequireSeniorReviewer bool   `db:"require_senior_reviewer"`
+	}
+	if err := conn(ctx, a.db).GetContext(ctx, &row, queryTeam, name); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Team{}, domain.ErrTeamNotFound
 		}
@@ -81,17 +110,20 @@ func (a *TeamAdapter) GetTeam(ctx context.Context, name string) (domain.Team, er
 	}
 
 	const queryUsers = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, team_name, is_active, level, timezone, work_start_hour, work_end_hour, review_weight, digest_enabled,
+			absent_from, absent_until
 		FROM users
 		WHERE team_name = $1
 		ORDER BY id
 	`
 
 	var members []domain.User
-	if err := a.db.SelectContext(ctx, &members, queryUsers, teamName); err != nil {
+	if err := conn(ctx, a.db).SelectContext(ctx, &members, queryUsers, row.Name); err != nil {
 		a.log.ErrorContext(ctx, "ошибка получения участников команды", "team_name", name, "error", err)
 		return domain.Team{}, err
 	}
 
-	return domain.NewTeam(teamName, members), nil
+	team := domain.NewTeam(row.Name, members)
+	team.RequireSeniorReviewer = row.RequireSeniorReviewer
+	return team, nil
 }
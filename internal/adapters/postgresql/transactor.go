@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// querier общие методы *sqlx.DB и *sqlx.Tx, которыми пользуются адаптеры.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row
+}
+
+// conn возвращает транзакцию из контекста, а без неё - пул соединений.
+func conn(ctx context.Context, db *sqlx.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// withTx выполняет fn в транзакции. Если в контексте уже есть транзакция,
+// fn выполняется в ней, а фиксирует её внешний вызов.
+func withTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Transactor выполняет use case в одной транзакции: все адаптеры, получившие
+// её контекст, пишут в неё.
+type Transactor struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewTransactor(db *sqlx.DB, log *slog.Logger) *Transactor {
+	return &Transactor{
+		db:  db,
+		log: log,
+	}
+}
+
+// WithinTransaction фиксирует изменения fn, если она вернула nil, иначе откатывает.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, t.db, fn)
+}
@@ -25,11 +25,15 @@ func NewUserAdapter(db *sqlx.DB, log *slog.Logger) *UserAdapter {
 // CreateUser сохраняет нового пользователя.
 func (a *UserAdapter) CreateUser(ctx context.Context, user domain.User) error {
 	const query = `
-		INSERT INTO users (id, name, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (id, name, team_name, is_active, level, timezone, work_start_hour, work_end_hour, review_weight, digest_enabled,
+			absent_from, absent_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
-	if _, err := a.db.ExecContext(ctx, query, user.ID, user.Name, user.TeamName, user.IsActive); err != nil {
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		user.ID, user.Name, user.TeamName, user.IsActive, user.Level, user.Timezone, user.WorkStartHour, user.WorkEndHour, user.ReviewWeight, user.DigestEnabled,
+		user.AbsentFrom, user.AbsentUntil,
+	); err != nil {
 		a.log.ErrorContext(ctx, "ошибка создания пользователя", "user_id", user.ID, "error", err)
 		return err
 	}
@@ -40,13 +44,14 @@ func (a *UserAdapter) CreateUser(ctx context.Context, user domain.User) error {
 // ListUsers возвращает список пользователей.
 func (a *UserAdapter) ListUsers(ctx context.Context) ([]domain.User, error) {
 	const query = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, team_name, is_active, level, timezone, work_start_hour, work_end_hour, review_weight, digest_enabled,
+			absent_from, absent_until
 		FROM users
 		ORDER BY id
 	`
 
 	var users []domain.User
-	if err := a.db.SelectContext(ctx, &users, query); err != nil {
+	if err := conn(ctx, a.db).SelectContext(ctx, &users, query); err != nil {
 		a.log.ErrorContext(ctx, "ошибка получения списка пользователей", "error", err)
 		return nil, err
 	}
@@ -57,13 +62,14 @@ func (a *UserAdapter) ListUsers(ctx context.Context) ([]domain.User, error) {
 // GetUser возвращает пользователя по идентификатору.
 func (a *UserAdapter) GetUser(ctx context.Context, id string) (domain.User, error) {
 	const query = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, team_name, is_active, level, timezone, work_start_hour, work_end_hour, review_weight, digest_enabled,
+			absent_from, absent_until
 		FROM users
 		WHERE id = $1
 	`
 
 	var user domain.User
-	if err := a.db.GetContext(ctx, &user, query, id); err != nil {
+	if err := conn(ctx, a.db).GetContext(ctx, &user, query, id); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.User{}, domain.ErrUserNotFound
 		}
@@ -74,15 +80,43 @@ func (a *UserAdapter) GetUser(ctx context.Context, id string) (domain.User, erro
 	return user, nil
 }
 
+// LockUser возвращает пользователя и блокирует его строку до фиксации транзакции use case,
+// чтобы проверка и изменение отсутствия не разошлись с параллельным изменением.
+func (a *UserAdapter) LockUser(ctx context.Context, id string) (domain.User, error) {
+	const query = `
+		SELECT id, name, team_name, is_active, level, timezone, work_start_hour, work_end_hour, review_weight, digest_enabled,
+			absent_from, absent_until
+		FROM users
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var user domain.User
+	if err := conn(ctx, a.db).GetContext(ctx, &user, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.User{}, domain.ErrUserNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка блокировки пользователя", "user_id", id, "error", err)
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
 // UpdateUser обновляет пользователя.
 func (a *UserAdapter) UpdateUser(ctx context.Context, user domain.User) error {
 	const query = `
 		UPDATE users
-		SET name = $2, team_name = $3, is_active = $4
+		SET name = $2, team_name = $3, is_active = $4, level = $5,
+			timezone = $6, work_start_hour = $7, work_end_hour = $8, review_weight = $9,
+			digest_enabled = $10, absent_from = $11, absent_until = $12
 		WHERE id = $1
 	`
 
-	result, err := a.db.ExecContext(ctx, query, user.ID, user.Name, user.TeamName, user.IsActive)
+	result, err := conn(ctx, a.db).ExecContext(ctx, query,
+		user.ID, user.Name, user.TeamName, user.IsActive, user.Level, user.Timezone, user.WorkStartHour, user.WorkEndHour, user.ReviewWeight, user.DigestEnabled,
+		user.AbsentFrom, user.AbsentUntil,
+	)
 	if err != nil {
 		a.log.ErrorContext(ctx, "ошибка обновления пользователя", "user_id", user.ID, "error", err)
 		return err
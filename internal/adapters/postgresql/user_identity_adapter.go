@@ -0,0 +1,146 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// uniqueViolation код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolation = "23505"
+
+type UserIdentityAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewUserIdentityAdapter(db *sqlx.DB, log *slog.Logger) *UserIdentityAdapter {
+	return &UserIdentityAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+// CreateUserIdentity привязывает учётную запись; занятая возвращает domain.ErrIdentityExists.
+func (a *UserIdentityAdapter) CreateUserIdentity(ctx context.Context, identity domain.UserIdentity) error {
+	const query = `
+		INSERT INTO user_identities (id, user_id, provider, handle, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		identity.ID, identity.UserID, identity.Provider, identity.Handle, identity.CreatedAt,
+	); err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrIdentityExists
+		}
+		a.log.ErrorContext(ctx, "ошибка привязки учётной записи", "user_id", identity.UserID, "provider", identity.Provider, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUserIdentity получает учётную запись по идентификатору.
+func (a *UserIdentityAdapter) GetUserIdentity(ctx context.Context, id string) (domain.UserIdentity, error) {
+	const query = `SELECT id, user_id, provider, handle, created_at FROM user_identities WHERE id = $1`
+
+	var identity domain.UserIdentity
+	if err := conn(ctx, a.db).GetContext(ctx, &identity, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.UserIdentity{}, domain.ErrIdentityNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка получения учётной записи", "identity_id", id, "error", err)
+		return domain.UserIdentity{}, err
+	}
+
+	return identity, nil
+}
+
+// FindUserIdentity ищет учётную запись по логину во внешней системе без учёта регистра.
+func (a *UserIdentityAdapter) FindUserIdentity(ctx context.Context, provider, handle string) (domain.UserIdentity, error) {
+	const query = `
+		SELECT id, user_id, provider, handle, created_at
+		FROM user_identities
+		WHERE provider = $1 AND lower(handle) = lower($2)
+	`
+
+	var identity domain.UserIdentity
+	if err := conn(ctx, a.db).GetContext(ctx, &identity, query, provider, handle); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.UserIdentity{}, domain.ErrIdentityNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка поиска учётной записи", "provider", provider, "error", err)
+		return domain.UserIdentity{}, err
+	}
+
+	return identity, nil
+}
+
+// ListUserIdentities возвращает учётные записи по фильтру.
+func (a *UserIdentityAdapter) ListUserIdentities(ctx context.Context, filter domain.UserIdentityFilter) ([]domain.UserIdentity, error) {
+	const query = `
+		SELECT id, user_id, provider, handle, created_at
+		FROM user_identities
+		WHERE ($1 = '' OR user_id = $1) AND ($2 = '' OR provider = $2)
+		ORDER BY user_id, provider
+	`
+
+	identities := make([]domain.UserIdentity, 0)
+	if err := conn(ctx, a.db).SelectContext(ctx, &identities, query, filter.UserID, filter.Provider); err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения учётных записей", "error", err)
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+// UpdateUserIdentity меняет логин учётной записи.
+func (a *UserIdentityAdapter) UpdateUserIdentity(ctx context.Context, identity domain.UserIdentity) error {
+	const query = `UPDATE user_identities SET handle = $2 WHERE id = $1`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, identity.ID, identity.Handle)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrIdentityExists
+		}
+		a.log.ErrorContext(ctx, "ошибка обновления учётной записи", "identity_id", identity.ID, "error", err)
+		return err
+	}
+	return requireAffected(result, domain.ErrIdentityNotFound)
+}
+
+// DeleteUserIdentity отвязывает учётную запись.
+func (a *UserIdentityAdapter) DeleteUserIdentity(ctx context.Context, id string) error {
+	const query = `DELETE FROM user_identities WHERE id = $1`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, id)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка удаления учётной записи", "identity_id", id, "error", err)
+		return err
+	}
+	return requireAffected(result, domain.ErrIdentityNotFound)
+}
+
+// requireAffected возвращает notFound, если запрос не затронул ни одной строки.
+func requireAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
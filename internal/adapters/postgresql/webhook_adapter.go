@@ -0,0 +1,254 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type WebhookAdapter struct {
+	db  *sqlx.DB
+	log *slog.Logger
+}
+
+func NewWebhookAdapter(db *sqlx.DB, log *slog.Logger) *WebhookAdapter {
+	return &WebhookAdapter{
+		db:  db,
+		log: log,
+	}
+}
+
+type webhookRow struct {
+	ID         string    `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []byte    `db:"event_types"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+func (r webhookRow) toDomain() (domain.WebhookSubscription, error) {
+	var eventTypes []string
+	if err := json.Unmarshal(r.EventTypes, &eventTypes); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return domain.WebhookSubscription{
+		ID:         r.ID,
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: eventTypes,
+		CreatedAt:  r.CreatedAt,
+	}, nil
+}
+
+const webhookDeliveryColumns = `id, subscription_id, message_id, event_type, payload, status, attempts, next_attempt_at, response_status, last_error, created_at, delivered_at`
+
+type webhookDeliveryRow struct {
+	ID             int64          `db:"id"`
+	SubscriptionID string         `db:"subscription_id"`
+	MessageID      int64          `db:"message_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	LastError      sql.NullString `db:"last_error"`
+	CreatedAt      time.Time      `db:"created_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+}
+
+func (r webhookDeliveryRow) toDomain() domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             r.ID,
+		SubscriptionID: r.SubscriptionID,
+		MessageID:      r.MessageID,
+		EventType:      r.EventType,
+		Payload:        r.Payload,
+		Status:         r.Status,
+		Attempts:       r.Attempts,
+		NextAttemptAt:  r.NextAttemptAt,
+		ResponseStatus: int(r.ResponseStatus.Int64),
+		LastError:      r.LastError.String,
+		CreatedAt:      r.CreatedAt,
+		DeliveredAt:    r.DeliveredAt,
+	}
+}
+
+// CreateWebhook сохраняет подписку.
+func (a *WebhookAdapter) CreateWebhook(ctx context.Context, subscription domain.WebhookSubscription) error {
+	const query = `
+		INSERT INTO webhook_subscriptions (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		subscription.ID, subscription.URL, subscription.Secret, eventTypes, subscription.CreatedAt,
+	); err != nil {
+		a.log.ErrorContext(ctx, "ошибка создания подписки на вебхук", "webhook_id", subscription.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetWebhook получает подписку по идентификатору.
+func (a *WebhookAdapter) GetWebhook(ctx context.Context, id string) (domain.WebhookSubscription, error) {
+	const query = `SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions WHERE id = $1`
+
+	var row webhookRow
+	if err := conn(ctx, a.db).GetContext(ctx, &row, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+		}
+		a.log.ErrorContext(ctx, "ошибка получения подписки на вебхук", "webhook_id", id, "error", err)
+		return domain.WebhookSubscription{}, err
+	}
+
+	return row.toDomain()
+}
+
+// ListWebhooks возвращает все подписки в порядке создания.
+func (a *WebhookAdapter) ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	const query = `SELECT id, url, secret, event_types, created_at FROM webhook_subscriptions ORDER BY created_at, id`
+
+	var rows []webhookRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query); err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения подписок на вебхуки", "error", err)
+		return nil, err
+	}
+
+	subscriptions := make([]domain.WebhookSubscription, 0, len(rows))
+	for _, row := range rows {
+		subscription, err := row.toDomain()
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+// DeleteWebhook удаляет подписку вместе с журналом её доставок.
+func (a *WebhookAdapter) DeleteWebhook(ctx context.Context, id string) error {
+	const query = `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	result, err := conn(ctx, a.db).ExecContext(ctx, query, id)
+	if err != nil {
+		a.log.ErrorContext(ctx, "ошибка удаления подписки на вебхук", "webhook_id", id, "error", err)
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// CreateWebhookDeliveries ставит доставки в очередь; уже созданные для того же сообщения пропускаются.
+func (a *WebhookAdapter) CreateWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	const query = `
+		INSERT INTO webhook_deliveries (subscription_id, message_id, event_type, payload, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subscription_id, message_id) DO NOTHING
+	`
+
+	for _, delivery := range deliveries {
+		if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+			delivery.SubscriptionID, delivery.MessageID, delivery.EventType, delivery.Payload, delivery.Status, delivery.NextAttemptAt, delivery.CreatedAt,
+		); err != nil {
+			a.log.ErrorContext(ctx, "ошибка создания доставки вебхука", "webhook_id", delivery.SubscriptionID, "message_id", delivery.MessageID, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ClaimWebhookDeliveries забирает до limit ожидающих доставок, чья очередь подошла к now,
+// и откладывает их до leaseUntil, чтобы после падения их забрали снова.
+func (a *WebhookAdapter) ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1,
+		    next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+
+	var rows []webhookDeliveryRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, now, leaseUntil, limit); err != nil {
+		a.log.ErrorContext(ctx, "ошибка захвата доставок вебхуков", "error", err)
+		return nil, err
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, row.toDomain())
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].ID < deliveries[j].ID
+	})
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery сохраняет итог попытки доставки.
+func (a *WebhookAdapter) UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = $2, next_attempt_at = $3, response_status = $4, last_error = $5, delivered_at = $6
+		WHERE id = $1
+	`
+
+	responseStatus := sql.NullInt64{Int64: int64(delivery.ResponseStatus), Valid: delivery.ResponseStatus != 0}
+	if _, err := conn(ctx, a.db).ExecContext(ctx, query,
+		delivery.ID, delivery.Status, delivery.NextAttemptAt, responseStatus, nullString(delivery.LastError), delivery.DeliveredAt,
+	); err != nil {
+		a.log.ErrorContext(ctx, "ошибка обновления доставки вебхука", "delivery_id", delivery.ID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ListWebhookDeliveries возвращает журнал доставок подписки, новые первыми.
+func (a *WebhookAdapter) ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	var rows []webhookDeliveryRow
+	if err := conn(ctx, a.db).SelectContext(ctx, &rows, query, subscriptionID, limit); err != nil {
+		a.log.ErrorContext(ctx, "ошибка получения доставок вебхука", "webhook_id", subscriptionID, "error", err)
+		return nil, err
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		deliveries = append(deliveries, row.toDomain())
+	}
+	return deliveries, nil
+}
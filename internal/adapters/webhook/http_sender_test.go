@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/signature"
+)
+
+func TestHTTPSender_Send(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	body := []byte(`{"id":1,"type":"user.deactivated"}`)
+
+	t.Run("sends body with signing headers", func(t *testing.T) {
+		t.Parallel()
+
+		type received struct {
+			method string
+			header http.Header
+			body   []byte
+		}
+		requests := make(chan received, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			requests <- received{method: r.Method, header: r.Header.Clone(), body: data}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		headers := map[string]string{
+			"X-Webhook-Event":         "user.deactivated",
+			"X-Webhook-Delivery":      "42",
+			"X-Webhook-Signature-256": signature.Sign("secret", body),
+		}
+		status, err := NewHTTPSender(time.Second).Send(ctx, server.URL, body, headers)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if status != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d", status)
+		}
+
+		got := <-requests
+		if got.method != http.MethodPost || string(got.body) != string(body) {
+			t.Fatalf("unexpected request %s %s", got.method, got.body)
+		}
+		if ct := got.header.Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("expected json content type, got %q", ct)
+		}
+		for name, value := range headers {
+			if got.header.Get(name) != value {
+				t.Fatalf("expected %s %q, got %q", name, value, got.header.Get(name))
+			}
+		}
+		if !signature.Verify("secret", got.body, got.header.Get("X-Webhook-Signature-256")) {
+			t.Fatal("expected signature to match received body")
+		}
+	})
+
+	statuses := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "200 is delivered", status: http.StatusOK},
+		{name: "202 is delivered", status: http.StatusAccepted},
+		{name: "400 is a failure", status: http.StatusBadRequest, wantErr: true},
+		{name: "404 is a failure", status: http.StatusNotFound, wantErr: true},
+		{name: "500 is a failure", status: http.StatusInternalServerError, wantErr: true},
+		{name: "503 is a failure", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+	for _, tt := range statuses {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"error":"whatever"}`))
+			}))
+			defer server.Close()
+
+			status, err := NewHTTPSender(time.Second).Send(ctx, server.URL, body, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if status != tt.status {
+				t.Fatalf("expected status %d, got %d", tt.status, status)
+			}
+		})
+	}
+
+	t.Run("slow receiver times out", func(t *testing.T) {
+		t.Parallel()
+
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		started := time.Now()
+		status, err := NewHTTPSender(50*time.Millisecond).Send(ctx, server.URL, body, nil)
+		if err == nil {
+			t.Fatal("expected timeout error")
+		}
+		if status != 0 {
+			t.Fatalf("expected no status on timeout, got %d", status)
+		}
+		if elapsed := time.Since(started); elapsed > 5*time.Second {
+			t.Fatalf("expected send to give up after timeout, took %s", elapsed)
+		}
+	})
+
+	t.Run("unreachable receiver is a failure", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		status, err := NewHTTPSender(time.Second).Send(ctx, url, body, nil)
+		if err == nil || status != 0 {
+			t.Fatalf("expected connection error without status, got %d, %v", status, err)
+		}
+	})
+}
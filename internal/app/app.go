@@ -6,24 +6,65 @@ import (
 	"log/slog"
 	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	"github.com/che1nov/Pr-reviewer-assignment-service/config"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/chat"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/email"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/events"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/github"
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/postgresql"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/adapters/webhook"
 	httpcontroller "github.com/che1nov/Pr-reviewer-assignment-service/internal/controllers/http"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
 	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/clock"
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/idgen"
 	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/random"
 )
 
+// Стратегии назначения ревьюеров
+const (
+	strategyRandom     = "random"
+	strategyHistory    = "history"
+	strategyWeighted   = "weighted"
+	strategyRoundRobin = "round_robin"
+)
+
+// Чаты для личных уведомлений
+const (
+	chatSlack      = "slack"
+	chatMattermost = "mattermost"
+)
+
+// githubAPITimeout сколько ждать ответа REST API GitHub
+const githubAPITimeout = 10 * time.Second
+
+// notifyTimeout сколько ждать ответа канала уведомлений
+const notifyTimeout = 10 * time.Second
+
 // App запущенное приложение
 type App struct {
 	server *http.Server
 	logger *slog.Logger
 	cfg    config.Config
 	db     *sqlx.DB
+
+	jobWorker   *usecases.JobWorker
+	outboxRelay *usecases.OutboxRelay
+	webhooks    *usecases.WebhookDispatcher
+	eventStream *usecases.EventStream
+	absences    *usecases.AbsenceWatcher
+	// overdue напоминания о просроченных ревью; nil, если каналов уведомлений нет
+	overdue *usecases.OverdueReviewNotifier
+	// digests ежедневные дайджесты; nil, если каналов уведомлений нет
+	digests      *usecases.DigestScheduler
+	startWorkers sync.Once
+	stopWorkers  context.CancelFunc
+	workersDone  sync.WaitGroup
 }
 
 // New настройка приложения.
@@ -46,33 +87,140 @@ func New(cfg config.Config, logger *slog.Logger) (*App, error) {
 	userStorage := postgresql.NewUserAdapter(connection, logger)
 	teamStorage := postgresql.NewTeamAdapter(connection, logger)
 	prStorage := postgresql.NewPullRequestAdapter(connection, logger)
+	rotationStorage := postgresql.NewRotationAdapter(connection, logger)
+	operationStorage := postgresql.NewOperationAdapter(connection, logger)
+	jobStorage := postgresql.NewJobAdapter(connection, logger)
+	auditStorage := postgresql.NewAuditAdapter(connection, logger)
+	historyStorage := postgresql.NewPullRequestHistoryAdapter(connection, logger)
+	outboxStorage := postgresql.NewOutboxAdapter(connection, logger)
+	webhookStorage := postgresql.NewWebhookAdapter(connection, logger)
+	identityStorage := postgresql.NewUserIdentityAdapter(connection, logger)
+	reminderStorage := postgresql.NewReviewReminderAdapter(connection, logger)
+	notificationDeliveryStorage := postgresql.NewNotificationDeliveryAdapter(connection, logger)
+	digestStorage := postgresql.NewReviewDigestAdapter(connection, logger)
+	transactor := postgresql.NewTransactor(connection, logger)
 
 	clockAdapter := clock.NewSystem()
 	randomAdapter := random.New(rand.New(rand.NewSource(time.Now().UnixNano())))
+	idGenerator := idgen.New()
+	auditTrail := usecases.NewAuditTrail(auditStorage, clockAdapter)
+	prHistory := usecases.NewPullRequestHistory(historyStorage, clockAdapter)
+	outbox := usecases.NewOutbox(outboxStorage, clockAdapter)
+
+	selector, err := newReviewerSelector(cfg, prStorage, rotationStorage, clockAdapter, randomAdapter, logger)
+	if err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
+
+	notifiers, err := newNotifiers(cfg)
+	if err != nil {
+		_ = connection.Close()
+		return nil, err
+	}
 
-	createTeamUC := usecases.NewCreateTeamUseCase(teamStorage, userStorage, logger)
+	topUpReviewersUC := usecases.NewTopUpReviewersUseCase(prStorage, teamStorage, userStorage, selector, transactor, auditTrail, prHistory, outbox, logger)
+	createTeamUC := usecases.NewCreateTeamUseCase(teamStorage, userStorage, transactor, auditTrail, logger)
 	getTeamUC := usecases.NewGetTeamUseCase(teamStorage, logger)
-	setUserActiveUC := usecases.NewSetUserActiveUseCase(userStorage, logger)
-	createPullRequestUC := usecases.NewCreatePullRequestUseCase(prStorage, teamStorage, userStorage, clockAdapter, randomAdapter, logger)
-	mergePullRequestUC := usecases.NewMergePullRequestUseCase(prStorage, clockAdapter, logger)
-	reassignReviewerUC := usecases.NewReassignReviewerUseCase(prStorage, teamStorage, userStorage, randomAdapter, logger)
+	updateTeamUC := usecases.NewUpdateTeamUseCase(teamStorage, userStorage, topUpReviewersUC, transactor, auditTrail, logger)
+	setUserActiveUC := usecases.NewSetUserActiveUseCase(userStorage, topUpReviewersUC, transactor, auditTrail, outbox, logger)
+	setUserDigestUC := usecases.NewSetUserDigestUseCase(userStorage, transactor, auditTrail, logger)
+	setUserAbsenceUC := usecases.NewSetUserAbsenceUseCase(userStorage, topUpReviewersUC, clockAdapter, transactor, auditTrail, logger)
+	createPullRequestUC := usecases.NewCreatePullRequestUseCase(prStorage, teamStorage, userStorage, clockAdapter, selector, transactor, auditTrail, prHistory, outbox, logger)
+	mergePullRequestUC := usecases.NewMergePullRequestUseCase(prStorage, clockAdapter, transactor, auditTrail, prHistory, outbox, logger)
+	reassignReviewerUC := usecases.NewReassignReviewerUseCase(prStorage, teamStorage, userStorage, selector, transactor, auditTrail, prHistory, outbox, logger)
 	getReviewerPRsUC := usecases.NewGetReviewerPullRequestsUseCase(prStorage, logger)
 	getStatsUC := usecases.NewGetStatsUseCase(prStorage, userStorage, logger)
-	deactivateTeamUsersUC := usecases.NewDeactivateTeamUsersUseCase(userStorage, teamStorage, prStorage, randomAdapter, logger)
+	deactivateTeamUsersUC := usecases.NewDeactivateTeamUsersUseCase(userStorage, teamStorage, prStorage, operationStorage, selector, clockAdapter, idGenerator, transactor, auditTrail, prHistory, outbox, logger)
+	revertOperationUC := usecases.NewRevertOperationUseCase(operationStorage, userStorage, prStorage, clockAdapter, transactor, auditTrail, prHistory, outbox, logger)
+	rebalanceTeamUC := usecases.NewRebalanceTeamUseCase(teamStorage, userStorage, prStorage, clockAdapter, transactor, auditTrail, prHistory, outbox, logger)
+	enqueueJobUC := usecases.NewEnqueueJobUseCase(jobStorage, clockAdapter, idGenerator, logger)
+	getJobUC := usecases.NewGetJobUseCase(jobStorage, logger)
+	listAuditEventsUC := usecases.NewListAuditEventsUseCase(auditStorage, logger)
+	getPRHistoryUC := usecases.NewGetPullRequestHistoryUseCase(prStorage, historyStorage, logger)
+	createWebhookUC := usecases.NewCreateWebhookUseCase(webhookStorage, clockAdapter, idGenerator, transactor, auditTrail, logger)
+	listWebhooksUC := usecases.NewListWebhooksUseCase(webhookStorage, logger)
+	deleteWebhookUC := usecases.NewDeleteWebhookUseCase(webhookStorage, transactor, auditTrail, logger)
+	listWebhookDeliveriesUC := usecases.NewListWebhookDeliveriesUseCase(webhookStorage, logger)
+
+	createUserIdentityUC := usecases.NewCreateUserIdentityUseCase(identityStorage, userStorage, clockAdapter, idGenerator, transactor, auditTrail, logger)
+	listUserIdentitiesUC := usecases.NewListUserIdentitiesUseCase(identityStorage, logger)
+	updateUserIdentityUC := usecases.NewUpdateUserIdentityUseCase(identityStorage, transactor, auditTrail, logger)
+	deleteUserIdentityUC := usecases.NewDeleteUserIdentityUseCase(identityStorage, transactor, auditTrail, logger)
+
+	identities := usecases.NewIdentityLookup(identityStorage)
+	pullRequestLifecycleUC := usecases.NewPullRequestLifecycleUseCase(prStorage, topUpReviewersUC, transactor, auditTrail, prHistory, logger)
+	syncPullRequestUC := usecases.NewSyncPullRequestUseCase(createPullRequestUC, mergePullRequestUC, pullRequestLifecycleUC, identities, logger)
+
+	jobWorker := usecases.NewJobWorker(jobStorage, map[string]usecases.JobFunc{
+		domain.JobTypeTeamDeactivation: usecases.TeamDeactivationJob(deactivateTeamUsersUC),
+		domain.JobTypeTeamRebalance:    usecases.TeamRebalanceJob(rebalanceTeamUC),
+	}, clockAdapter, cfg.JobTimeout, cfg.JobPollInterval, logger)
+
+	publishers := []usecases.EventPublisher{
+		events.NewLogPublisher(logger),
+		usecases.NewWebhookFanout(webhookStorage, clockAdapter, logger),
+	}
+	if cfg.GitHubToken != "" {
+		reviewerPublisher := github.NewReviewerPublisher(cfg.GitHubAPIURL, cfg.GitHubToken, githubAPITimeout)
+		publishers = append(publishers, usecases.NewReviewerSync(reviewerPublisher, identities, auditTrail, logger))
+	}
+	if len(notifiers) > 0 {
+		publishers = append(publishers, usecases.NewReviewNotifier(notifiers, identities, prStorage, userStorage, notificationDeliveryStorage, clockAdapter, logger))
+	}
+	outboxRelay := usecases.NewOutboxRelay(outboxStorage, publishers, clockAdapter, cfg.OutboxBatchSize, cfg.OutboxPollInterval, logger)
+
+	var overdueNotifier *usecases.OverdueReviewNotifier
+	var digestScheduler *usecases.DigestScheduler
+	if len(notifiers) > 0 {
+		overdueNotifier = usecases.NewOverdueReviewNotifier(reminderStorage, notifiers, identities, userStorage,
+			clockAdapter, cfg.ReviewOverdueAfter, cfg.ReviewOverdueCheckInterval, logger)
+		digestScheduler = usecases.NewDigestScheduler(userStorage, prStorage, digestStorage, notifiers, identities,
+			clockAdapter, cfg.DigestHour, cfg.DigestCheckInterval, logger)
+	}
+
+	absenceWatcher := usecases.NewAbsenceWatcher(userStorage, setUserAbsenceUC, clockAdapter, cfg.AbsenceCheckInterval, logger)
+	eventStream := usecases.NewEventStream(outboxStorage, cfg.EventStreamPollInterval, logger)
+
+	webhookDispatcher := usecases.NewWebhookDispatcher(webhookStorage, webhook.NewHTTPSender(cfg.WebhookTimeout),
+		clockAdapter, cfg.WebhookBatchSize, cfg.WebhookPollInterval, logger)
 
 	router := httpcontroller.NewRouter(httpcontroller.RouterConfig{
-		Logger:                     logger,
-		AdminToken:                 cfg.AdminToken,
-		UserToken:                  cfg.UserToken,
-		AddTeamUseCase:             createTeamUC,
-		GetTeamUseCase:             getTeamUC,
-		SetUserActiveUseCase:       setUserActiveUC,
-		CreatePullRequestUseCase:   createPullRequestUC,
-		MergePullRequestUseCase:    mergePullRequestUC,
-		ReassignReviewerUseCase:    reassignReviewerUC,
-		GetReviewerPRsUseCase:      getReviewerPRsUC,
-		GetStatsUseCase:            getStatsUC,
-		DeactivateTeamUsersUseCase: deactivateTeamUsersUC,
+		Logger:                   logger,
+		AdminToken:               cfg.AdminToken,
+		UserToken:                cfg.UserToken,
+		GitHubWebhookSecret:      cfg.GitHubWebhookSecret,
+		GitLabWebhookToken:       cfg.GitLabWebhookToken,
+		AddTeamUseCase:           createTeamUC,
+		GetTeamUseCase:           getTeamUC,
+		UpdateTeamUseCase:        updateTeamUC,
+		SetUserActiveUseCase:     setUserActiveUC,
+		SetUserDigestUseCase:     setUserDigestUC,
+		SetUserAbsenceUseCase:    setUserAbsenceUC,
+		CreatePullRequestUseCase: createPullRequestUC,
+		MergePullRequestUseCase:  mergePullRequestUC,
+		ReassignReviewerUseCase:  reassignReviewerUC,
+		GetReviewerPRsUseCase:    getReviewerPRsUC,
+		GetStatsUseCase:          getStatsUC,
+		RevertOperationUseCase:   revertOperationUC,
+		EnqueueJobUseCase:        enqueueJobUC,
+		GetJobUseCase:            getJobUC,
+		ListAuditEventsUseCase:   listAuditEventsUC,
+		GetPRHistoryUseCase:      getPRHistoryUC,
+
+		CreateWebhookUseCase:         createWebhookUC,
+		ListWebhooksUseCase:          listWebhooksUC,
+		DeleteWebhookUseCase:         deleteWebhookUC,
+		ListWebhookDeliveriesUseCase: listWebhookDeliveriesUC,
+
+		SyncPullRequestUseCase: syncPullRequestUC,
+
+		CreateUserIdentityUseCase: createUserIdentityUC,
+		ListUserIdentitiesUseCase: listUserIdentitiesUC,
+		UpdateUserIdentityUseCase: updateUserIdentityUC,
+		DeleteUserIdentityUseCase: deleteUserIdentityUC,
+
+		EventStream: eventStream,
 	})
 
 	server := &http.Server{
@@ -80,33 +228,185 @@ func New(cfg config.Config, logger *slog.Logger) (*App, error) {
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	// Shutdown ждёт завершения запросов, а поток событий сам не заканчивается
+	server.RegisterOnShutdown(eventStream.Close)
 
 	return &App{
-		server: server,
-		logger: logger,
-		cfg:    cfg,
-		db:     db,
+		server:      server,
+		logger:      logger,
+		cfg:         cfg,
+		db:          db,
+		jobWorker:   jobWorker,
+		outboxRelay: outboxRelay,
+		webhooks:    webhookDispatcher,
+		eventStream: eventStream,
+		absences:    absenceWatcher,
+		overdue:     overdueNotifier,
+		digests:     digestScheduler,
 	}, nil
 }
 
+// newReviewerSelector выбирает стратегию назначения ревьюеров по конфигурации.
+func newReviewerSelector(
+	cfg config.Config,
+	prStorage usecases.PullRequestStorage,
+	rotationStorage usecases.RotationStorage,
+	clockAdapter usecases.ClockAdapter,
+	randomAdapter usecases.RandomAdapter,
+	logger *slog.Logger,
+) (usecases.ReviewerSelector, error) {
+	var selector usecases.ReviewerSelector
+	switch cfg.AssignmentStrategy {
+	case "", strategyRandom:
+		selector = usecases.NewRandomReviewerSelector(randomAdapter)
+	case strategyHistory:
+		selector = usecases.NewHistoryReviewerSelector(prStorage, randomAdapter, cfg.PairingHistoryWindow, logger)
+	case strategyWeighted:
+		selector = usecases.NewWeightedReviewerSelector(randomAdapter)
+	case strategyRoundRobin:
+		selector = usecases.NewRoundRobinReviewerSelector(rotationStorage, logger)
+	default:
+		return nil, fmt.Errorf("неизвестная стратегия назначения ревьюеров: %q", cfg.AssignmentStrategy)
+	}
+
+	if cfg.PreferWorkingHours {
+		selector = usecases.NewWorkingHoursReviewerSelector(selector, clockAdapter)
+	}
+	return usecases.NewAvailableReviewerSelector(selector, clockAdapter), nil
+}
+
+// newNotifiers каналы личных уведомлений, настроенные в конфигурации.
+func newNotifiers(cfg config.Config) ([]usecases.Notifier, error) {
+	var notifiers []usecases.Notifier
+	if cfg.ChatWebhookURL != "" {
+		switch cfg.ChatProvider {
+		case "", chatSlack:
+			notifiers = append(notifiers, chat.NewSlackNotifier(cfg.ChatWebhookURL, notifyTimeout))
+		case chatMattermost:
+			notifiers = append(notifiers, chat.NewMattermostNotifier(cfg.ChatWebhookURL, notifyTimeout))
+		default:
+			return nil, fmt.Errorf("неизвестный чат для уведомлений: %q", cfg.ChatProvider)
+		}
+	}
+	if cfg.SMTPHost != "" {
+		smtpNotifier, err := email.NewSMTPNotifier(email.Config{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			Locale:   cfg.SMTPLocale,
+			Timeout:  notifyTimeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, smtpNotifier)
+	}
+	return notifiers, nil
+}
+
+// shutdownWorkers отменяет обработчики задач, релей outbox, отправку вебхуков, поток событий, напоминания и дайджесты и ждёт их выхода.
+func (a *App) shutdownWorkers(ctx context.Context) error {
+	if a.stopWorkers == nil {
+		return nil
+	}
+	a.stopWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		a.workersDone.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Handler для тестирования.
 func (a *App) Handler() http.Handler {
 	return a.server.Handler
 }
 
-// Start запускает HTTP сервер.
+// StartWorkers запускает обработчики фоновых задач, релей outbox и отправку вебхуков. Повторный вызов ничего не делает.
+// Задачи и события, брошенные прошлым запуском, подхватываются после истечения аренды.
+func (a *App) StartWorkers() {
+	a.startWorkers.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		a.stopWorkers = cancel
+
+		a.logger.Info("запускаем обработчики фоновых задач", "workers", a.cfg.JobWorkers)
+		for i := 0; i < a.cfg.JobWorkers; i++ {
+			a.workersDone.Add(1)
+			go func() {
+				defer a.workersDone.Done()
+				a.jobWorker.Run(ctx)
+			}()
+		}
+
+		a.workersDone.Add(1)
+		go func() {
+			defer a.workersDone.Done()
+			a.outboxRelay.Run(ctx)
+		}()
+
+		a.workersDone.Add(1)
+		go func() {
+			defer a.workersDone.Done()
+			a.webhooks.Run(ctx)
+		}()
+
+		a.workersDone.Add(1)
+		go func() {
+			defer a.workersDone.Done()
+			a.eventStream.Run(ctx)
+		}()
+
+		a.workersDone.Add(1)
+		go func() {
+			defer a.workersDone.Done()
+			a.absences.Run(ctx)
+		}()
+
+		if a.overdue != nil {
+			a.workersDone.Add(1)
+			go func() {
+				defer a.workersDone.Done()
+				a.overdue.Run(ctx)
+			}()
+		}
+		if a.digests != nil {
+			a.workersDone.Add(1)
+			go func() {
+				defer a.workersDone.Done()
+				a.digests.Run(ctx)
+			}()
+		}
+	})
+}
+
+// Start запускает обработчики задач и HTTP сервер.
 func (a *App) Start() error {
+	a.StartWorkers()
 	a.logger.Info("запускаем HTTP сервер", "addr", a.server.Addr)
 	return a.server.ListenAndServe()
 }
 
-// Shutdown останавливает сервер.
+// Shutdown останавливает сервер и дожидается обработчиков задач.
 func (a *App) Shutdown(ctx context.Context) error {
 	a.logger.Info("останавливаем HTTP сервер")
 	if err := a.server.Shutdown(ctx); err != nil {
 		return err
 	}
 
+	if err := a.shutdownWorkers(ctx); err != nil {
+		return err
+	}
+
 	if a.db != nil {
 		if err := a.db.Close(); err != nil {
 			a.logger.Warn("ошибка закрытия соединения с PostgreSQL", "error", err)
@@ -0,0 +1,106 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type AuditHandler struct {
+	logger                 *slog.Logger
+	listAuditEventsUseCase *usecases.ListAuditEventsUseCase
+}
+
+func NewAuditHandler(
+	logger *slog.Logger,
+	listAuditEventsUseCase *usecases.ListAuditEventsUseCase,
+) *AuditHandler {
+	return &AuditHandler{
+		logger:                 logger,
+		listAuditEventsUseCase: listAuditEventsUseCase,
+	}
+}
+
+// List возвращает журнал аудита с фильтрами по сущности, автору и периоду
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "некорректные параметры аудита", "error", err)
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", err.Error())
+		return
+	}
+
+	events, err := h.listAuditEventsUseCase.List(r.Context(), filter)
+	if err != nil {
+		status, code, message := mapAuditError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка чтения журнала аудита", "error", err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output := dto.AuditEventsOutput{Events: make([]dto.AuditEvent, 0, len(events))}
+	for _, event := range events {
+		output.Events = append(output.Events, dto.AuditEvent{
+			ID:         event.ID,
+			Actor:      event.Actor,
+			Action:     event.Action,
+			EntityType: event.EntityType,
+			EntityID:   event.EntityID,
+			Before:     json.RawMessage(event.Before),
+			After:      json.RawMessage(event.After),
+			CreatedAt:  event.CreatedAt,
+		})
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	query := r.URL.Query()
+	filter := domain.AuditFilter{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Actor:      query.Get("actor"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		return domain.AuditFilter{}, errors.New("from должен быть в формате RFC3339")
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		return domain.AuditFilter{}, errors.New("to должен быть в формате RFC3339")
+	}
+	if raw := query.Get("limit"); raw != "" {
+		if filter.Limit, err = strconv.Atoi(raw); err != nil || filter.Limit <= 0 {
+			return domain.AuditFilter{}, errors.New("limit должен быть положительным числом")
+		}
+	}
+	return filter, nil
+}
+
+func parseTimeParam(raw string) (*time.Time, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
+
+func mapAuditError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidAuditFilter):
+		return http.StatusBadRequest, "INVALID_INPUT", "invalid audit filter"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
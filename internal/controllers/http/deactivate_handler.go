@@ -12,21 +12,24 @@ import (
 )
 
 type DeactivateHandler struct {
-	logger                     *slog.Logger
-	deactivateTeamUsersUseCase *usecases.DeactivateTeamUsersUseCase
+	logger            *slog.Logger
+	getTeamUseCase    *usecases.GetTeamUseCase
+	enqueueJobUseCase *usecases.EnqueueJobUseCase
 }
 
 func NewDeactivateHandler(
 	logger *slog.Logger,
-	deactivateTeamUsersUseCase *usecases.DeactivateTeamUsersUseCase,
+	getTeamUseCase *usecases.GetTeamUseCase,
+	enqueueJobUseCase *usecases.EnqueueJobUseCase,
 ) *DeactivateHandler {
 	return &DeactivateHandler{
-		logger:                     logger,
-		deactivateTeamUsersUseCase: deactivateTeamUsersUseCase,
+		logger:            logger,
+		getTeamUseCase:    getTeamUseCase,
+		enqueueJobUseCase: enqueueJobUseCase,
 	}
 }
 
-// DeactivateTeamUsers массово деактивирует пользователей команды
+// DeactivateTeamUsers ставит массовую деактивацию пользователей команды в очередь
 func (h *DeactivateHandler) DeactivateTeamUsers(w http.ResponseWriter, r *http.Request) {
 	var input dto.DeactivateTeamUsersInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
@@ -39,27 +42,89 @@ func (h *DeactivateHandler) DeactivateTeamUsers(w http.ResponseWriter, r *http.R
 		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "team_name обязателен")
 		return
 	}
+	if len(input.UserIDs) > 0 && len(input.ExceptUserIDs) > 0 {
+		status, code, message := mapDeactivateError(domain.ErrInvalidUserFilter)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
 
-	result, err := h.deactivateTeamUsersUseCase.DeactivateTeamUsers(r.Context(), input.TeamName)
-	if err != nil {
+	// команду проверяю сразу, чтобы опечатка давала 404, а не упавшую задачу
+	if _, err := h.getTeamUseCase.Get(r.Context(), input.TeamName); err != nil {
 		status, code, message := mapDeactivateError(err)
-		h.logger.ErrorContext(r.Context(), "ошибка массовой деактивации", "error", err, "team", input.TeamName)
 		respondError(h.logger, w, status, code, message)
 		return
 	}
 
+	job, err := h.enqueueJobUseCase.Enqueue(r.Context(), domain.JobTypeTeamDeactivation, usecases.TeamDeactivationParams{
+		TeamName: input.TeamName,
+		Options: usecases.DeactivateOptions{
+			DryRun:        input.DryRun,
+			UserIDs:       input.UserIDs,
+			ExceptUserIDs: input.ExceptUserIDs,
+		},
+	})
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "ошибка постановки массовой деактивации", "error", err, "team", input.TeamName)
+		respondError(h.logger, w, http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError)
+		return
+	}
+
+	respondJobAccepted(h.logger, w, job)
+}
+
+func toDeactivateOutput(result usecases.DeactivateResult) dto.DeactivateTeamUsersOutput {
 	output := dto.DeactivateTeamUsersOutput{
-		DeactivatedCount:  result.DeactivatedCount,
-		ReassignedPRCount: result.ReassignedPRCount,
+		DryRun:                 result.DryRun,
+		DeactivatedCount:       result.DeactivatedCount,
+		ReassignedPRCount:      result.ReassignedPRCount,
+		DeactivatedUserIDs:     result.DeactivatedUserIDs,
+		PullRequests:           make([]dto.PullRequestReport, 0, len(result.PullRequests)),
+		OperationID:            result.OperationID,
+		ReplacementsIndicative: result.ReplacementsIndicative,
+	}
+	if output.DeactivatedUserIDs == nil {
+		output.DeactivatedUserIDs = []string{}
+	}
+	for _, report := range result.PullRequests {
+		output.PullRequests = append(output.PullRequests, toPullRequestReport(report))
 	}
+	return output
+}
 
-	respondJSON(h.logger, w, http.StatusOK, output)
+func toPullRequestReport(report usecases.PullRequestReport) dto.PullRequestReport {
+	result := dto.PullRequestReport{
+		PullRequestID:   report.PullRequestID,
+		ReviewersBefore: report.ReviewersBefore,
+		ReviewersAfter:  report.ReviewersAfter,
+		Replacements:    make([]dto.ReviewerReplacement, 0, len(report.Replacements)),
+	}
+	if result.ReviewersAfter == nil {
+		result.ReviewersAfter = []string{}
+	}
+	for _, replacement := range report.Replacements {
+		item := dto.ReviewerReplacement{
+			OldReviewerID: replacement.OldReviewerID,
+			Reason:        replacement.Reason,
+		}
+		if replacement.NewReviewerID != "" {
+			newReviewerID := replacement.NewReviewerID
+			item.NewReviewerID = &newReviewerID
+		}
+		result.Replacements = append(result.Replacements, item)
+	}
+	return result
 }
 
 func mapDeactivateError(err error) (int, string, string) {
 	switch {
 	case errors.Is(err, domain.ErrTeamNotFound):
 		return http.StatusNotFound, ErrCodeNotFound, "team not found"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "user is not a team member"
+	case errors.Is(err, domain.ErrInvalidUserFilter):
+		return http.StatusBadRequest, "INVALID_INPUT", "user_ids and except_user_ids are mutually exclusive"
+	case errors.Is(err, domain.ErrNoSeniorReviewer):
+		return http.StatusConflict, ErrCodeNoSenior, "team policy requires a senior reviewer, none available"
 	default:
 		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
 	}
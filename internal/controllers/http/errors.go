@@ -2,17 +2,21 @@ package httpcontroller
 
 // Коды ошибок для API
 const (
-	ErrCodeNotFound     = "NOT_FOUND"
-	ErrCodeInternal     = "INTERNAL"
-	ErrCodeNoCandidate  = "NO_CANDIDATE"
-	ErrCodeTeamExists   = "TEAM_EXISTS"
-	ErrCodePRExists     = "PR_EXISTS"
-	ErrCodePRMerged     = "PR_MERGED"
-	ErrCodeNotAssigned  = "NOT_ASSIGNED"
+	ErrCodeNotFound       = "NOT_FOUND"
+	ErrCodeInternal       = "INTERNAL"
+	ErrCodeNoCandidate    = "NO_CANDIDATE"
+	ErrCodeTeamExists     = "TEAM_EXISTS"
+	ErrCodePRExists       = "PR_EXISTS"
+	ErrCodePRMerged       = "PR_MERGED"
+	ErrCodeNotAssigned    = "NOT_ASSIGNED"
+	ErrCodeBadReviewer    = "INVALID_REVIEWER"
+	ErrCodeRequired       = "REVIEWER_REQUIRED"
+	ErrCodeNoSenior       = "NO_SENIOR_CANDIDATE"
+	ErrCodeReverted       = "OPERATION_REVERTED"
+	ErrCodeIdentityExists = "IDENTITY_EXISTS"
 )
 
 // Сообщения об ошибках
 const (
 	ErrMsgInternalError = "internal error"
 )
-
@@ -0,0 +1,139 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+// eventStreamHeartbeat как часто слать комментарий в простаивающий поток: прокси не рвут
+// соединение, а отвалившийся клиент обнаруживается по ошибке записи
+const eventStreamHeartbeat = 15 * time.Second
+
+type EventStreamHandler struct {
+	logger *slog.Logger
+	stream *usecases.EventStream
+}
+
+func NewEventStreamHandler(logger *slog.Logger, stream *usecases.EventStream) *EventStreamHandler {
+	return &EventStreamHandler{
+		logger: logger,
+		stream: stream,
+	}
+}
+
+// Stream отдаёт доменные события как Server-Sent Events с фильтром по команде и пользователю.
+// После обрыва клиент передаёт Last-Event-ID и получает пропущенные события из журнала.
+func (h *EventStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var lastEventID int64
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 0 {
+			respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "Last-Event-ID должен быть id события")
+			return
+		}
+		lastEventID = id
+	}
+
+	filter := domain.EventFilter{
+		TeamName: r.URL.Query().Get("team_name"),
+		UserID:   r.URL.Query().Get("user_id"),
+	}
+	sub, err := h.stream.Subscribe(ctx, filter, lastEventID)
+	if err != nil {
+		status, code, message := mapEventStreamError(err)
+		h.logger.ErrorContext(ctx, "ошибка подписки на поток событий", "error", err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// nginx иначе копит ответ в буфере и события приходят пачками
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if err := controller.Flush(); err != nil {
+		h.logger.ErrorContext(ctx, "поток событий не поддерживается соединением", "error", err)
+		return
+	}
+
+	if sub.Reset {
+		if err := h.writeReset(w); err != nil {
+			return
+		}
+	}
+	for _, message := range sub.Backlog {
+		if err := h.write(w, message); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if err := h.write(w, message); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// write пишет событие в формате SSE; json.Marshal не переносит строки, поэтому хватает одной строки data.
+func (h *EventStreamHandler) write(w http.ResponseWriter, message domain.OutboxMessage) error {
+	data, err := json.Marshal(dto.Event{
+		ID:         message.ID,
+		Type:       message.EventType,
+		Actor:      message.Actor,
+		OccurredAt: message.CreatedAt,
+		Data:       message.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.EventType, data)
+	return err
+}
+
+// writeReset сообщает, что пропущенные события не восстановить. Пустой id сбрасывает
+// Last-Event-ID у EventSource, так что следующее переподключение начнётся с текущего момента.
+func (h *EventStreamHandler) writeReset(w http.ResponseWriter) error {
+	_, err := fmt.Fprint(w, "id\nevent: reset\ndata: {\"reason\":\"unknown_last_event_id\"}\n\n")
+	return err
+}
+
+func mapEventStreamError(err error) (int, string, string) {
+	if errors.Is(err, domain.ErrEventStreamClosed) {
+		return http.StatusServiceUnavailable, "UNAVAILABLE", "event stream is shutting down"
+	}
+	return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+}
@@ -0,0 +1,112 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/signature"
+)
+
+// Заголовки вебхуков GitHub
+const (
+	GitHubEventHeader     = "X-GitHub-Event"
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+)
+
+// maxIntegrationBody предел тела входящего вебхука
+const maxIntegrationBody = 5 << 20
+
+type GitHubHandler struct {
+	logger                 *slog.Logger
+	secret                 string
+	syncPullRequestUseCase *usecases.SyncPullRequestUseCase
+}
+
+func NewGitHubHandler(
+	logger *slog.Logger,
+	secret string,
+	syncPullRequestUseCase *usecases.SyncPullRequestUseCase,
+) *GitHubHandler {
+	return &GitHubHandler{
+		logger:                 logger,
+		secret:                 secret,
+		syncPullRequestUseCase: syncPullRequestUseCase,
+	}
+}
+
+// Webhook принимает вебхук GitHub, подписанный общим секретом; без секрета в конфигурации все запросы отклоняются
+func (h *GitHubHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBody))
+	if err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "не удалось прочитать тело запроса", err)
+		return
+	}
+
+	if !signature.Verify(h.secret, body, r.Header.Get(GitHubSignatureHeader)) {
+		h.logger.WarnContext(r.Context(), "неверная подпись вебхука GitHub")
+		respondError(h.logger, w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid signature")
+		return
+	}
+
+	if r.Header.Get(GitHubEventHeader) != "pull_request" {
+		respondJSON(h.logger, w, http.StatusOK, dto.IntegrationEventOutput{Result: usecases.SyncResultIgnored})
+		return
+	}
+
+	var payload dto.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "неверный формат события", err)
+		return
+	}
+	if payload.Repository.FullName == "" || payload.Number <= 0 {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "в событии нет репозитория или номера pull request", nil)
+		return
+	}
+
+	action := payload.Action
+	if action == usecases.ExternalActionClosed && payload.PullRequest.Merged {
+		action = usecases.ExternalActionMerged
+	}
+
+	ctx := usecases.WithActor(r.Context(), domain.IdentityProviderGitHub+":"+payload.Sender.Login)
+	result, err := h.syncPullRequestUseCase.Sync(ctx, usecases.ExternalPullRequestEvent{
+		Provider:      domain.IdentityProviderGitHub,
+		Action:        action,
+		PullRequestID: usecases.GitHubPullRequestID(payload.Repository.FullName, payload.Number),
+		Title:         payload.PullRequest.Title,
+		AuthorLogin:   payload.PullRequest.User.Login,
+		Draft:         payload.PullRequest.Draft,
+	})
+	if err != nil {
+		status, code, message := mapIntegrationError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка обработки события GitHub", "error", err, "action", payload.Action)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.IntegrationEventOutput{Result: result})
+}
+
+// mapIntegrationError ошибки use case'ов видны в журнале доставок внешней системы
+func mapIntegrationError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrIdentityNotFound):
+		return http.StatusUnprocessableEntity, ErrCodeNotFound, "author has no linked identity"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusUnprocessableEntity, ErrCodeNotFound, "author is not a known user"
+	case errors.Is(err, domain.ErrTeamNotFound):
+		return http.StatusUnprocessableEntity, ErrCodeNotFound, "author has no team"
+	case errors.Is(err, domain.ErrNoReviewerCandidates):
+		return http.StatusConflict, ErrCodeNoCandidate, "no active reviewer candidates in team"
+	case errors.Is(err, domain.ErrNoSeniorReviewer):
+		return http.StatusConflict, ErrCodeNoSenior, "team policy requires a senior reviewer, none available"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
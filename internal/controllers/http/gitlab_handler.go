@@ -0,0 +1,124 @@
+package httpcontroller
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+// Заголовки вебхуков GitLab
+const (
+	GitLabEventHeader = "X-Gitlab-Event"
+	GitLabTokenHeader = "X-Gitlab-Token"
+)
+
+// gitLabActions действия Merge Request Hook, у которых есть аналог в сервисе
+var gitLabActions = map[string]string{
+	"open":   usecases.ExternalActionOpened,
+	"reopen": usecases.ExternalActionReopened,
+	"merge":  usecases.ExternalActionMerged,
+	"close":  usecases.ExternalActionClosed,
+}
+
+type GitLabHandler struct {
+	logger                 *slog.Logger
+	token                  string
+	syncPullRequestUseCase *usecases.SyncPullRequestUseCase
+}
+
+func NewGitLabHandler(
+	logger *slog.Logger,
+	token string,
+	syncPullRequestUseCase *usecases.SyncPullRequestUseCase,
+) *GitLabHandler {
+	return &GitLabHandler{
+		logger:                 logger,
+		token:                  token,
+		syncPullRequestUseCase: syncPullRequestUseCase,
+	}
+}
+
+// Webhook принимает Merge Request Hook с секретным токеном; без токена в конфигурации все запросы отклоняются
+func (h *GitLabHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if !checkGitLabToken(r, h.token) {
+		h.logger.WarnContext(r.Context(), "неверный токен вебхука GitLab")
+		respondError(h.logger, w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid token")
+		return
+	}
+
+	if r.Header.Get(GitLabEventHeader) != "Merge Request Hook" {
+		respondJSON(h.logger, w, http.StatusOK, dto.IntegrationEventOutput{Result: usecases.SyncResultIgnored})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationBody))
+	if err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "не удалось прочитать тело запроса", err)
+		return
+	}
+
+	var payload dto.GitLabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "неверный формат события", err)
+		return
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID <= 0 {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "в событии нет проекта или номера merge request", nil)
+		return
+	}
+
+	ctx := usecases.WithActor(r.Context(), domain.IdentityProviderGitLab+":"+payload.User.Username)
+	result, err := h.syncPullRequestUseCase.Sync(ctx, usecases.ExternalPullRequestEvent{
+		Provider:      domain.IdentityProviderGitLab,
+		Action:        gitLabAction(payload),
+		PullRequestID: usecases.GitLabMergeRequestID(payload.Project.PathWithNamespace, payload.ObjectAttributes.IID),
+		Title:         payload.ObjectAttributes.Title,
+		AuthorLogin:   gitLabAuthorLogin(payload),
+		Draft:         payload.ObjectAttributes.Draft,
+	})
+	if err != nil {
+		status, code, message := mapIntegrationError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка обработки события GitLab", "error", err, "action", payload.ObjectAttributes.Action)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, dto.IntegrationEventOutput{Result: result})
+}
+
+// gitLabAction приводит действие GitLab к действию сервиса. Отдельных событий про черновик в GitLab нет,
+// это update с изменением draft.
+func gitLabAction(payload dto.GitLabMergeRequestEvent) string {
+	if action, ok := gitLabActions[payload.ObjectAttributes.Action]; ok {
+		return action
+	}
+	if payload.ObjectAttributes.Action == "update" && payload.Changes.Draft != nil {
+		if payload.Changes.Draft.Current {
+			return usecases.ExternalActionConvertedToDraft
+		}
+		return usecases.ExternalActionReadyForReview
+	}
+	return payload.ObjectAttributes.Action
+}
+
+// gitLabAuthorLogin логин автора MR. В событии есть только числовой author_id, а логин - у того,
+// кто вызвал событие, поэтому он годится лишь когда это сам автор; иначе автор неизвестен.
+func gitLabAuthorLogin(payload dto.GitLabMergeRequestEvent) string {
+	if payload.User.ID == 0 || payload.User.ID != payload.ObjectAttributes.AuthorID {
+		return ""
+	}
+	return payload.User.Username
+}
+
+func checkGitLabToken(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(GitLabTokenHeader)), []byte(token)) == 1
+}
@@ -0,0 +1,130 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type IdentityHandler struct {
+	logger                    *slog.Logger
+	createUserIdentityUseCase *usecases.CreateUserIdentityUseCase
+	listUserIdentitiesUseCase *usecases.ListUserIdentitiesUseCase
+	updateUserIdentityUseCase *usecases.UpdateUserIdentityUseCase
+	deleteUserIdentityUseCase *usecases.DeleteUserIdentityUseCase
+}
+
+func NewIdentityHandler(
+	logger *slog.Logger,
+	createUserIdentityUseCase *usecases.CreateUserIdentityUseCase,
+	listUserIdentitiesUseCase *usecases.ListUserIdentitiesUseCase,
+	updateUserIdentityUseCase *usecases.UpdateUserIdentityUseCase,
+	deleteUserIdentityUseCase *usecases.DeleteUserIdentityUseCase,
+) *IdentityHandler {
+	return &IdentityHandler{
+		logger:                    logger,
+		createUserIdentityUseCase: createUserIdentityUseCase,
+		listUserIdentitiesUseCase: listUserIdentitiesUseCase,
+		updateUserIdentityUseCase: updateUserIdentityUseCase,
+		deleteUserIdentityUseCase: deleteUserIdentityUseCase,
+	}
+}
+
+// Create привязывает к пользователю учётную запись во внешней системе
+func (h *IdentityHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateUserIdentityInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "неверный формат запроса", err)
+		return
+	}
+
+	identity, err := h.createUserIdentityUseCase.Create(r.Context(), input.UserID, input.Provider, input.Handle)
+	if err != nil {
+		status, code, message := mapIdentityError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusCreated, toUserIdentity(identity))
+}
+
+// List возвращает учётные записи с фильтрами по пользователю и системе
+func (h *IdentityHandler) List(w http.ResponseWriter, r *http.Request) {
+	identities, err := h.listUserIdentitiesUseCase.List(r.Context(), domain.UserIdentityFilter{
+		UserID:   r.URL.Query().Get("user_id"),
+		Provider: r.URL.Query().Get("provider"),
+	})
+	if err != nil {
+		status, code, message := mapIdentityError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output := dto.UserIdentitiesOutput{Identities: make([]dto.UserIdentity, 0, len(identities))}
+	for _, identity := range identities {
+		output.Identities = append(output.Identities, toUserIdentity(identity))
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+// Update меняет логин учётной записи
+func (h *IdentityHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var input dto.UpdateUserIdentityInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondBadRequest(h.logger, r, w, "INVALID_INPUT", "неверный формат запроса", err)
+		return
+	}
+
+	identity, err := h.updateUserIdentityUseCase.Update(r.Context(), chi.URLParam(r, "id"), input.Handle)
+	if err != nil {
+		status, code, message := mapIdentityError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, toUserIdentity(identity))
+}
+
+// Delete отвязывает учётную запись
+func (h *IdentityHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.deleteUserIdentityUseCase.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		status, code, message := mapIdentityError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toUserIdentity(identity domain.UserIdentity) dto.UserIdentity {
+	return dto.UserIdentity{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Handle:    identity.Handle,
+		CreatedAt: identity.CreatedAt,
+	}
+}
+
+func mapIdentityError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidIdentity):
+		return http.StatusBadRequest, "INVALID_INPUT", "invalid identity"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "user not found"
+	case errors.Is(err, domain.ErrIdentityNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "identity not found"
+	case errors.Is(err, domain.ErrIdentityExists):
+		return http.StatusConflict, ErrCodeIdentityExists, "user already has an identity in this provider or handle is taken"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
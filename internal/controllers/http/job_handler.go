@@ -0,0 +1,113 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type JobHandler struct {
+	logger        *slog.Logger
+	getJobUseCase *usecases.GetJobUseCase
+}
+
+func NewJobHandler(
+	logger *slog.Logger,
+	getJobUseCase *usecases.GetJobUseCase,
+) *JobHandler {
+	return &JobHandler{
+		logger:        logger,
+		getJobUseCase: getJobUseCase,
+	}
+}
+
+// Get возвращает статус, прогресс и результат фоновой задачи
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "id")
+	if jobID == "" {
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "id задачи обязателен")
+		return
+	}
+
+	job, err := h.getJobUseCase.Get(r.Context(), jobID)
+	if err != nil {
+		status, code, message := mapJobError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка получения задачи", "error", err, "job_id", jobID)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output, err := toJob(job)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "ошибка чтения результата задачи", "error", err, "job_id", jobID)
+		respondError(h.logger, w, http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+// respondJobAccepted отвечает 202 со ссылкой на задачу
+func respondJobAccepted(logger *slog.Logger, w http.ResponseWriter, job domain.Job) {
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respondJSON(logger, w, http.StatusAccepted, dto.JobAccepted{JobID: job.ID, Status: job.Status})
+}
+
+func toJob(job domain.Job) (dto.Job, error) {
+	result, err := toJobResult(job)
+	if err != nil {
+		return dto.Job{}, err
+	}
+	return dto.Job{
+		JobID:      job.ID,
+		Type:       job.Type,
+		Status:     job.Status,
+		Progress:   dto.JobProgress{Done: job.Done, Total: job.Total},
+		Attempts:   job.Attempts,
+		Result:     result,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+	}, nil
+}
+
+// toJobResult приводит сохранённый результат к ответу синхронного эндпоинта
+func toJobResult(job domain.Job) (any, error) {
+	if len(job.Result) == 0 {
+		return nil, nil
+	}
+
+	switch job.Type {
+	case domain.JobTypeTeamDeactivation:
+		var result usecases.DeactivateResult
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			return nil, err
+		}
+		return toDeactivateOutput(result), nil
+	case domain.JobTypeTeamRebalance:
+		var result usecases.RebalanceResult
+		if err := json.Unmarshal(job.Result, &result); err != nil {
+			return nil, err
+		}
+		return toRebalanceOutput(result), nil
+	default:
+		return json.RawMessage(job.Result), nil
+	}
+}
+
+func mapJobError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrJobNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "job not found"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
@@ -3,6 +3,18 @@ package httpcontroller
 import (
 	"log/slog"
 	"net/http"
+	"strings"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+// ActorHeader заголовок с именем человека или сервиса, от чьего имени идёт запрос.
+// В журнал аудита попадает как "<роль>:<значение>", без заголовка - просто роль.
+const ActorHeader = "X-Actor"
+
+const (
+	roleAdmin = "admin"
+	roleUser  = "user"
 )
 
 func adminAuth(logger *slog.Logger, token string) func(http.Handler) http.Handler {
@@ -13,7 +25,7 @@ func adminAuth(logger *slog.Logger, token string) func(http.Handler) http.Handle
 				respondError(logger, w, http.StatusUnauthorized, "UNAUTHORIZED", "admin token required")
 				return
 			}
-			next.ServeHTTP(w, r)
+			next.ServeHTTP(w, withRequestActor(r, roleAdmin))
 		})
 	}
 }
@@ -21,8 +33,12 @@ func adminAuth(logger *slog.Logger, token string) func(http.Handler) http.Handle
 func userAuth(logger *slog.Logger, adminToken, userToken string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if checkBearer(r, adminToken) || checkBearer(r, userToken) {
-				next.ServeHTTP(w, r)
+			if checkBearer(r, adminToken) {
+				next.ServeHTTP(w, withRequestActor(r, roleAdmin))
+				return
+			}
+			if checkBearer(r, userToken) {
+				next.ServeHTTP(w, withRequestActor(r, roleUser))
 				return
 			}
 			logger.WarnContext(r.Context(), "неверный пользовательский токен")
@@ -39,3 +55,12 @@ func checkBearer(r *http.Request, token string) bool {
 	const prefix = "Bearer "
 	return len(value) == len(prefix)+len(token) && value[:len(prefix)] == prefix && value[len(prefix):] == token
 }
+
+// withRequestActor кладёт в контекст запроса автора изменений для аудита.
+func withRequestActor(r *http.Request, role string) *http.Request {
+	actor := role
+	if name := strings.TrimSpace(r.Header.Get(ActorHeader)); name != "" {
+		actor = role + ":" + name
+	}
+	return r.WithContext(usecases.WithActor(r.Context(), actor))
+}
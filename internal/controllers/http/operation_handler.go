@@ -0,0 +1,74 @@
+package httpcontroller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type OperationHandler struct {
+	logger                 *slog.Logger
+	revertOperationUseCase *usecases.RevertOperationUseCase
+}
+
+func NewOperationHandler(
+	logger *slog.Logger,
+	revertOperationUseCase *usecases.RevertOperationUseCase,
+) *OperationHandler {
+	return &OperationHandler{
+		logger:                 logger,
+		revertOperationUseCase: revertOperationUseCase,
+	}
+}
+
+// Revert откатывает массовую операцию
+func (h *OperationHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	operationID := chi.URLParam(r, "id")
+	if operationID == "" {
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "id операции обязателен")
+		return
+	}
+
+	result, err := h.revertOperationUseCase.Revert(r.Context(), operationID)
+	if err != nil {
+		status, code, message := mapOperationError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка отката операции", "error", err, "operation_id", operationID)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output := dto.RevertOperationOutput{
+		OperationID:      result.OperationID,
+		ReactivatedCount: result.ReactivatedCount,
+		RestoredPRIDs:    result.RestoredPRIDs,
+		SkippedPRs:       make([]dto.SkippedPullRequest, 0, len(result.SkippedPRs)),
+	}
+	if output.RestoredPRIDs == nil {
+		output.RestoredPRIDs = []string{}
+	}
+	for _, skipped := range result.SkippedPRs {
+		output.SkippedPRs = append(output.SkippedPRs, dto.SkippedPullRequest{
+			PullRequestID: skipped.PullRequestID,
+			Reason:        skipped.Reason,
+		})
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+func mapOperationError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrOperationNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "operation not found"
+	case errors.Is(err, domain.ErrOperationReverted):
+		return http.StatusConflict, ErrCodeReverted, "operation already reverted"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
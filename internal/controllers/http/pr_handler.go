@@ -16,6 +16,7 @@ type PullRequestHandler struct {
 	createPRUseCase   *usecases.CreatePullRequestUseCase
 	mergePRUseCase    *usecases.MergePullRequestUseCase
 	reassignPRUseCase *usecases.ReassignReviewerUseCase
+	historyUseCase    *usecases.GetPullRequestHistoryUseCase
 }
 
 func NewPullRequestHandler(
@@ -23,12 +24,14 @@ func NewPullRequestHandler(
 	createPRUseCase *usecases.CreatePullRequestUseCase,
 	mergePRUseCase *usecases.MergePullRequestUseCase,
 	reassignPRUseCase *usecases.ReassignReviewerUseCase,
+	historyUseCase *usecases.GetPullRequestHistoryUseCase,
 ) *PullRequestHandler {
 	return &PullRequestHandler{
 		logger:            logger,
 		createPRUseCase:   createPRUseCase,
 		mergePRUseCase:    mergePRUseCase,
 		reassignPRUseCase: reassignPRUseCase,
+		historyUseCase:    historyUseCase,
 	}
 }
 
@@ -44,7 +47,7 @@ func (h *PullRequestHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.createPRUseCase.Create(r.Context(), body.PullRequestID, body.PullRequestName, body.AuthorID)
+	pr, err := h.createPRUseCase.Create(r.Context(), body.PullRequestID, body.PullRequestName, body.AuthorID, body.RequiredReviewers)
 	if err != nil {
 		status, code, message := mapCreatePRError(err)
 		h.logger.ErrorContext(r.Context(), "ошибка создания pull request", "error", err, "pr_id", body.PullRequestID)
@@ -90,7 +93,7 @@ func (h *PullRequestHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, replacedBy, err := h.reassignPRUseCase.Reassign(r.Context(), body.PullRequestID, body.OldReviewerID, body.NewReviewerID)
+	pr, replacedBy, err := h.reassignPRUseCase.Reassign(r.Context(), body.PullRequestID, body.OldReviewerID, body.NewReviewerID, body.OverrideRequired)
 	if err != nil {
 		status, code, message := mapReassignPRError(err)
 		h.logger.ErrorContext(r.Context(), "ошибка переназначения ревьюера", "error", err, "pr_id", body.PullRequestID, "old_reviewer", body.OldReviewerID)
@@ -104,6 +107,40 @@ func (h *PullRequestHandler) Reassign(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// History возвращает историю назначений и состояний pull request.
+func (h *PullRequestHandler) History(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "pull_request_id обязателен", nil)
+		return
+	}
+
+	entries, err := h.historyUseCase.History(r.Context(), prID)
+	if err != nil {
+		status, code, message := mapMergePRError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка получения истории pull request", "error", err, "pr_id", prID)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	response := dto.PullRequestHistoryResponse{
+		PullRequestID: prID,
+		History:       make([]dto.PullRequestHistoryEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		response.History = append(response.History, dto.PullRequestHistoryEntry{
+			Event:              entry.Event,
+			ReviewerID:         entry.ReviewerID,
+			PreviousReviewerID: entry.PreviousReviewerID,
+			Reason:             entry.Reason,
+			Actor:              entry.Actor,
+			CreatedAt:          entry.CreatedAt,
+		})
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, response)
+}
+
 func toPullRequest(pr domain.PullRequest) dto.PullRequest {
 	return dto.PullRequest{
 		PullRequestID:     pr.ID,
@@ -111,6 +148,7 @@ func toPullRequest(pr domain.PullRequest) dto.PullRequest {
 		AuthorID:          pr.AuthorID,
 		Status:            pr.Status,
 		AssignedReviewers: append([]string(nil), pr.Reviewers...),
+		RequiredReviewers: append([]string(nil), pr.RequiredReviewers...),
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 	}
@@ -126,6 +164,18 @@ func mapCreatePRError(err error) (int, string, string) {
 		return http.StatusNotFound, ErrCodeNotFound, "team not found"
 	case errors.Is(err, domain.ErrNoReviewerCandidates):
 		return http.StatusConflict, ErrCodeNoCandidate, "no active reviewer candidates in team"
+	case errors.Is(err, domain.ErrReviewerIsAuthor):
+		return http.StatusBadRequest, ErrCodeBadReviewer, "author cannot be a required reviewer"
+	case errors.Is(err, domain.ErrReviewerInactive):
+		return http.StatusBadRequest, ErrCodeBadReviewer, "required reviewer is inactive"
+	case errors.Is(err, domain.ErrReviewerNotInTeam):
+		return http.StatusBadRequest, ErrCodeBadReviewer, "required reviewer is not in author's team"
+	case errors.Is(err, domain.ErrReviewerAlreadyAdded):
+		return http.StatusBadRequest, ErrCodeBadReviewer, "required reviewers must be unique"
+	case errors.Is(err, domain.ErrReviewerLimitReached):
+		return http.StatusBadRequest, ErrCodeBadReviewer, "too many required reviewers"
+	case errors.Is(err, domain.ErrNoSeniorReviewer):
+		return http.StatusConflict, ErrCodeNoSenior, "team policy requires a senior reviewer, none available"
 	default:
 		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
 	}
@@ -154,6 +204,10 @@ func mapReassignPRError(err error) (int, string, string) {
 		return http.StatusConflict, ErrCodeNoCandidate, "no active replacement candidate in team"
 	case errors.Is(err, domain.ErrReviewerInactive):
 		return http.StatusConflict, ErrCodeNoCandidate, "reviewer inactive"
+	case errors.Is(err, domain.ErrReviewerRequired):
+		return http.StatusConflict, ErrCodeRequired, "reviewer is required, set override_required to replace"
+	case errors.Is(err, domain.ErrNoSeniorReviewer):
+		return http.StatusConflict, ErrCodeNoSenior, "team policy requires a senior reviewer, none available"
 	default:
 		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
 	}
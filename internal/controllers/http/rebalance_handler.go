@@ -0,0 +1,95 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type RebalanceHandler struct {
+	logger            *slog.Logger
+	getTeamUseCase    *usecases.GetTeamUseCase
+	enqueueJobUseCase *usecases.EnqueueJobUseCase
+}
+
+func NewRebalanceHandler(
+	logger *slog.Logger,
+	getTeamUseCase *usecases.GetTeamUseCase,
+	enqueueJobUseCase *usecases.EnqueueJobUseCase,
+) *RebalanceHandler {
+	return &RebalanceHandler{
+		logger:            logger,
+		getTeamUseCase:    getTeamUseCase,
+		enqueueJobUseCase: enqueueJobUseCase,
+	}
+}
+
+// RebalanceTeam ставит выравнивание нагрузки ревью внутри команды в очередь
+func (h *RebalanceHandler) RebalanceTeam(w http.ResponseWriter, r *http.Request) {
+	var input dto.RebalanceTeamInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.WarnContext(r.Context(), "ошибка декодирования запроса", "error", err)
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "неверный формат запроса")
+		return
+	}
+
+	if input.TeamName == "" {
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "team_name обязателен")
+		return
+	}
+
+	if _, err := h.getTeamUseCase.Get(r.Context(), input.TeamName); err != nil {
+		status, code, message := mapRebalanceError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	job, err := h.enqueueJobUseCase.Enqueue(r.Context(), domain.JobTypeTeamRebalance, usecases.TeamRebalanceParams{
+		TeamName: input.TeamName,
+		DryRun:   input.DryRun,
+	})
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "ошибка постановки ребалансировки", "error", err, "team", input.TeamName)
+		respondError(h.logger, w, http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError)
+		return
+	}
+
+	respondJobAccepted(h.logger, w, job)
+}
+
+func toRebalanceOutput(result usecases.RebalanceResult) dto.RebalanceTeamOutput {
+	output := dto.RebalanceTeamOutput{
+		DryRun: result.DryRun,
+		Moves:  make([]dto.ReviewMove, 0, len(result.Moves)),
+		Loads:  make([]dto.MemberLoad, 0, len(result.Loads)),
+	}
+	for _, move := range result.Moves {
+		output.Moves = append(output.Moves, dto.ReviewMove{
+			PullRequestID: move.PullRequestID,
+			FromUserID:    move.FromUserID,
+			ToUserID:      move.ToUserID,
+		})
+	}
+	for _, load := range result.Loads {
+		output.Loads = append(output.Loads, dto.MemberLoad{
+			UserID: load.UserID,
+			Before: load.Before,
+			After:  load.After,
+		})
+	}
+	return output
+}
+
+func mapRebalanceError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrTeamNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "team not found"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
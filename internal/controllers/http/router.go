@@ -16,15 +16,41 @@ type RouterConfig struct {
 	AdminToken string
 	UserToken  string
 
-	AddTeamUseCase             *usecases.CreateTeamUseCase
-	GetTeamUseCase             *usecases.GetTeamUseCase
-	SetUserActiveUseCase       *usecases.SetUserActiveUseCase
-	CreatePullRequestUseCase   *usecases.CreatePullRequestUseCase
-	MergePullRequestUseCase    *usecases.MergePullRequestUseCase
-	ReassignReviewerUseCase    *usecases.ReassignReviewerUseCase
-	GetReviewerPRsUseCase      *usecases.GetReviewerPullRequestsUseCase
-	GetStatsUseCase            *usecases.GetStatsUseCase
-	DeactivateTeamUsersUseCase *usecases.DeactivateTeamUsersUseCase
+	// GitHubWebhookSecret общий секрет подписи вебхуков GitHub
+	GitHubWebhookSecret string
+	// GitLabWebhookToken секретный токен вебхуков GitLab
+	GitLabWebhookToken string
+
+	AddTeamUseCase           *usecases.CreateTeamUseCase
+	GetTeamUseCase           *usecases.GetTeamUseCase
+	UpdateTeamUseCase        *usecases.UpdateTeamUseCase
+	SetUserActiveUseCase     *usecases.SetUserActiveUseCase
+	SetUserDigestUseCase     *usecases.SetUserDigestUseCase
+	SetUserAbsenceUseCase    *usecases.SetUserAbsenceUseCase
+	CreatePullRequestUseCase *usecases.CreatePullRequestUseCase
+	MergePullRequestUseCase  *usecases.MergePullRequestUseCase
+	ReassignReviewerUseCase  *usecases.ReassignReviewerUseCase
+	GetReviewerPRsUseCase    *usecases.GetReviewerPullRequestsUseCase
+	GetStatsUseCase          *usecases.GetStatsUseCase
+	RevertOperationUseCase   *usecases.RevertOperationUseCase
+	EnqueueJobUseCase        *usecases.EnqueueJobUseCase
+	GetJobUseCase            *usecases.GetJobUseCase
+	ListAuditEventsUseCase   *usecases.ListAuditEventsUseCase
+	GetPRHistoryUseCase      *usecases.GetPullRequestHistoryUseCase
+
+	CreateWebhookUseCase         *usecases.CreateWebhookUseCase
+	ListWebhooksUseCase          *usecases.ListWebhooksUseCase
+	DeleteWebhookUseCase         *usecases.DeleteWebhookUseCase
+	ListWebhookDeliveriesUseCase *usecases.ListWebhookDeliveriesUseCase
+
+	SyncPullRequestUseCase *usecases.SyncPullRequestUseCase
+
+	CreateUserIdentityUseCase *usecases.CreateUserIdentityUseCase
+	ListUserIdentitiesUseCase *usecases.ListUserIdentitiesUseCase
+	UpdateUserIdentityUseCase *usecases.UpdateUserIdentityUseCase
+	DeleteUserIdentityUseCase *usecases.DeleteUserIdentityUseCase
+
+	EventStream *usecases.EventStream
 }
 
 func NewRouter(cfg RouterConfig) http.Handler {
@@ -40,21 +66,49 @@ func NewRouter(cfg RouterConfig) http.Handler {
 	r.Get("/swagger", ServeSwaggerUI)
 	r.Get("/openapi.yml", ServeOpenAPISpec)
 
-	teamHandler := NewTeamHandler(cfg.Logger, cfg.AddTeamUseCase, cfg.GetTeamUseCase)
-	prHandler := NewPullRequestHandler(cfg.Logger, cfg.CreatePullRequestUseCase, cfg.MergePullRequestUseCase, cfg.ReassignReviewerUseCase)
-	userHandler := NewUserHandler(cfg.Logger, cfg.SetUserActiveUseCase, cfg.GetReviewerPRsUseCase)
+	teamHandler := NewTeamHandler(cfg.Logger, cfg.AddTeamUseCase, cfg.GetTeamUseCase, cfg.UpdateTeamUseCase)
+	prHandler := NewPullRequestHandler(cfg.Logger, cfg.CreatePullRequestUseCase, cfg.MergePullRequestUseCase, cfg.ReassignReviewerUseCase, cfg.GetPRHistoryUseCase)
+	userHandler := NewUserHandler(cfg.Logger, cfg.SetUserActiveUseCase, cfg.SetUserDigestUseCase, cfg.SetUserAbsenceUseCase, cfg.GetReviewerPRsUseCase)
 	statsHandler := NewStatsHandler(cfg.Logger, cfg.GetStatsUseCase)
-	deactivateHandler := NewDeactivateHandler(cfg.Logger, cfg.DeactivateTeamUsersUseCase)
+	deactivateHandler := NewDeactivateHandler(cfg.Logger, cfg.GetTeamUseCase, cfg.EnqueueJobUseCase)
+	rebalanceHandler := NewRebalanceHandler(cfg.Logger, cfg.GetTeamUseCase, cfg.EnqueueJobUseCase)
+	operationHandler := NewOperationHandler(cfg.Logger, cfg.RevertOperationUseCase)
+	jobHandler := NewJobHandler(cfg.Logger, cfg.GetJobUseCase)
+	auditHandler := NewAuditHandler(cfg.Logger, cfg.ListAuditEventsUseCase)
+	identityHandler := NewIdentityHandler(cfg.Logger, cfg.CreateUserIdentityUseCase, cfg.ListUserIdentitiesUseCase, cfg.UpdateUserIdentityUseCase, cfg.DeleteUserIdentityUseCase)
+	webhookHandler := NewWebhookHandler(cfg.Logger, cfg.CreateWebhookUseCase, cfg.ListWebhooksUseCase, cfg.DeleteWebhookUseCase, cfg.ListWebhookDeliveriesUseCase)
+	eventStreamHandler := NewEventStreamHandler(cfg.Logger, cfg.EventStream)
+
+	githubHandler := NewGitHubHandler(cfg.Logger, cfg.GitHubWebhookSecret, cfg.SyncPullRequestUseCase)
+	gitlabHandler := NewGitLabHandler(cfg.Logger, cfg.GitLabWebhookToken, cfg.SyncPullRequestUseCase)
+
+	// Интеграции проверяют подпись или токен сами, токены API им не нужны
+	r.Post("/integrations/github/webhook", githubHandler.Webhook)
+	r.Post("/integrations/gitlab/webhook", gitlabHandler.Webhook)
 
 	r.Group(func(admin chi.Router) {
 		admin.Use(adminAuth(cfg.Logger, cfg.AdminToken))
 
 		admin.Post("/team/add", teamHandler.AddTeam)
+		admin.Post("/team/update", teamHandler.UpdateTeam)
 		admin.Post("/team/deactivateUsers", deactivateHandler.DeactivateTeamUsers)
+		admin.Post("/team/rebalance", rebalanceHandler.RebalanceTeam)
 		admin.Post("/pullRequest/create", prHandler.Create)
 		admin.Post("/pullRequest/merge", prHandler.Merge)
 		admin.Post("/pullRequest/reassign", prHandler.Reassign)
 		admin.Post("/users/setIsActive", userHandler.SetActive)
+		admin.Post("/users/setAbsence", userHandler.SetAbsence)
+		admin.Post("/users/identities", identityHandler.Create)
+		admin.Get("/users/identities", identityHandler.List)
+		admin.Patch("/users/identities/{id}", identityHandler.Update)
+		admin.Delete("/users/identities/{id}", identityHandler.Delete)
+		admin.Post("/operations/{id}/revert", operationHandler.Revert)
+		admin.Get("/jobs/{id}", jobHandler.Get)
+		admin.Get("/audit", auditHandler.List)
+		admin.Post("/webhooks", webhookHandler.Create)
+		admin.Get("/webhooks", webhookHandler.List)
+		admin.Delete("/webhooks/{id}", webhookHandler.Delete)
+		admin.Get("/webhooks/{id}/deliveries", webhookHandler.Deliveries)
 	})
 
 	r.Group(func(user chi.Router) {
@@ -62,7 +116,11 @@ func NewRouter(cfg RouterConfig) http.Handler {
 
 		user.Get("/team/get", teamHandler.GetTeam)
 		user.Get("/users/getReview", userHandler.GetReviews)
+		// подписку на дайджест пользователи меняют сами, админ для этого не нужен
+		user.Post("/users/setDigest", userHandler.SetDigest)
+		user.Get("/pullRequest/history", prHandler.History)
 		user.Get("/stats", statsHandler.GetStats)
+		user.Get("/events/stream", eventStreamHandler.Stream)
 	})
 
 	return r
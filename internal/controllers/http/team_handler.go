@@ -12,16 +12,23 @@ import (
 )
 
 type TeamHandler struct {
-	logger    *slog.Logger
-	addTeamUC *usecases.CreateTeamUseCase
-	getTeamUC *usecases.GetTeamUseCase
+	logger       *slog.Logger
+	addTeamUC    *usecases.CreateTeamUseCase
+	getTeamUC    *usecases.GetTeamUseCase
+	updateTeamUC *usecases.UpdateTeamUseCase
 }
 
-func NewTeamHandler(logger *slog.Logger, addTeamUC *usecases.CreateTeamUseCase, getTeamUC *usecases.GetTeamUseCase) *TeamHandler {
+func NewTeamHandler(
+	logger *slog.Logger,
+	addTeamUC *usecases.CreateTeamUseCase,
+	getTeamUC *usecases.GetTeamUseCase,
+	updateTeamUC *usecases.UpdateTeamUseCase,
+) *TeamHandler {
 	return &TeamHandler{
-		logger:    logger,
-		addTeamUC: addTeamUC,
-		getTeamUC: getTeamUC,
+		logger:       logger,
+		addTeamUC:    addTeamUC,
+		getTeamUC:    getTeamUC,
+		updateTeamUC: updateTeamUC,
 	}
 }
 
@@ -39,15 +46,20 @@ func (h *TeamHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
 	}
 
 	members := make([]domain.User, 0, len(body.Members))
+	settings := make(map[string]usecases.MemberUpdate, len(body.Members))
 	for _, member := range body.Members {
 		if member.UserID == "" || member.Username == "" {
 			respondBadRequest(h.logger, r, w, "BAD_REQUEST", "user_id и username обязательны", nil)
 			return
 		}
 		members = append(members, domain.NewUser(member.UserID, member.Username, body.TeamName, member.IsActive))
+		settings[member.UserID] = toMemberUpdate(member)
 	}
 
-	team, err := h.addTeamUC.Create(r.Context(), domain.NewTeam(body.TeamName, members))
+	newTeam := domain.NewTeam(body.TeamName, members)
+	newTeam.RequireSeniorReviewer = body.RequireSeniorReviewer
+
+	team, err := h.addTeamUC.Create(r.Context(), newTeam, settings)
 	if err != nil {
 		status, code, message := mapTeamError(err)
 		h.logger.ErrorContext(r.Context(), "ошибка создания команды", "error", err, "team_name", body.TeamName)
@@ -76,16 +88,88 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	respondJSON(h.logger, w, http.StatusOK, toTeam(team))
 }
 
+// UpdateTeam меняет политику команды, состав и настройки участников.
+func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	var body dto.UpdateTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "некорректный формат запроса", err)
+		return
+	}
+	if body.TeamName == "" {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "team_name обязателен", nil)
+		return
+	}
+
+	update := usecases.TeamUpdate{
+		RequireSeniorReviewer: body.RequireSeniorReviewer,
+		Members:               make(map[string]usecases.MemberUpdate, len(body.Members)),
+		AddMembers:            make([]usecases.NewMember, 0, len(body.AddMembers)),
+	}
+	for _, member := range body.AddMembers {
+		if member.UserID == "" || member.Username == "" {
+			respondBadRequest(h.logger, r, w, "BAD_REQUEST", "user_id и username обязательны", nil)
+			return
+		}
+		update.AddMembers = append(update.AddMembers, usecases.NewMember{
+			ID:       member.UserID,
+			Name:     member.Username,
+			IsActive: member.IsActive,
+			Settings: toMemberUpdate(member),
+		})
+	}
+	for _, member := range body.Members {
+		if member.UserID == "" {
+			respondBadRequest(h.logger, r, w, "BAD_REQUEST", "user_id обязателен", nil)
+			return
+		}
+		update.Members[member.UserID] = usecases.MemberUpdate{
+			Level:         member.Level,
+			Timezone:      member.Timezone,
+			WorkStartHour: member.WorkStartHour,
+			WorkEndHour:   member.WorkEndHour,
+			ReviewWeight:  member.ReviewWeight,
+		}
+	}
+
+	team, err := h.updateTeamUC.Update(r.Context(), body.TeamName, update)
+	if err != nil {
+		status, code, message := mapTeamError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка обновления команды", "error", err, "team_name", body.TeamName)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, map[string]dto.Team{"team": toTeam(team)})
+}
+
+// toMemberUpdate настройки участника из запроса; незаданные поля не меняются.
+func toMemberUpdate(member dto.TeamMember) usecases.MemberUpdate {
+	return usecases.MemberUpdate{
+		Level:         member.Level,
+		Timezone:      member.Timezone,
+		WorkStartHour: member.WorkStartHour,
+		WorkEndHour:   member.WorkEndHour,
+		ReviewWeight:  member.ReviewWeight,
+	}
+}
+
 func toTeam(team domain.Team) dto.Team {
 	result := dto.Team{
-		TeamName: team.Name,
-		Members:  make([]dto.TeamMember, 0, len(team.Users)),
+		TeamName:              team.Name,
+		Members:               make([]dto.TeamMember, 0, len(team.Users)),
+		RequireSeniorReviewer: team.RequireSeniorReviewer,
 	}
 	for _, user := range team.Users {
+		startHour, endHour, weight := user.WorkStartHour, user.WorkEndHour, user.ReviewWeight
 		result.Members = append(result.Members, dto.TeamMember{
-			UserID:   user.ID,
-			Username: user.Name,
-			IsActive: user.IsActive,
+			UserID:        user.ID,
+			Username:      user.Name,
+			IsActive:      user.IsActive,
+			Level:         user.Level,
+			Timezone:      user.Timezone,
+			WorkStartHour: &startHour,
+			WorkEndHour:   &endHour,
+			ReviewWeight:  &weight,
 		})
 	}
 	return result
@@ -95,6 +179,16 @@ func mapTeamError(err error) (int, string, string) {
 	switch {
 	case errors.Is(err, domain.ErrTeamExists):
 		return http.StatusBadRequest, ErrCodeTeamExists, "team_name already exists"
+	case errors.Is(err, domain.ErrTeamNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "team not found"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "user is not a team member"
+	case errors.Is(err, domain.ErrInvalidLevel):
+		return http.StatusBadRequest, "BAD_REQUEST", "level must be junior, middle or senior"
+	case errors.Is(err, domain.ErrInvalidReviewWeight):
+		return http.StatusBadRequest, "BAD_REQUEST", "review_weight must be non-negative"
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		return http.StatusBadRequest, "BAD_REQUEST", "invalid timezone or working hours"
 	default:
 		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
 	}
@@ -14,17 +14,23 @@ import (
 type UserHandler struct {
 	logger            *slog.Logger
 	setActiveUseCase  *usecases.SetUserActiveUseCase
+	setDigestUseCase  *usecases.SetUserDigestUseCase
+	setAbsenceUseCase *usecases.SetUserAbsenceUseCase
 	getReviewsUseCase *usecases.GetReviewerPullRequestsUseCase
 }
 
 func NewUserHandler(
 	logger *slog.Logger,
 	setActiveUseCase *usecases.SetUserActiveUseCase,
+	setDigestUseCase *usecases.SetUserDigestUseCase,
+	setAbsenceUseCase *usecases.SetUserAbsenceUseCase,
 	getReviewsUseCase *usecases.GetReviewerPullRequestsUseCase,
 ) *UserHandler {
 	return &UserHandler{
 		logger:            logger,
 		setActiveUseCase:  setActiveUseCase,
+		setDigestUseCase:  setDigestUseCase,
+		setAbsenceUseCase: setAbsenceUseCase,
 		getReviewsUseCase: getReviewsUseCase,
 	}
 }
@@ -52,6 +58,52 @@ func (h *UserHandler) SetActive(w http.ResponseWriter, r *http.Request) {
 	respondJSON(h.logger, w, http.StatusOK, map[string]dto.User{"user": toUser(user)})
 }
 
+// SetDigest подписывает пользователя на ежедневный дайджест ревью или отписывает от него.
+func (h *UserHandler) SetDigest(w http.ResponseWriter, r *http.Request) {
+	var body dto.SetUserDigestRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "некорректный формат запроса", err)
+		return
+	}
+	if body.UserID == "" {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "user_id обязателен", nil)
+		return
+	}
+
+	user, err := h.setDigestUseCase.SetDigest(r.Context(), body.UserID, body.Enabled)
+	if err != nil {
+		status, code, message := mapUserError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка изменения подписки на дайджест", "error", err, "user_id", body.UserID)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, map[string]dto.User{"user": toUser(user)})
+}
+
+// SetAbsence планирует отсутствие пользователя или снимает его.
+func (h *UserHandler) SetAbsence(w http.ResponseWriter, r *http.Request) {
+	var body dto.SetUserAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "некорректный формат запроса", err)
+		return
+	}
+	if body.UserID == "" {
+		respondBadRequest(h.logger, r, w, "BAD_REQUEST", "user_id обязателен", nil)
+		return
+	}
+
+	user, err := h.setAbsenceUseCase.SetAbsence(r.Context(), body.UserID, body.AbsentFrom, body.AbsentUntil)
+	if err != nil {
+		status, code, message := mapUserError(err)
+		h.logger.ErrorContext(r.Context(), "ошибка изменения отсутствия пользователя", "error", err, "user_id", body.UserID)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, map[string]dto.User{"user": toUser(user)})
+}
+
 // GetReviews возвращает PR, где пользователь ревьювер.
 func (h *UserHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
@@ -89,6 +141,12 @@ func toUser(user domain.User) dto.User {
 		Username: user.Name,
 		TeamName: user.TeamName,
 		IsActive: user.IsActive,
+		Level:    user.Level,
+		Timezone: user.Timezone,
+
+		DigestEnabled: user.DigestEnabled,
+		AbsentFrom:    user.AbsentFrom,
+		AbsentUntil:   user.AbsentUntil,
 	}
 }
 
@@ -96,6 +154,8 @@ func mapUserError(err error) (int, string, string) {
 	switch {
 	case errors.Is(err, domain.ErrUserNotFound):
 		return http.StatusNotFound, ErrCodeNotFound, "user not found"
+	case errors.Is(err, domain.ErrInvalidAbsence):
+		return http.StatusBadRequest, "BAD_REQUEST", "absent_from and absent_until must be set together, from before until"
 	default:
 		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
 	}
@@ -0,0 +1,148 @@
+package httpcontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/usecases"
+)
+
+type WebhookHandler struct {
+	logger                       *slog.Logger
+	createWebhookUseCase         *usecases.CreateWebhookUseCase
+	listWebhooksUseCase          *usecases.ListWebhooksUseCase
+	deleteWebhookUseCase         *usecases.DeleteWebhookUseCase
+	listWebhookDeliveriesUseCase *usecases.ListWebhookDeliveriesUseCase
+}
+
+func NewWebhookHandler(
+	logger *slog.Logger,
+	createWebhookUseCase *usecases.CreateWebhookUseCase,
+	listWebhooksUseCase *usecases.ListWebhooksUseCase,
+	deleteWebhookUseCase *usecases.DeleteWebhookUseCase,
+	listWebhookDeliveriesUseCase *usecases.ListWebhookDeliveriesUseCase,
+) *WebhookHandler {
+	return &WebhookHandler{
+		logger:                       logger,
+		createWebhookUseCase:         createWebhookUseCase,
+		listWebhooksUseCase:          listWebhooksUseCase,
+		deleteWebhookUseCase:         deleteWebhookUseCase,
+		listWebhookDeliveriesUseCase: listWebhookDeliveriesUseCase,
+	}
+}
+
+// Create регистрирует подписку на доменные события
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input dto.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.WarnContext(r.Context(), "ошибка декодирования запроса", "error", err)
+		respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "неверный формат запроса")
+		return
+	}
+
+	subscription, err := h.createWebhookUseCase.Create(r.Context(), input.URL, input.Secret, input.EventTypes)
+	if err != nil {
+		status, code, message := mapWebhookError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	respondJSON(h.logger, w, http.StatusCreated, toWebhook(subscription))
+}
+
+// List возвращает подписки без секретов
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.listWebhooksUseCase.List(r.Context())
+	if err != nil {
+		status, code, message := mapWebhookError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output := dto.WebhooksOutput{Webhooks: make([]dto.Webhook, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		output.Webhooks = append(output.Webhooks, toWebhook(subscription))
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+// Delete удаляет подписку
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.deleteWebhookUseCase.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		status, code, message := mapWebhookError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries возвращает журнал доставок подписки
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit <= 0 {
+			respondError(h.logger, w, http.StatusBadRequest, "INVALID_INPUT", "limit должен быть положительным числом")
+			return
+		}
+	}
+
+	deliveries, err := h.listWebhookDeliveriesUseCase.List(r.Context(), chi.URLParam(r, "id"), limit)
+	if err != nil {
+		status, code, message := mapWebhookError(err)
+		respondError(h.logger, w, status, code, message)
+		return
+	}
+
+	output := dto.WebhookDeliveriesOutput{Deliveries: make([]dto.WebhookDelivery, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		item := dto.WebhookDelivery{
+			ID:             delivery.ID,
+			MessageID:      delivery.MessageID,
+			EventType:      delivery.EventType,
+			Payload:        json.RawMessage(delivery.Payload),
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt,
+			DeliveredAt:    delivery.DeliveredAt,
+		}
+		if delivery.Status == domain.WebhookDeliveryPending {
+			nextAttemptAt := delivery.NextAttemptAt
+			item.NextAttemptAt = &nextAttemptAt
+		}
+		output.Deliveries = append(output.Deliveries, item)
+	}
+
+	respondJSON(h.logger, w, http.StatusOK, output)
+}
+
+func toWebhook(subscription domain.WebhookSubscription) dto.Webhook {
+	return dto.Webhook{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func mapWebhookError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidWebhook):
+		return http.StatusBadRequest, "INVALID_INPUT", "invalid webhook"
+	case errors.Is(err, domain.ErrWebhookNotFound):
+		return http.StatusNotFound, ErrCodeNotFound, "webhook not found"
+	default:
+		return http.StatusInternalServerError, ErrCodeInternal, ErrMsgInternalError
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// ActorSystem автор изменений, сделанных без запроса пользователя.
+const ActorSystem = "system"
+
+// Типы сущностей в журнале аудита
+const (
+	AuditEntityTeam        = "team"
+	AuditEntityUser        = "user"
+	AuditEntityPullRequest = "pull_request"
+	AuditEntityOperation   = "operation"
+	AuditEntityWebhook     = "webhook"
+)
+
+// Действия в журнале аудита
+const (
+	AuditTeamCreated         = "team.created"
+	AuditTeamUpdated         = "team.updated"
+	AuditUserCreated         = "user.created"
+	AuditUserUpdated         = "user.updated"
+	AuditUserActivated       = "user.activated"
+	AuditUserDeactivated     = "user.deactivated"
+	AuditUserDigestUpdated   = "user.digest_updated"
+	AuditUserAbsenceUpdated  = "user.absence_updated"
+	AuditPullRequestCreated  = "pull_request.created"
+	AuditPullRequestMerged   = "pull_request.merged"
+	AuditPullRequestDrafted  = "pull_request.converted_to_draft"
+	AuditPullRequestReady    = "pull_request.ready_for_review"
+	AuditPullRequestClosed   = "pull_request.closed"
+	AuditPullRequestReopened = "pull_request.reopened"
+	AuditReviewerReassigned  = "pull_request.reviewer_reassigned"
+	AuditReviewersReplaced   = "pull_request.reviewers_replaced"
+	AuditReviewersToppedUp   = "pull_request.reviewers_topped_up"
+	AuditReviewersRebalanced = "pull_request.reviewers_rebalanced"
+	AuditReviewersRestored   = "pull_request.reviewers_restored"
+	AuditOperationReverted   = "operation.reverted"
+	AuditIdentityAdded       = "user.identity_added"
+	AuditIdentityUpdated     = "user.identity_updated"
+	AuditIdentityRemoved     = "user.identity_removed"
+	AuditWebhookCreated      = "webhook.created"
+	AuditWebhookDeleted      = "webhook.deleted"
+	// AuditReviewRequestFailed хостинг так и не принял запрос ревью; пишется от ActorSystem
+	AuditReviewRequestFailed = "pull_request.review_request_failed"
+)
+
+// AuditEvent запись журнала аудита: кто, что и с какой сущностью сделал.
+// Before и After - JSON состояния сущности, nil для создания и удаления.
+type AuditEvent struct {
+	ID         int64
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     []byte
+	After      []byte
+	CreatedAt  time.Time
+}
+
+// AuditFilter условия выборки журнала аудита; пустые поля не ограничивают.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
@@ -3,17 +3,38 @@ package domain
 import "errors"
 
 var (
-	ErrTeamExists           = errors.New("команда уже существует")
-	ErrTeamNotFound         = errors.New("команда не найдена")
-	ErrPullRequestExists    = errors.New("pull request уже существует")
-	ErrPullRequestNotFound  = errors.New("pull request не найден")
-	ErrPullRequestMerged    = errors.New("pull request в статусе merged")
-	ErrNoReviewerCandidates = errors.New("нет доступных кандидатов в ревьюеры")
-	ErrReviewerAlreadyAdded = errors.New("ревьюер уже назначен")
-	ErrReviewerInactive     = errors.New("ревьюер неактивен")
-	ErrReviewerNotInTeam    = errors.New("ревьюер не в команде автора")
-	ErrReviewerIsAuthor     = errors.New("автор не может быть ревьюером")
-	ErrReviewerLimitReached = errors.New("достигнут лимит ревьюеров")
-	ErrReviewerNotAssigned  = errors.New("ревьюер не назначен")
-	ErrUserNotFound         = errors.New("пользователь не найден")
+	ErrTeamExists            = errors.New("команда уже существует")
+	ErrTeamNotFound          = errors.New("команда не найдена")
+	ErrPullRequestExists     = errors.New("pull request уже существует")
+	ErrPullRequestNotFound   = errors.New("pull request не найден")
+	ErrPullRequestMerged     = errors.New("pull request в статусе merged")
+	ErrPullRequestClosed     = errors.New("pull request закрыт")
+	ErrNoReviewerCandidates  = errors.New("нет доступных кандидатов в ревьюеры")
+	ErrReviewerAlreadyAdded  = errors.New("ревьюер уже назначен")
+	ErrReviewerInactive      = errors.New("ревьюер неактивен")
+	ErrReviewerNotInTeam     = errors.New("ревьюер не в команде автора")
+	ErrReviewerIsAuthor      = errors.New("автор не может быть ревьюером")
+	ErrReviewerLimitReached  = errors.New("достигнут лимит ревьюеров")
+	ErrReviewerNotAssigned   = errors.New("ревьюер не назначен")
+	ErrReviewerRequired      = errors.New("обязательного ревьюера нельзя заменить без подтверждения")
+	ErrUserNotFound          = errors.New("пользователь не найден")
+	ErrInvalidLevel          = errors.New("неизвестный уровень пользователя")
+	ErrInvalidReviewWeight   = errors.New("вес ревью должен быть неотрицательным")
+	ErrInvalidSchedule       = errors.New("некорректный часовой пояс или рабочие часы")
+	ErrInvalidAbsence        = errors.New("отсутствие должно начинаться раньше, чем заканчивается")
+	ErrInvalidUserFilter     = errors.New("нельзя одновременно указать user_ids и except_user_ids")
+	ErrOperationNotFound     = errors.New("операция не найдена")
+	ErrOperationReverted     = errors.New("операция уже откачена")
+	ErrJobNotFound           = errors.New("задача не найдена")
+	ErrJobLeaseLost          = errors.New("задачу перехватил другой обработчик")
+	ErrInvalidAuditFilter    = errors.New("некорректный фильтр журнала аудита")
+	ErrNoSeniorReviewer      = errors.New("политика команды требует senior-ревьюера, но доступных нет")
+	ErrInvalidWebhook        = errors.New("некорректная подписка на вебхук")
+	ErrWebhookNotFound       = errors.New("подписка на вебхук не найдена")
+	ErrReviewRequestRejected = errors.New("хостинг отклонил запрос ревью")
+	ErrInvalidIdentity       = errors.New("некорректная внешняя учётная запись")
+	ErrIdentityNotFound      = errors.New("внешняя учётная запись не найдена")
+	ErrIdentityExists        = errors.New("внешняя учётная запись уже привязана")
+	ErrEventStreamClosed     = errors.New("поток событий остановлен")
+	ErrEventNotFound         = errors.New("события нет в журнале")
 )
@@ -0,0 +1,127 @@
+package domain
+
+import "time"
+
+// Типы доменных событий
+const (
+	EventPullRequestCreated = "pull_request.created"
+	EventPullRequestMerged  = "pull_request.merged"
+	EventReviewerAssigned   = "pull_request.reviewer_assigned"
+	EventReviewerReplaced   = "pull_request.reviewer_replaced"
+	EventReviewerRemoved    = "pull_request.reviewer_removed"
+	EventUserActivated      = "user.activated"
+	EventUserDeactivated    = "user.deactivated"
+)
+
+// EventTypes все типы доменных событий
+var EventTypes = []string{
+	EventPullRequestCreated,
+	EventPullRequestMerged,
+	EventReviewerAssigned,
+	EventReviewerReplaced,
+	EventReviewerRemoved,
+	EventUserActivated,
+	EventUserDeactivated,
+}
+
+// Event доменное событие; сериализуется в JSON и доставляется подписчикам через outbox.
+type Event interface {
+	EventType() string
+	// AggregateID id сущности, к которой относится событие
+	AggregateID() string
+}
+
+type PullRequestCreated struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Title         string    `json:"title"`
+	AuthorID      string    `json:"author_id"`
+	TeamName      string    `json:"team_name"`
+	Reviewers     []string  `json:"reviewers"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (e PullRequestCreated) EventType() string   { return EventPullRequestCreated }
+func (e PullRequestCreated) AggregateID() string { return e.PullRequestID }
+
+type PullRequestMerged struct {
+	PullRequestID string    `json:"pull_request_id"`
+	AuthorID      string    `json:"author_id"`
+	TeamName      string    `json:"team_name"`
+	MergedAt      time.Time `json:"merged_at"`
+}
+
+func (e PullRequestMerged) EventType() string   { return EventPullRequestMerged }
+func (e PullRequestMerged) AggregateID() string { return e.PullRequestID }
+
+// ReviewerAssigned ревьюер добавлен в PR; Reason - причина из истории PR.
+type ReviewerAssigned struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+func (e ReviewerAssigned) EventType() string   { return EventReviewerAssigned }
+func (e ReviewerAssigned) AggregateID() string { return e.PullRequestID }
+
+type ReviewerReplaced struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+func (e ReviewerReplaced) EventType() string   { return EventReviewerReplaced }
+func (e ReviewerReplaced) AggregateID() string { return e.PullRequestID }
+
+// ReviewerRemoved ревьюер снят с PR без замены.
+type ReviewerRemoved struct {
+	PullRequestID string `json:"pull_request_id"`
+	TeamName      string `json:"team_name"`
+	ReviewerID    string `json:"reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+func (e ReviewerRemoved) EventType() string   { return EventReviewerRemoved }
+func (e ReviewerRemoved) AggregateID() string { return e.PullRequestID }
+
+type UserActivated struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+func (e UserActivated) EventType() string   { return EventUserActivated }
+func (e UserActivated) AggregateID() string { return e.UserID }
+
+type UserDeactivated struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+func (e UserDeactivated) EventType() string   { return EventUserDeactivated }
+func (e UserDeactivated) AggregateID() string { return e.UserID }
+
+// EventFilter отбор событий для потока; пустое поле не ограничивает.
+type EventFilter struct {
+	TeamName string
+	// UserID автор, ревьюер или пользователь, к которому относится событие
+	UserID string
+}
+
+// OutboxMessage событие в outbox: записано вместе с изменением и ждёт доставки.
+type OutboxMessage struct {
+	ID          int64
+	EventType   string
+	AggregateID string
+	Payload     []byte
+	Actor       string
+	CreatedAt   time.Time
+
+	// Attempts сколько раз сообщение забирали на доставку
+	Attempts int
+	// NextAttemptAt раньше этого времени сообщение не доставляется
+	NextAttemptAt time.Time
+	LastError     string
+	DeliveredAt   *time.Time
+}
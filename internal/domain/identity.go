@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Внешние системы, в которых у пользователя есть учётная запись
+const (
+	IdentityProviderGitHub     = "github"
+	IdentityProviderGitLab     = "gitlab"
+	IdentityProviderSlack      = "slack"
+	IdentityProviderMattermost = "mattermost"
+	IdentityProviderEmail      = "email"
+)
+
+// IdentityProviders все поддерживаемые внешние системы
+var IdentityProviders = []string{
+	IdentityProviderGitHub,
+	IdentityProviderGitLab,
+	IdentityProviderSlack,
+	IdentityProviderMattermost,
+	IdentityProviderEmail,
+}
+
+// UserIdentity учётная запись пользователя во внешней системе: логин GitHub, имя в GitLab, Slack или Mattermost, email.
+// У пользователя не больше одной записи на систему, а Handle уникален в системе без учёта регистра.
+type UserIdentity struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	Provider  string    `db:"provider" json:"provider"`
+	Handle    string    `db:"handle" json:"handle"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+func NewUserIdentity(id, userID, provider, handle string, createdAt time.Time) (UserIdentity, error) {
+	handle = strings.TrimSpace(handle)
+	if userID == "" || handle == "" || !slices.Contains(IdentityProviders, provider) {
+		return UserIdentity{}, ErrInvalidIdentity
+	}
+	if provider == IdentityProviderEmail {
+		address, err := mail.ParseAddress(handle)
+		if err != nil || address.Address != handle {
+			return UserIdentity{}, ErrInvalidIdentity
+		}
+	}
+
+	return UserIdentity{
+		ID:        id,
+		UserID:    userID,
+		Provider:  provider,
+		Handle:    handle,
+		CreatedAt: createdAt,
+	}, nil
+}
+
+// UserIdentityFilter пустые поля не фильтруют.
+type UserIdentityFilter struct {
+	UserID   string
+	Provider string
+}
@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+// Типы фоновых задач
+const (
+	JobTypeTeamDeactivation = "TEAM_DEACTIVATION"
+	JobTypeTeamRebalance    = "TEAM_REBALANCE"
+)
+
+// Статусы фоновых задач
+const (
+	JobStatusPending   = "PENDING"
+	JobStatusRunning   = "RUNNING"
+	JobStatusSucceeded = "SUCCEEDED"
+	JobStatusFailed    = "FAILED"
+)
+
+// Job фоновая задача: параметры запуска, прогресс и результат в JSON.
+type Job struct {
+	ID   string
+	Type string
+	// Actor кто поставил задачу; от его имени изменения попадают в аудит
+	Actor    string
+	Status   string
+	Payload  []byte
+	Result   []byte
+	Error    string
+	Done     int
+	Total    int
+	Attempts int
+
+	CreatedAt time.Time
+	StartedAt *time.Time
+	// HeartbeatAt обновляется при каждом сохранении прогресса; по нему находят
+	// задачи, обработчик которых упал
+	HeartbeatAt *time.Time
+	FinishedAt  *time.Time
+}
+
+func NewJob(id, jobType, actor string, payload []byte, createdAt time.Time) Job {
+	return Job{
+		ID:        id,
+		Type:      jobType,
+		Actor:     actor,
+		Status:    JobStatusPending,
+		Payload:   payload,
+		CreatedAt: createdAt,
+	}
+}
+
+// Finished показывает, что задача завершена успешно или с ошибкой.
+func (j Job) Finished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed
+}
+
+// SetProgress сохраняет прогресс выполнения.
+func (j *Job) SetProgress(done, total int, now time.Time) {
+	j.Done = done
+	j.Total = total
+	j.HeartbeatAt = &now
+}
+
+// Succeed завершает задачу с результатом.
+func (j *Job) Succeed(result []byte, now time.Time) {
+	j.Status = JobStatusSucceeded
+	j.Result = result
+	j.Error = ""
+	j.HeartbeatAt = &now
+	j.FinishedAt = &now
+}
+
+// Fail завершает задачу с ошибкой.
+func (j *Job) Fail(message string, now time.Time) {
+	j.Status = JobStatusFailed
+	j.Error = message
+	j.HeartbeatAt = &now
+	j.FinishedAt = &now
+}
@@ -0,0 +1,87 @@
+package domain
+
+import "time"
+
+// Виды личных уведомлений
+const (
+	// NotificationAssigned пользователь назначен ревьюером
+	NotificationAssigned = "assigned"
+	// NotificationReassigned пользователь назначен вместо другого ревьюера
+	NotificationReassigned = "reassigned"
+	// NotificationUnassigned пользователь снят с ревью
+	NotificationUnassigned = "unassigned"
+	// NotificationOverdue ревью ждёт дольше допустимого
+	NotificationOverdue = "overdue"
+	// NotificationDigest ежедневная сводка ожидающих ревью
+	NotificationDigest = "digest"
+)
+
+// Возрастные группы PR в дайджесте, от старых к новым
+const (
+	// DigestAgeStale PR открыт больше трёх дней
+	DigestAgeStale = "stale"
+	// DigestAgeWaiting PR открыт от суток до трёх дней
+	DigestAgeWaiting = "waiting"
+	// DigestAgeFresh PR открыт меньше суток
+	DigestAgeFresh = "fresh"
+)
+
+// DigestAge возрастная группа PR, открытого age назад.
+func DigestAge(age time.Duration) string {
+	switch {
+	case age >= 72*time.Hour:
+		return DigestAgeStale
+	case age >= 24*time.Hour:
+		return DigestAgeWaiting
+	default:
+		return DigestAgeFresh
+	}
+}
+
+// DigestItem pull request в дайджесте; Age - сколько он открыт.
+type DigestItem struct {
+	PullRequestID string
+	Title         string
+	AuthorName    string
+	Age           time.Duration
+}
+
+// DigestGroup PR дайджеста из одной возрастной группы, самые старые первыми.
+type DigestGroup struct {
+	Age   string
+	Items []DigestItem
+}
+
+// Notification личное уведомление пользователю о pull request.
+// Имена уже подставлены, чтобы шаблонам каналов не ходить в хранилища.
+type Notification struct {
+	Kind          string
+	UserID        string
+	PullRequestID string
+	Title         string
+	AuthorName    string
+	// OtherReviewerName кого пользователь заменил (reassigned) или кто заменил его (unassigned)
+	OtherReviewerName string
+	// Waiting сколько ревью уже ждёт (для overdue)
+	Waiting time.Duration
+	// Digest группы PR ежедневной сводки (для digest)
+	Digest []DigestGroup
+}
+
+// NotificationDelivery уведомление о назначении, доставленное пользователю в канал по сообщению outbox.
+type NotificationDelivery struct {
+	MessageID   int64     `db:"message_id"`
+	UserID      string    `db:"user_id"`
+	Provider    string    `db:"provider"`
+	DeliveredAt time.Time `db:"delivered_at"`
+}
+
+// OverdueReview назначение ревьюера на открытый PR, которое ждёт дольше порога.
+// AssignedAt - время последнего назначения ревьюера на этот PR.
+type OverdueReview struct {
+	PullRequestID string    `db:"pr_id"`
+	Title         string    `db:"title"`
+	AuthorID      string    `db:"author_id"`
+	ReviewerID    string    `db:"reviewer_id"`
+	AssignedAt    time.Time `db:"assigned_at"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Типы операций
+const (
+	OperationTeamDeactivation = "TEAM_DEACTIVATION"
+)
+
+// Статусы операций
+const (
+	OperationStatusApplied  = "APPLIED"
+	OperationStatusReverted = "REVERTED"
+)
+
+// Operation массовое изменение с сохранённым исходным состоянием для отката.
+type Operation struct {
+	ID           string                `json:"id"`
+	Type         string                `json:"type"`
+	TeamName     string                `json:"team_name"`
+	Status       string                `json:"status"`
+	UserIDs      []string              `json:"user_ids"`
+	PullRequests []PullRequestSnapshot `json:"pull_requests"`
+	CreatedAt    time.Time             `json:"created_at"`
+	RevertedAt   *time.Time            `json:"reverted_at,omitempty"`
+}
+
+// PullRequestSnapshot ревьюеры PR до и после операции.
+type PullRequestSnapshot struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	ReviewersBefore []string `json:"reviewers_before"`
+	RequiredBefore  []string `json:"required_before"`
+	ReviewersAfter  []string `json:"reviewers_after"`
+}
+
+func NewOperation(id, operationType, teamName string, createdAt time.Time) Operation {
+	return Operation{
+		ID:        id,
+		Type:      operationType,
+		TeamName:  teamName,
+		Status:    OperationStatusApplied,
+		CreatedAt: createdAt,
+	}
+}
+
+// MarkReverted помечает операцию откаченной.
+func (o *Operation) MarkReverted(revertedAt time.Time) error {
+	if o.Status == OperationStatusReverted {
+		return ErrOperationReverted
+	}
+	o.Status = OperationStatusReverted
+	o.RevertedAt = &revertedAt
+	return nil
+}
+
+// Unchanged проверяет, что ревьюеры PR совпадают с состоянием сразу после операции.
+func (s PullRequestSnapshot) Unchanged(pr PullRequest) bool {
+	if len(pr.Reviewers) != len(s.ReviewersAfter) {
+		return false
+	}
+	for _, reviewerID := range s.ReviewersAfter {
+		if !pr.HasReviewer(reviewerID) {
+			return false
+		}
+	}
+	return true
+}
@@ -6,17 +6,26 @@ import "time"
 const (
 	PRStatusOpen   = "OPEN"
 	PRStatusMerged = "MERGED"
+	// PRStatusDraft ревью на паузе: ревьюеры остаются, но напоминания, дайджесты и дозаполнение PR не трогают
+	PRStatusDraft = "DRAFT"
+	// PRStatusClosed закрыт без merge; ведёт себя как черновик, пока PR не откроют снова
+	PRStatusClosed = "CLOSED"
 )
 
+// MaxReviewers сколько ревьюеров назначается на pull request.
+const MaxReviewers = 2
+
 type PullRequest struct {
-	ID        string
-	Title     string
-	AuthorID  string
-	TeamName  string
-	Reviewers []string
-	Status    string
-	CreatedAt time.Time
-	MergedAt  *time.Time
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	AuthorID  string   `json:"author_id"`
+	TeamName  string   `json:"team_name"`
+	Reviewers []string `json:"reviewers"`
+	// RequiredReviewers подмножество Reviewers, которое нельзя снять без явного подтверждения.
+	RequiredReviewers []string   `json:"required_reviewers"`
+	Status            string     `json:"status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
 }
 
 func NewPullRequest(id, title, authorID, teamName string, createdAt time.Time) PullRequest {
@@ -26,13 +35,59 @@ func NewPullRequest(id, title, authorID, teamName string, createdAt time.Time) P
 		AuthorID:  authorID,
 		TeamName:  teamName,
 		Status:    PRStatusOpen,
-		Reviewers: make([]string, 0, 2),
+		Reviewers: make([]string, 0, MaxReviewers),
 		CreatedAt: createdAt,
 	}
 }
 
+// AssignReviewers задаёт список ревьюеров; обязательные, которых нет в списке, снимаются.
 func (pr *PullRequest) AssignReviewers(reviewers []string) {
 	pr.Reviewers = reviewers
+
+	required := make([]string, 0, len(pr.RequiredReviewers))
+	for _, id := range pr.RequiredReviewers {
+		if pr.HasReviewer(id) {
+			required = append(required, id)
+		}
+	}
+	pr.RequiredReviewers = required
+}
+
+// AddRequiredReviewer назначает обязательного ревьюера по тем же правилам, что и AddReviewer.
+func (pr *PullRequest) AddRequiredReviewer(reviewerID string) error {
+	if err := pr.AddReviewer(reviewerID); err != nil {
+		return err
+	}
+	pr.RequiredReviewers = append(pr.RequiredReviewers, reviewerID)
+	return nil
+}
+
+// HasReviewer проверяет, назначен ли пользователь ревьюером.
+func (pr PullRequest) HasReviewer(reviewerID string) bool {
+	for _, existing := range pr.Reviewers {
+		if existing == reviewerID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRequiredReviewer проверяет, является ли ревьюер обязательным.
+func (pr PullRequest) IsRequiredReviewer(reviewerID string) bool {
+	for _, existing := range pr.RequiredReviewers {
+		if existing == reviewerID {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingReviewers сколько ревьюеров не хватает открытому pull request.
+func (pr PullRequest) MissingReviewers() int {
+	if pr.Status != PRStatusOpen || len(pr.Reviewers) >= MaxReviewers {
+		return 0
+	}
+	return MaxReviewers - len(pr.Reviewers)
 }
 
 func (pr *PullRequest) MarkMerged(mergedAt time.Time) {
@@ -46,6 +101,59 @@ func (pr *PullRequest) MarkMerged(mergedAt time.Time) {
 	pr.MergedAt = &mergedAt
 }
 
+// MarkDraft возвращает PR в черновик; повторный вызов ничего не меняет.
+func (pr *PullRequest) MarkDraft() error {
+	if err := pr.checkNotFinished(); err != nil {
+		return err
+	}
+	pr.Status = PRStatusDraft
+	return nil
+}
+
+// MarkReady возвращает черновик в ревью; повторный вызов ничего не меняет.
+func (pr *PullRequest) MarkReady() error {
+	if err := pr.checkNotFinished(); err != nil {
+		return err
+	}
+	pr.Status = PRStatusOpen
+	return nil
+}
+
+// MarkClosed закрывает PR без merge; ревьюеры остаются на случай повторного открытия.
+func (pr *PullRequest) MarkClosed() error {
+	if pr.Status == PRStatusMerged {
+		return ErrPullRequestMerged
+	}
+	pr.Status = PRStatusClosed
+	return nil
+}
+
+// Reopen снова открывает закрытый PR, черновиком или в ревью. Незакрытый PR не меняется.
+func (pr *PullRequest) Reopen(draft bool) error {
+	if pr.Status == PRStatusMerged {
+		return ErrPullRequestMerged
+	}
+	if pr.Status != PRStatusClosed {
+		return nil
+	}
+	pr.Status = PRStatusOpen
+	if draft {
+		pr.Status = PRStatusDraft
+	}
+	return nil
+}
+
+// checkNotFinished запрещает менять черновик у смерженного или закрытого PR.
+func (pr PullRequest) checkNotFinished() error {
+	switch pr.Status {
+	case PRStatusMerged:
+		return ErrPullRequestMerged
+	case PRStatusClosed:
+		return ErrPullRequestClosed
+	}
+	return nil
+}
+
 func (pr *PullRequest) AddReviewer(reviewerID string) error {
 	if pr.Status == PRStatusMerged {
 		return ErrPullRequestMerged
@@ -58,7 +166,7 @@ func (pr *PullRequest) AddReviewer(reviewerID string) error {
 			return ErrReviewerAlreadyAdded
 		}
 	}
-	if len(pr.Reviewers) >= 2 {
+	if len(pr.Reviewers) >= MaxReviewers {
 		return ErrReviewerLimitReached
 	}
 	pr.Reviewers = append(pr.Reviewers, reviewerID)
@@ -83,6 +191,7 @@ func (pr *PullRequest) ReplaceReviewer(oldReviewerID, newReviewerID string) erro
 	for i, existing := range pr.Reviewers {
 		if existing == oldReviewerID {
 			pr.Reviewers[i] = newReviewerID
+			pr.AssignReviewers(pr.Reviewers)
 			return nil
 		}
 	}
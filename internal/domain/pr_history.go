@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// События истории PR
+const (
+	PRHistoryCreated          = "CREATED"
+	PRHistoryReviewerAssigned = "REVIEWER_ASSIGNED"
+	PRHistoryReviewerReplaced = "REVIEWER_REPLACED"
+	PRHistoryReviewerRemoved  = "REVIEWER_REMOVED"
+	PRHistoryMerged           = "MERGED"
+	PRHistoryConvertedToDraft = "CONVERTED_TO_DRAFT"
+	PRHistoryReadyForReview   = "READY_FOR_REVIEW"
+	PRHistoryClosed           = "CLOSED"
+	PRHistoryReopened         = "REOPENED"
+)
+
+// Причины изменения ревьюеров в истории PR
+const (
+	PRHistoryReasonAuto         = "auto"
+	PRHistoryReasonRequired     = "required"
+	PRHistoryReasonManual       = "manual"
+	PRHistoryReasonDeactivation = "deactivation"
+	PRHistoryReasonTopUp        = "top_up"
+	PRHistoryReasonRebalance    = "rebalance"
+	PRHistoryReasonRevert       = "revert"
+)
+
+// PullRequestHistoryEntry одно событие в истории PR.
+// ReviewerID - назначенный ревьюер, PreviousReviewerID - снятый; пустые, если событие не про ревьюеров.
+type PullRequestHistoryEntry struct {
+	ID                 int64
+	PullRequestID      string
+	Event              string
+	ReviewerID         string
+	PreviousReviewerID string
+	Reason             string
+	Actor              string
+	CreatedAt          time.Time
+}
@@ -1,8 +1,10 @@
 package domain
 
 type Team struct {
-	Name  string
-	Users []User
+	Name  string `json:"name"`
+	Users []User `json:"users"`
+	// RequireSeniorReviewer требует хотя бы одного senior среди ревьюеров PR команды.
+	RequireSeniorReviewer bool `json:"require_senior_reviewer"`
 }
 
 func NewTeam(name string, users []User) Team {
@@ -27,3 +29,20 @@ func (t Team) ActiveReviewersExcluding(authorID string) []User {
 	}
 	return reviewers
 }
+
+// ValidateReviewer проверяет, что участник команды может ревьюить PR автора.
+func (t Team) ValidateReviewer(reviewerID, authorID string) error {
+	if reviewerID == authorID {
+		return ErrReviewerIsAuthor
+	}
+	for _, user := range t.Users {
+		if user.ID != reviewerID {
+			continue
+		}
+		if !user.IsActive {
+			return ErrReviewerInactive
+		}
+		return nil
+	}
+	return ErrReviewerNotInTeam
+}
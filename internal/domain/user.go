@@ -1,10 +1,43 @@
 package domain
 
+import (
+	"math"
+	"time"
+)
+
+// Уровни пользователя по возрастанию старшинства
+const (
+	LevelJunior = "junior"
+	LevelMiddle = "middle"
+	LevelSenior = "senior"
+)
+
+var levelRanks = map[string]int{
+	LevelJunior: 1,
+	LevelMiddle: 2,
+	LevelSenior: 3,
+}
+
 type User struct {
-	ID       string `db:"id"`
-	Name     string `db:"name"`
-	TeamName string `db:"team_name"`
-	IsActive bool   `db:"is_active"`
+	ID       string `db:"id" json:"id"`
+	Name     string `db:"name" json:"name"`
+	TeamName string `db:"team_name" json:"team_name"`
+	IsActive bool   `db:"is_active" json:"is_active"`
+	Level    string `db:"level" json:"level"`
+	// Timezone часовой пояс IANA, например Europe/Moscow.
+	Timezone string `db:"timezone" json:"timezone"`
+	// WorkStartHour и WorkEndHour задают рабочие часы [start, end) в локальном времени;
+	// если start > end, интервал переходит через полночь.
+	WorkStartHour int `db:"work_start_hour" json:"work_start_hour"`
+	WorkEndHour   int `db:"work_end_hour" json:"work_end_hour"`
+	// ReviewWeight относительная частота назначений: 0 - никогда, 1 - обычно.
+	ReviewWeight float64 `db:"review_weight" json:"review_weight"`
+	// DigestEnabled пользователь получает ежедневный дайджест ожидающих ревью.
+	DigestEnabled bool `db:"digest_enabled" json:"digest_enabled"`
+	// AbsentFrom и AbsentUntil задают отсутствие [from, until), например отпуск;
+	// оба nil, если отсутствие не запланировано.
+	AbsentFrom  *time.Time `db:"absent_from" json:"absent_from,omitempty"`
+	AbsentUntil *time.Time `db:"absent_until" json:"absent_until,omitempty"`
 }
 
 // NewUser создаёт пользователя с привязкой к команде.
@@ -14,5 +47,81 @@ func NewUser(id, name, teamName string, isActive bool) User {
 		Name:     name,
 		TeamName: teamName,
 		IsActive: isActive,
+		Level:    LevelMiddle,
+		Timezone: "UTC",
+
+		WorkStartHour: 0,
+		WorkEndHour:   24,
+		ReviewWeight:  1,
+	}
+}
+
+// IsValidReviewWeight проверяет, что вес ревью неотрицательный.
+func IsValidReviewWeight(weight float64) bool {
+	return weight >= 0 && !math.IsInf(weight, 0) && !math.IsNaN(weight)
+}
+
+// IsValidLevel проверяет, что уровень известен.
+func IsValidLevel(level string) bool {
+	_, ok := levelRanks[level]
+	return ok
+}
+
+// IsSenior сообщает, что пользователь уровня senior или выше.
+func (u User) IsSenior() bool {
+	return levelRanks[u.Level] >= levelRanks[LevelSenior]
+}
+
+// ValidateSchedule проверяет часовой пояс и рабочие часы.
+func ValidateSchedule(timezone string, startHour, endHour int) error {
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+		return ErrInvalidSchedule
+	}
+	if startHour < 0 || startHour > 23 || endHour < 1 || endHour > 24 || startHour == endHour {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// ValidateAbsence проверяет, что отсутствие либо не задано, либо задано целиком и не пустое.
+func ValidateAbsence(from, until *time.Time) error {
+	if from == nil && until == nil {
+		return nil
+	}
+	if from == nil || until == nil || !from.Before(*until) {
+		return ErrInvalidAbsence
+	}
+	return nil
+}
+
+// IsAbsentAt сообщает, попадает ли момент t в отсутствие пользователя.
+func (u User) IsAbsentAt(t time.Time) bool {
+	if u.AbsentFrom == nil || u.AbsentUntil == nil {
+		return false
+	}
+	return !t.Before(*u.AbsentFrom) && t.Before(*u.AbsentUntil)
+}
+
+// IsAvailableAt сообщает, можно ли в момент t назначить пользователя ревьюером:
+// он не отсутствует и его вес ревью больше 0.
+func (u User) IsAvailableAt(t time.Time) bool {
+	return u.ReviewWeight > 0 && !u.IsAbsentAt(t)
+}
+
+// LocalTime момент t в часовом поясе пользователя; неизвестный пояс считается UTC.
+func (u User) LocalTime(t time.Time) time.Time {
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	return t.In(location)
+}
+
+// IsWorkingAt сообщает, попадает ли момент t в рабочие часы пользователя.
+func (u User) IsWorkingAt(t time.Time) bool {
+	hour := u.LocalTime(t).Hour()
+	if u.WorkStartHour <= u.WorkEndHour {
+		return hour >= u.WorkStartHour && hour < u.WorkEndHour
 	}
+	return hour >= u.WorkStartHour || hour < u.WorkEndHour
 }
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	WebhookDeliveryPending   = "PENDING"
+	WebhookDeliverySucceeded = "SUCCEEDED"
+	WebhookDeliveryFailed    = "FAILED"
+)
+
+// WebhookSubscription подписка внешней системы на доменные события.
+// Secret подписывает тело запроса (HMAC-SHA256) и наружу не отдаётся, в том числе в журнал аудита.
+type WebhookSubscription struct {
+	ID         string
+	URL        string
+	Secret     string `json:"-"`
+	EventTypes []string
+	CreatedAt  time.Time
+}
+
+func NewWebhookSubscription(id, rawURL, secret string, eventTypes []string, createdAt time.Time) (WebhookSubscription, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return WebhookSubscription{}, ErrInvalidWebhook
+	}
+	if secret == "" || len(eventTypes) == 0 {
+		return WebhookSubscription{}, ErrInvalidWebhook
+	}
+
+	types := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(EventTypes, eventType) {
+			return WebhookSubscription{}, ErrInvalidWebhook
+		}
+		if !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+
+	return WebhookSubscription{
+		ID:         id,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: types,
+		CreatedAt:  createdAt,
+	}, nil
+}
+
+// Wants сообщает, подписана ли подписка на тип события.
+func (s WebhookSubscription) Wants(eventType string) bool {
+	return slices.Contains(s.EventTypes, eventType)
+}
+
+// WebhookDelivery доставка одного события одной подписке; заодно запись журнала доставок.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID string
+	MessageID      int64
+	EventType      string
+	Payload        []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// Succeed отмечает успешную доставку.
+func (d *WebhookDelivery) Succeed(responseStatus int, at time.Time) {
+	d.Status = WebhookDeliverySucceeded
+	d.ResponseStatus = responseStatus
+	d.LastError = ""
+	d.DeliveredAt = &at
+}
+
+// Retry сохраняет ошибку попытки; без nextAttemptAt доставка считается проваленной.
+func (d *WebhookDelivery) Retry(responseStatus int, err error, nextAttemptAt *time.Time) {
+	d.ResponseStatus = responseStatus
+	d.LastError = err.Error()
+	if nextAttemptAt == nil {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = *nextAttemptAt
+}
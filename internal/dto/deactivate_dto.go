@@ -2,9 +2,33 @@ package dto
 
 type DeactivateTeamUsersInput struct {
 	TeamName string `json:"team_name" validate:"required"`
+	DryRun   bool   `json:"dry_run"`
+	// UserIDs и ExceptUserIDs взаимоисключающие; без них деактивируется вся команда
+	UserIDs       []string `json:"user_ids,omitempty"`
+	ExceptUserIDs []string `json:"except_user_ids,omitempty"`
+}
+
+// ReviewerReplacement замена ревьювера; new_reviewer_id = null - замены нет, причина в reason
+type ReviewerReplacement struct {
+	OldReviewerID string  `json:"old_reviewer_id"`
+	NewReviewerID *string `json:"new_reviewer_id"`
+	Reason        string  `json:"reason,omitempty"`
+}
+
+type PullRequestReport struct {
+	PullRequestID   string                `json:"pull_request_id"`
+	ReviewersBefore []string              `json:"reviewers_before"`
+	ReviewersAfter  []string              `json:"reviewers_after"`
+	Replacements    []ReviewerReplacement `json:"replacements"`
 }
 
 type DeactivateTeamUsersOutput struct {
-	DeactivatedCount  int `json:"deactivated_count"`
-	ReassignedPRCount int `json:"reassigned_pr_count"`
+	DryRun             bool                `json:"dry_run"`
+	DeactivatedCount   int                 `json:"deactivated_count"`
+	ReassignedPRCount  int                 `json:"reassigned_pr_count"`
+	DeactivatedUserIDs []string            `json:"deactivated_user_ids"`
+	PullRequests       []PullRequestReport `json:"pull_requests"`
+	OperationID        string              `json:"operation_id,omitempty"`
+	// ReplacementsIndicative замены в плане dry run ориентировочные, реальный запуск подбирает их заново
+	ReplacementsIndicative bool `json:"replacements_indicative,omitempty"`
 }
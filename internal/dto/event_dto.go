@@ -0,0 +1,15 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event событие потока; id совпадает с id сообщения outbox и передаётся в Last-Event-ID
+type Event struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Actor      string          `json:"actor"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// GitHubAccount пользователь GitHub
+type GitHubAccount struct {
+	Login string `json:"login"`
+}
+
+// GitHubPullRequestEvent тело вебхука pull_request, только используемые поля
+type GitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string        `json:"title"`
+		User   GitHubAccount `json:"user"`
+		Draft  bool          `json:"draft"`
+		Merged bool          `json:"merged"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender GitHubAccount `json:"sender"`
+}
+
+// IntegrationEventOutput что сервис сделал с событием внешней системы
+type IntegrationEventOutput struct {
+	Result string `json:"result"`
+}
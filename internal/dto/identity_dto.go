@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type CreateUserIdentityInput struct {
+	UserID   string `json:"user_id" validate:"required"`
+	Provider string `json:"provider" validate:"required"`
+	Handle   string `json:"handle" validate:"required"`
+}
+
+type UpdateUserIdentityInput struct {
+	Handle string `json:"handle" validate:"required"`
+}
+
+// UserIdentity учётная запись пользователя во внешней системе
+type UserIdentity struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Handle    string    `json:"handle"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserIdentitiesOutput struct {
+	Identities []UserIdentity `json:"identities"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// JobAccepted ответ на постановку фоновой задачи; статус смотреть в GET /jobs/{id}
+type JobAccepted struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
+type JobProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type Job struct {
+	JobID    string      `json:"job_id"`
+	Type     string      `json:"type"`
+	Status   string      `json:"status"`
+	Progress JobProgress `json:"progress"`
+	Attempts int         `json:"attempts"`
+	// Result ответ операции в том же формате, что раньше отдавал синхронный эндпоинт
+	Result     any        `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type SkippedPullRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	Reason        string `json:"reason"`
+}
+
+type RevertOperationOutput struct {
+	OperationID      string               `json:"operation_id"`
+	ReactivatedCount int                  `json:"reactivated_count"`
+	RestoredPRIDs    []string             `json:"restored_pull_request_ids"`
+	SkippedPRs       []SkippedPullRequest `json:"skipped_pull_requests"`
+}
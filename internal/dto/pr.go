@@ -3,9 +3,10 @@ package dto
 import "time"
 
 type CreatePullRequestRequest struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	RequiredReviewers []string `json:"required_reviewers,omitempty"`
 }
 
 type PullRequest struct {
@@ -14,6 +15,7 @@ type PullRequest struct {
 	AuthorID          string     `json:"author_id"`
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	RequiredReviewers []string   `json:"required_reviewers,omitempty"`
 	CreatedAt         time.Time  `json:"createdAt"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
 }
@@ -30,9 +32,10 @@ type PullRequestShort struct {
 }
 
 type ReassignReviewerRequest struct {
-	PullRequestID string  `json:"pull_request_id"`
-	OldReviewerID string  `json:"old_user_id"`
-	NewReviewerID *string `json:"new_user_id,omitempty"`
+	PullRequestID    string  `json:"pull_request_id"`
+	OldReviewerID    string  `json:"old_user_id"`
+	NewReviewerID    *string `json:"new_user_id,omitempty"`
+	OverrideRequired bool    `json:"override_required,omitempty"`
 }
 
 type ReassignReviewerResponse struct {
@@ -44,3 +47,17 @@ type ReviewerPullRequestsResponse struct {
 	UserID       string             `json:"user_id"`
 	PullRequests []PullRequestShort `json:"pull_requests"`
 }
+
+type PullRequestHistoryEntry struct {
+	Event              string    `json:"event"`
+	ReviewerID         string    `json:"reviewer_id,omitempty"`
+	PreviousReviewerID string    `json:"previous_reviewer_id,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	Actor              string    `json:"actor"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type PullRequestHistoryResponse struct {
+	PullRequestID string                    `json:"pull_request_id"`
+	History       []PullRequestHistoryEntry `json:"history"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+type RebalanceTeamInput struct {
+	TeamName string `json:"team_name" validate:"required"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+type ReviewMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+type MemberLoad struct {
+	UserID string `json:"user_id"`
+	Before int    `json:"open_reviews_before"`
+	After  int    `json:"open_reviews_after"`
+}
+
+type RebalanceTeamOutput struct {
+	DryRun bool         `json:"dry_run"`
+	Moves  []ReviewMove `json:"moves"`
+	Loads  []MemberLoad `json:"loads"`
+}
@@ -1,12 +1,36 @@
 package dto
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID        string   `json:"user_id"`
+	Username      string   `json:"username"`
+	IsActive      bool     `json:"is_active"`
+	Level         string   `json:"level,omitempty"`
+	Timezone      string   `json:"timezone,omitempty"`
+	WorkStartHour *int     `json:"work_start_hour,omitempty"`
+	WorkEndHour   *int     `json:"work_end_hour,omitempty"`
+	ReviewWeight  *float64 `json:"review_weight,omitempty"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName              string       `json:"team_name"`
+	Members               []TeamMember `json:"members"`
+	RequireSeniorReviewer bool         `json:"require_senior_reviewer"`
+}
+
+type MemberSettings struct {
+	UserID        string   `json:"user_id"`
+	Level         string   `json:"level,omitempty"`
+	Timezone      string   `json:"timezone,omitempty"`
+	WorkStartHour *int     `json:"work_start_hour,omitempty"`
+	WorkEndHour   *int     `json:"work_end_hour,omitempty"`
+	ReviewWeight  *float64 `json:"review_weight,omitempty"`
+}
+
+type UpdateTeamRequest struct {
+	TeamName              string           `json:"team_name"`
+	RequireSeniorReviewer *bool            `json:"require_senior_reviewer,omitempty"`
+	Members               []MemberSettings `json:"members,omitempty"`
+	// AddMembers новые участники; существующий пользователь переводится в команду
+	// с сохранением настроек, которые не указаны
+	AddMembers []TeamMember `json:"add_members,omitempty"`
 }
@@ -1,13 +1,34 @@
 package dto
 
+import "time"
+
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	Level    string `json:"level,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// DigestEnabled подписан ли пользователь на ежедневный дайджест
+	DigestEnabled bool `json:"digest_enabled"`
+	// AbsentFrom и AbsentUntil запланированное отсутствие, если оно есть
+	AbsentFrom  *time.Time `json:"absent_from,omitempty"`
+	AbsentUntil *time.Time `json:"absent_until,omitempty"`
 }
 
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id"`
 	IsActive bool   `json:"is_active"`
 }
+
+type SetUserDigestRequest struct {
+	UserID  string `json:"user_id"`
+	Enabled bool   `json:"enabled"`
+}
+
+// SetUserAbsenceRequest без absent_from и absent_until снимает отсутствие.
+type SetUserAbsenceRequest struct {
+	UserID      string     `json:"user_id"`
+	AbsentFrom  *time.Time `json:"absent_from"`
+	AbsentUntil *time.Time `json:"absent_until"`
+}
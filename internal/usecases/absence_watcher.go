@@ -0,0 +1,73 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AbsenceWatcher снимает закончившиеся отсутствия. Снятие дозаполняет PR команды вернувшегося,
+// так что ёмкость не ждёт следующего ручного изменения.
+type AbsenceWatcher struct {
+	users    UserStorage
+	absences AbsenceUpdater
+	clock    ClockAdapter
+	poll     time.Duration
+	log      *slog.Logger
+}
+
+func NewAbsenceWatcher(users UserStorage, absences AbsenceUpdater, clock ClockAdapter, pollInterval time.Duration, log *slog.Logger) *AbsenceWatcher {
+	return &AbsenceWatcher{
+		users:    users,
+		absences: absences,
+		clock:    clock,
+		poll:     pollInterval,
+		log:      log,
+	}
+}
+
+// Run проверяет закончившиеся отсутствия, пока не отменён ctx.
+func (w *AbsenceWatcher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if _, err := w.RunOnce(ctx); err != nil {
+			w.log.ErrorContext(ctx, "ошибка снятия закончившихся отсутствий", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.poll):
+		}
+	}
+}
+
+// RunOnce снимает отсутствия, которые уже закончились. Возвращает, сколько снято.
+func (w *AbsenceWatcher) RunOnce(ctx context.Context) (int, error) {
+	users, err := w.users.ListUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := w.clock.Now()
+	cleared := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return cleared, nil
+		}
+		if user.AbsentUntil == nil || now.Before(*user.AbsentUntil) {
+			continue
+		}
+
+		// список мог устареть: отсутствие снимается, только если оно всё ещё закончилось
+		ok, err := w.absences.ClearExpiredAbsence(ctx, user.ID)
+		if err != nil {
+			w.log.ErrorContext(ctx, "не удалось снять отсутствие", "user_id", user.ID, "error", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		w.log.InfoContext(ctx, "отсутствие закончилось", "user_id", user.ID)
+		cleared++
+	}
+	return cleared, nil
+}
@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type actorKey struct{}
+
+// WithActor запоминает в контексте автора изменений для журнала аудита.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext возвращает автора изменений; без него изменения записываются от domain.ActorSystem.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return domain.ActorSystem
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// AuditTrail Auditor поверх AuditStorage: сериализует состояния в JSON и ставит время и автора.
+type AuditTrail struct {
+	events AuditStorage
+	clock  ClockAdapter
+}
+
+func NewAuditTrail(auditStorage AuditStorage, clock ClockAdapter) *AuditTrail {
+	return &AuditTrail{
+		events: auditStorage,
+		clock:  clock,
+	}
+}
+
+// Record пишет событие; nil в before или after сохраняется как отсутствие состояния.
+func (a *AuditTrail) Record(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	beforeJSON, err := marshalAuditState(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalAuditState(after)
+	if err != nil {
+		return err
+	}
+
+	return a.events.CreateAuditEvent(ctx, domain.AuditEvent{
+		Actor:      ActorFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  a.clock.Now(),
+	})
+}
+
+func marshalAuditState(state any) ([]byte, error) {
+	if state == nil {
+		return nil, nil
+	}
+	return json.Marshal(state)
+}
+
+// inTransaction выполняет fn в транзакции и возвращает её результат.
+func inTransaction[T any](ctx context.Context, tx Transactor, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
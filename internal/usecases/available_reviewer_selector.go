@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// AvailableReviewerSelector убирает из кандидатов тех, кого сейчас нельзя назначать,
+// и передаёт остальных вложенной стратегии. Стоит снаружи остальных обёрток,
+// поэтому исключение действует при любой стратегии.
+type AvailableReviewerSelector struct {
+	next  ReviewerSelector
+	clock ClockAdapter
+}
+
+func NewAvailableReviewerSelector(next ReviewerSelector, clock ClockAdapter) *AvailableReviewerSelector {
+	return &AvailableReviewerSelector{
+		next:  next,
+		clock: clock,
+	}
+}
+
+// Select пропускает отсутствующих и тех, у кого вес ревью 0, и выбирает среди остальных.
+func (s *AvailableReviewerSelector) Select(ctx context.Context, pr domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	now := s.clock.Now()
+
+	available := make([]domain.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.IsAvailableAt(now) {
+			available = append(available, candidate)
+		}
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+	return s.next.Select(ctx, pr, available, count)
+}
+
+// RecordSelection передаёт итоговый выбор вложенной стратегии.
+func (s *AvailableReviewerSelector) RecordSelection(ctx context.Context, pr domain.PullRequest, selected []domain.User) error {
+	return recordSelection(ctx, s.next, pr, selected)
+}
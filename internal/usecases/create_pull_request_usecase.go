@@ -9,12 +9,16 @@ import (
 )
 
 type CreatePullRequestUseCase struct {
-	prs   PullRequestStorage
-	teams TeamStorage
-	users UserStorage
-	clock ClockAdapter
-	rand  RandomAdapter
-	log   *slog.Logger
+	prs      PullRequestStorage
+	teams    TeamStorage
+	users    UserStorage
+	clock    ClockAdapter
+	selector ReviewerSelector
+	tx       Transactor
+	audit    Auditor
+	history  HistoryRecorder
+	events   EventRecorder
+	log      *slog.Logger
 }
 
 func NewCreatePullRequestUseCase(
@@ -22,23 +26,37 @@ func NewCreatePullRequestUseCase(
 	teamStorage TeamStorage,
 	userStorage UserStorage,
 	clock ClockAdapter,
-	random RandomAdapter,
+	selector ReviewerSelector,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
 	log *slog.Logger,
 ) *CreatePullRequestUseCase {
 	return &CreatePullRequestUseCase{
-		prs:   prStorage,
-		teams: teamStorage,
-		users: userStorage,
-		clock: clock,
-		rand:  random,
-		log:   log,
+		prs:      prStorage,
+		teams:    teamStorage,
+		users:    userStorage,
+		clock:    clock,
+		selector: selector,
+		tx:       tx,
+		audit:    audit,
+		history:  history,
+		events:   events,
+		log:      log,
 	}
 }
 
-// Create создаёт pull request.
-func (uc *CreatePullRequestUseCase) Create(ctx context.Context, id, title, authorID string) (domain.PullRequest, error) {
+// Create создаёт pull request. Обязательные ревьюеры назначаются первыми, остальные места заполняет selector.
+func (uc *CreatePullRequestUseCase) Create(ctx context.Context, id, title, authorID string, requiredReviewers []string) (domain.PullRequest, error) {
 	uc.log.InfoContext(ctx, "создаём pull request", "pr_id", id, "author_id", authorID)
 
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		return uc.create(ctx, id, title, authorID, requiredReviewers)
+	})
+}
+
+func (uc *CreatePullRequestUseCase) create(ctx context.Context, id, title, authorID string, requiredReviewers []string) (domain.PullRequest, error) {
 	if _, err := uc.prs.GetPullRequest(ctx, id); err == nil {
 		uc.log.WarnContext(ctx, "pull request уже существует", "pr_id", id)
 		return domain.PullRequest{}, domain.ErrPullRequestExists
@@ -63,37 +81,78 @@ func (uc *CreatePullRequestUseCase) Create(ctx context.Context, id, title, autho
 		return domain.PullRequest{}, err
 	}
 
-	candidates := team.ActiveReviewersExcluding(authorID)
-	if len(candidates) == 0 {
-		uc.log.WarnContext(ctx, "нет активных кандидатов в ревьюеры", "pr_id", id, "team_name", team.Name)
-	}
+	pr := domain.NewPullRequest(id, title, authorID, team.Name, uc.clock.Now())
 
-	selected := candidates
-	if len(selected) > 0 {
-		if uc.rand != nil && len(selected) > 1 {
-			uc.rand.Shuffle(len(selected), func(i, j int) {
-				selected[i], selected[j] = selected[j], selected[i]
-			})
+	for _, reviewerID := range requiredReviewers {
+		if err := team.ValidateReviewer(reviewerID, authorID); err != nil {
+			uc.log.WarnContext(ctx, "обязательный ревьюер не подходит", "pr_id", id, "reviewer_id", reviewerID, "error", err)
+			return domain.PullRequest{}, err
+		}
+		if err := pr.AddRequiredReviewer(reviewerID); err != nil {
+			uc.log.WarnContext(ctx, "не удалось назначить обязательного ревьюера", "pr_id", id, "reviewer_id", reviewerID, "error", err)
+			return domain.PullRequest{}, err
 		}
+	}
 
-		if len(selected) > 2 {
-			selected = selected[:2]
+	candidates := make([]domain.User, 0, len(team.Users))
+	for _, candidate := range team.ActiveReviewersExcluding(authorID) {
+		if !pr.HasReviewer(candidate.ID) {
+			candidates = append(candidates, candidate)
 		}
 	}
+	if len(candidates) == 0 && len(pr.Reviewers) == 0 {
+		uc.log.WarnContext(ctx, "нет активных кандидатов в ревьюеры", "pr_id", id, "team_name", team.Name)
+	}
 
-	reviewerIDs := make([]string, 0, len(selected))
-	for _, reviewer := range selected {
-		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	selected, err := selectWithSeniorPolicy(ctx, uc.selector, uc.users, team, pr, candidates, pr.MissingReviewers())
+	if errors.Is(err, domain.ErrNoSeniorReviewer) {
+		uc.log.WarnContext(ctx, "не удалось выполнить политику senior-ревьюера", "pr_id", id, "team_name", team.Name)
+		return domain.PullRequest{}, err
+	}
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка выбора ревьюеров", "pr_id", id, "error", err)
+		return domain.PullRequest{}, err
 	}
 
-	pr := domain.NewPullRequest(id, title, authorID, team.Name, uc.clock.Now())
-	pr.AssignReviewers(reviewerIDs)
+	for _, reviewer := range selected {
+		if err := pr.AddReviewer(reviewer.ID); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось назначить ревьюера", "pr_id", id, "reviewer_id", reviewer.ID, "error", err)
+			return domain.PullRequest{}, err
+		}
+	}
 
 	if err := uc.prs.CreatePullRequest(ctx, pr); err != nil {
 		uc.log.ErrorContext(ctx, "ошибка сохранения pull request", "error", err, "pr_id", id)
 		return domain.PullRequest{}, err
 	}
+	if err := uc.audit.Record(ctx, domain.AuditPullRequestCreated, domain.AuditEntityPullRequest, pr.ID, nil, pr); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	entries := []domain.PullRequestHistoryEntry{historyEvent(pr.ID, domain.PRHistoryCreated)}
+	for _, reviewerID := range pr.Reviewers {
+		reason := domain.PRHistoryReasonAuto
+		if pr.IsRequiredReviewer(reviewerID) {
+			reason = domain.PRHistoryReasonRequired
+		}
+		entries = append(entries, historyAssigned(pr.ID, reviewerID, reason))
+	}
+	if err := uc.history.Record(ctx, entries...); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	created := domain.PullRequestCreated{
+		PullRequestID: pr.ID,
+		Title:         pr.Title,
+		AuthorID:      pr.AuthorID,
+		TeamName:      pr.TeamName,
+		Reviewers:     pr.Reviewers,
+		CreatedAt:     pr.CreatedAt,
+	}
+	if err := uc.events.Record(ctx, append([]domain.Event{created}, reviewerEvents(pr, entries)...)...); err != nil {
+		return domain.PullRequest{}, err
+	}
 
-	uc.log.InfoContext(ctx, "pull request создан", "pr_id", id, "reviewers", reviewerIDs)
+	uc.log.InfoContext(ctx, "pull request создан", "pr_id", id, "reviewers", pr.Reviewers, "required_reviewers", pr.RequiredReviewers)
 	return pr, nil
 }
@@ -11,21 +11,45 @@ import (
 type CreateTeamUseCase struct {
 	teams TeamStorage
 	users UserStorage
+	tx    Transactor
+	audit Auditor
 	log   *slog.Logger
 }
 
-func NewCreateTeamUseCase(teamStorage TeamStorage, userStorage UserStorage, log *slog.Logger) *CreateTeamUseCase {
+func NewCreateTeamUseCase(
+	teamStorage TeamStorage,
+	userStorage UserStorage,
+	tx Transactor,
+	audit Auditor,
+	log *slog.Logger,
+) *CreateTeamUseCase {
 	return &CreateTeamUseCase{
 		teams: teamStorage,
 		users: userStorage,
+		tx:    tx,
+		audit: audit,
 		log:   log,
 	}
 }
 
-// Create создаёт новую команду и обновляет участников.
-func (uc *CreateTeamUseCase) Create(ctx context.Context, team domain.Team) (domain.Team, error) {
+// Create создаёт новую команду и обновляет участников. В team.Users важны только id, имя
+// и активность; остальные настройки участника берутся из settings. Существующий пользователь
+// сохраняет настройки, которых нет в settings, новый получает для них значения по умолчанию.
+func (uc *CreateTeamUseCase) Create(ctx context.Context, team domain.Team, settings map[string]MemberUpdate) (domain.Team, error) {
 	uc.log.InfoContext(ctx, "создаём команду", "team_name", team.Name)
 
+	created, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.Team, error) {
+		return uc.create(ctx, team, settings)
+	})
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	uc.log.InfoContext(ctx, "команда успешно создана", "team_name", created.Name)
+	return created, nil
+}
+
+func (uc *CreateTeamUseCase) create(ctx context.Context, team domain.Team, settings map[string]MemberUpdate) (domain.Team, error) {
 	if _, err := uc.teams.GetTeam(ctx, team.Name); err == nil {
 		uc.log.WarnContext(ctx, "команда уже существует", "team_name", team.Name)
 		return domain.Team{}, domain.ErrTeamExists
@@ -34,33 +58,16 @@ func (uc *CreateTeamUseCase) Create(ctx context.Context, team domain.Team) (doma
 		return domain.Team{}, err
 	}
 
-	for i := range team.Users {
-		member := team.Users[i]
-		member.TeamName = team.Name
-
-		existing, err := uc.users.GetUser(ctx, member.ID)
-		switch {
-		case errors.Is(err, domain.ErrUserNotFound):
-			if err := uc.users.CreateUser(ctx, member); err != nil {
-				uc.log.ErrorContext(ctx, "не удалось создать пользователя", "error", err, "user_id", member.ID)
-				return domain.Team{}, err
-			}
-			uc.log.InfoContext(ctx, "создали нового пользователя", "user_id", member.ID, "team_name", team.Name)
-		case err != nil:
-			uc.log.ErrorContext(ctx, "не удалось получить пользователя", "error", err, "user_id", member.ID)
+	for i, user := range team.Users {
+		member, err := upsertMember(ctx, uc.users, uc.audit, uc.log, team.Name, NewMember{
+			ID:       user.ID,
+			Name:     user.Name,
+			IsActive: user.IsActive,
+			Settings: settings[user.ID],
+		})
+		if err != nil {
 			return domain.Team{}, err
-		default:
-			existing.Name = member.Name
-			existing.TeamName = team.Name
-			existing.IsActive = member.IsActive
-			if err := uc.users.UpdateUser(ctx, existing); err != nil {
-				uc.log.ErrorContext(ctx, "не удалось обновить пользователя", "error", err, "user_id", member.ID)
-				return domain.Team{}, err
-			}
-			member = existing
-			uc.log.InfoContext(ctx, "обновили пользователя", "user_id", member.ID, "team_name", team.Name)
 		}
-
 		team.Users[i] = member
 	}
 
@@ -68,7 +75,9 @@ func (uc *CreateTeamUseCase) Create(ctx context.Context, team domain.Team) (doma
 		uc.log.ErrorContext(ctx, "не удалось сохранить команду", "error", err, "team_name", team.Name)
 		return domain.Team{}, err
 	}
+	if err := uc.audit.Record(ctx, domain.AuditTeamCreated, domain.AuditEntityTeam, team.Name, nil, team); err != nil {
+		return domain.Team{}, err
+	}
 
-	uc.log.InfoContext(ctx, "команда успешно создана", "team_name", team.Name)
 	return team, nil
 }
@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type CreateUserIdentityUseCase struct {
+	identities UserIdentityStorage
+	users      UserStorage
+	clock      ClockAdapter
+	ids        IDGenerator
+	tx         Transactor
+	audit      Auditor
+	log        *slog.Logger
+}
+
+func NewCreateUserIdentityUseCase(
+	identityStorage UserIdentityStorage,
+	userStorage UserStorage,
+	clock ClockAdapter,
+	ids IDGenerator,
+	tx Transactor,
+	audit Auditor,
+	log *slog.Logger,
+) *CreateUserIdentityUseCase {
+	return &CreateUserIdentityUseCase{
+		identities: identityStorage,
+		users:      userStorage,
+		clock:      clock,
+		ids:        ids,
+		tx:         tx,
+		audit:      audit,
+		log:        log,
+	}
+}
+
+// Create привязывает к пользователю учётную запись во внешней системе.
+func (uc *CreateUserIdentityUseCase) Create(ctx context.Context, userID, provider, handle string) (domain.UserIdentity, error) {
+	identity, err := domain.NewUserIdentity(uc.ids.NewID(), userID, provider, handle, uc.clock.Now())
+	if err != nil {
+		uc.log.WarnContext(ctx, "некорректная учётная запись", "user_id", userID, "provider", provider)
+		return domain.UserIdentity{}, err
+	}
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.UserIdentity, error) {
+		if _, err := uc.users.GetUser(ctx, userID); err != nil {
+			uc.log.WarnContext(ctx, "пользователь не найден", "user_id", userID, "error", err)
+			return domain.UserIdentity{}, err
+		}
+
+		if err := uc.identities.CreateUserIdentity(ctx, identity); err != nil {
+			uc.log.WarnContext(ctx, "не удалось привязать учётную запись", "user_id", userID, "provider", provider, "error", err)
+			return domain.UserIdentity{}, err
+		}
+
+		if err := uc.audit.Record(ctx, domain.AuditIdentityAdded, domain.AuditEntityUser, userID, nil, identity); err != nil {
+			return domain.UserIdentity{}, err
+		}
+
+		uc.log.InfoContext(ctx, "учётная запись привязана", "identity_id", identity.ID, "user_id", userID, "provider", provider)
+		return identity, nil
+	})
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type CreateWebhookUseCase struct {
+	webhooks WebhookStorage
+	clock    ClockAdapter
+	ids      IDGenerator
+	tx       Transactor
+	audit    Auditor
+	log      *slog.Logger
+}
+
+func NewCreateWebhookUseCase(
+	webhookStorage WebhookStorage,
+	clock ClockAdapter,
+	ids IDGenerator,
+	tx Transactor,
+	audit Auditor,
+	log *slog.Logger,
+) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{
+		webhooks: webhookStorage,
+		clock:    clock,
+		ids:      ids,
+		tx:       tx,
+		audit:    audit,
+		log:      log,
+	}
+}
+
+// Create регистрирует подписку на перечисленные типы событий.
+func (uc *CreateWebhookUseCase) Create(ctx context.Context, url, secret string, eventTypes []string) (domain.WebhookSubscription, error) {
+	subscription, err := domain.NewWebhookSubscription(uc.ids.NewID(), url, secret, eventTypes, uc.clock.Now())
+	if err != nil {
+		uc.log.WarnContext(ctx, "некорректная подписка на вебхук", "url", url, "event_types", eventTypes)
+		return domain.WebhookSubscription{}, err
+	}
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.WebhookSubscription, error) {
+		if err := uc.webhooks.CreateWebhook(ctx, subscription); err != nil {
+			uc.log.ErrorContext(ctx, "ошибка создания подписки на вебхук", "error", err)
+			return domain.WebhookSubscription{}, err
+		}
+
+		if err := uc.audit.Record(ctx, domain.AuditWebhookCreated, domain.AuditEntityWebhook, subscription.ID, nil, subscription); err != nil {
+			return domain.WebhookSubscription{}, err
+		}
+
+		uc.log.InfoContext(ctx, "подписка на вебхук создана", "webhook_id", subscription.ID, "url", subscription.URL)
+		return subscription, nil
+	})
+}
@@ -2,43 +2,128 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
 )
 
+// DeactivateOptions параметры массовой деактивации
+type DeactivateOptions struct {
+	// DryRun только строит план, ничего не сохраняя
+	DryRun bool `json:"dry_run"`
+	// UserIDs деактивировать только этих участников; пусто - всю команду
+	UserIDs []string `json:"user_ids,omitempty"`
+	// ExceptUserIDs деактивировать всех, кроме этих участников
+	ExceptUserIDs []string `json:"except_user_ids,omitempty"`
+}
+
+// Причины, по которым место ревьювера осталось пустым
+const (
+	ReasonNoActiveCandidates   = "no_active_candidates"
+	ReasonNoEligibleCandidates = "no_eligible_candidates"
+	ReasonAuthorTeamNotFound   = "author_team_not_found"
+)
+
+// ReviewerReplacement замена ревьювера; пустой NewReviewerID - замены нет, причина в Reason
+type ReviewerReplacement struct {
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// PullRequestReport изменения одного затронутого PR
+type PullRequestReport struct {
+	PullRequestID   string                `json:"pull_request_id"`
+	ReviewersBefore []string              `json:"reviewers_before"`
+	ReviewersAfter  []string              `json:"reviewers_after"`
+	Replacements    []ReviewerReplacement `json:"replacements"`
+}
+
 type DeactivateResult struct {
-	DeactivatedCount  int `json:"deactivated_count"`
-	ReassignedPRCount int `json:"reassigned_pr_count"`
+	DryRun             bool                `json:"dry_run"`
+	DeactivatedCount   int                 `json:"deactivated_count"`
+	ReassignedPRCount  int                 `json:"reassigned_pr_count"`
+	DeactivatedUserIDs []string            `json:"deactivated_user_ids"`
+	PullRequests       []PullRequestReport `json:"pull_requests"`
+	// OperationID идентификатор для отката; пустой для dry run и когда ничего не изменилось
+	OperationID string `json:"operation_id"`
+	// ReplacementsIndicative замены в плане dry run ориентировочные: реальный запуск подбирает их заново
+	ReplacementsIndicative bool `json:"replacements_indicative,omitempty"`
 }
 
 type DeactivateTeamUsersUseCase struct {
-	users  UserStorage
-	teams  TeamStorage
-	prs    PullRequestStorage
-	random RandomAdapter
-	log    *slog.Logger
+	users      UserStorage
+	teams      TeamStorage
+	prs        PullRequestStorage
+	operations OperationStorage
+	selector   ReviewerSelector
+	clock      ClockAdapter
+	ids        IDGenerator
+	tx         Transactor
+	audit      Auditor
+	history    HistoryRecorder
+	events     EventRecorder
+	log        *slog.Logger
 }
 
 func NewDeactivateTeamUsersUseCase(
 	userStorage UserStorage,
 	teamStorage TeamStorage,
 	prStorage PullRequestStorage,
-	random RandomAdapter,
+	operationStorage OperationStorage,
+	selector ReviewerSelector,
+	clock ClockAdapter,
+	ids IDGenerator,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
 	log *slog.Logger,
 ) *DeactivateTeamUsersUseCase {
 	return &DeactivateTeamUsersUseCase{
-		users:  userStorage,
-		teams:  teamStorage,
-		prs:    prStorage,
-		random: random,
-		log:    log,
+		users:      userStorage,
+		teams:      teamStorage,
+		prs:        prStorage,
+		operations: operationStorage,
+		selector:   selector,
+		clock:      clock,
+		ids:        ids,
+		tx:         tx,
+		audit:      audit,
+		history:    history,
+		events:     events,
+		log:        log,
 	}
 }
 
-// DeactivateTeamUsers деактивирует всех пользователей команды и переназначает их открытые PR
-func (uc *DeactivateTeamUsersUseCase) DeactivateTeamUsers(ctx context.Context, teamName string) (DeactivateResult, error) {
-	uc.log.InfoContext(ctx, "массовая деактивация пользователей команды", "team_name", teamName)
+// DeactivateTeamUsers деактивирует пользователей команды и переназначает их открытые PR.
+// По умолчанию деактивируется вся команда, opts.UserIDs и opts.ExceptUserIDs сужают выборку.
+// С opts.DryRun строит план тем же путём, но ничего не сохраняет. Кто будет деактивирован и какие PR
+// затронуты, план показывает точно, а замены ориентировочные: курсор round_robin сдвигается только в памяти
+// запуска, случайные стратегии (random, history, weighted) при реальном запуске выберут заново.
+// Операция, PR, пользователи и аудит пишутся в одной транзакции.
+func (uc *DeactivateTeamUsersUseCase) DeactivateTeamUsers(ctx context.Context, teamName string, opts DeactivateOptions) (DeactivateResult, error) {
+	uc.log.InfoContext(ctx, "массовая деактивация пользователей команды",
+		"team_name", teamName,
+		"user_ids", opts.UserIDs,
+		"except_user_ids", opts.ExceptUserIDs,
+		"dry_run", opts.DryRun,
+	)
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (DeactivateResult, error) {
+		return uc.deactivateTeamUsers(ctx, teamName, opts)
+	})
+}
+
+func (uc *DeactivateTeamUsersUseCase) deactivateTeamUsers(ctx context.Context, teamName string, opts DeactivateOptions) (DeactivateResult, error) {
+	if len(opts.UserIDs) > 0 && len(opts.ExceptUserIDs) > 0 {
+		uc.log.WarnContext(ctx, "указаны оба фильтра пользователей", "team_name", teamName)
+		return DeactivateResult{}, domain.ErrInvalidUserFilter
+	}
+	if opts.DryRun {
+		ctx = withDryRun(ctx)
+	}
 
 	team, err := uc.teams.GetTeam(ctx, teamName)
 	if err != nil {
@@ -48,13 +133,17 @@ func (uc *DeactivateTeamUsersUseCase) DeactivateTeamUsers(ctx context.Context, t
 
 	if len(team.Users) == 0 {
 		uc.log.InfoContext(ctx, "команда пустая, ничего не делаем", "team_name", teamName)
-		return DeactivateResult{DeactivatedCount: 0, ReassignedPRCount: 0}, nil
+		return DeactivateResult{DryRun: opts.DryRun}, nil
 	}
 
-	userIDs := uc.getActiveUserIDs(team)
+	userIDs, err := uc.getActiveUserIDs(team, opts)
+	if err != nil {
+		uc.log.WarnContext(ctx, "пользователь не состоит в команде", "team_name", teamName, "error", err)
+		return DeactivateResult{}, err
+	}
 	if len(userIDs) == 0 {
-		uc.log.InfoContext(ctx, "все пользователи уже неактивны", "team_name", teamName)
-		return DeactivateResult{DeactivatedCount: 0, ReassignedPRCount: 0}, nil
+		uc.log.InfoContext(ctx, "все выбранные пользователи уже неактивны", "team_name", teamName)
+		return DeactivateResult{DryRun: opts.DryRun}, nil
 	}
 
 	affectedPRs, userIDMap, err := uc.filterAffectedPRs(ctx, userIDs)
@@ -62,37 +151,119 @@ func (uc *DeactivateTeamUsersUseCase) DeactivateTeamUsers(ctx context.Context, t
 		return DeactivateResult{}, err
 	}
 
-	reassignedCount, err := uc.reassignAffectedPRs(ctx, affectedPRs, userIDMap)
+	updatedPRs, reports, err := uc.planReassignments(ctx, affectedPRs, userIDMap)
 	if err != nil {
 		return DeactivateResult{}, err
 	}
 
-	deactivatedCount, err := uc.deactivateUsers(ctx, userIDs)
+	result := DeactivateResult{
+		DryRun:                 opts.DryRun,
+		DeactivatedCount:       len(userIDs),
+		ReassignedPRCount:      len(reports),
+		DeactivatedUserIDs:     userIDs,
+		PullRequests:           reports,
+		ReplacementsIndicative: opts.DryRun,
+	}
+	if opts.DryRun {
+		uc.log.InfoContext(ctx, "план массовой деактивации построен", "team", teamName, "deactivated", len(userIDs), "reassigned_prs", len(reports))
+		return result, nil
+	}
+
+	originals := make(map[string]domain.PullRequest, len(affectedPRs))
+	for _, pr := range affectedPRs {
+		originals[pr.ID] = pr
+	}
+
+	operation, err := uc.recordOperation(ctx, teamName, userIDs, originals, updatedPRs)
+	if err != nil {
+		return DeactivateResult{}, err
+	}
+	result.OperationID = operation.ID
+
+	tracker := startProgress(ctx, len(updatedPRs)+len(userIDs))
+	for i, pr := range updatedPRs {
+		if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
+			uc.log.ErrorContext(ctx, "ошибка обновления PR", "pr_id", pr.ID, "error", err)
+			return DeactivateResult{}, err
+		}
+		if err := uc.audit.Record(ctx, domain.AuditReviewersReplaced, domain.AuditEntityPullRequest, pr.ID, originals[pr.ID], pr); err != nil {
+			return DeactivateResult{}, err
+		}
+		entries := deactivationHistory(pr.ID, reports[i].Replacements)
+		if err := uc.history.Record(ctx, entries...); err != nil {
+			return DeactivateResult{}, err
+		}
+		if err := uc.events.Record(ctx, reviewerEvents(pr, entries)...); err != nil {
+			return DeactivateResult{}, err
+		}
+		tracker.step()
+	}
+
+	result.DeactivatedCount, err = uc.deactivateUsers(ctx, userIDs, tracker)
 	if err != nil {
 		return DeactivateResult{}, err
 	}
 
 	uc.log.InfoContext(ctx, "массовая деактивация завершена",
 		"team", teamName,
-		"deactivated", deactivatedCount,
-		"reassigned_prs", reassignedCount,
+		"deactivated", result.DeactivatedCount,
+		"reassigned_prs", len(reports),
+		"operation_id", result.OperationID,
 	)
 
-	return DeactivateResult{
-		DeactivatedCount:  deactivatedCount,
-		ReassignedPRCount: reassignedCount,
-	}, nil
+	return result, nil
+}
+
+// recordOperation сохраняет операцию деактивации с ревьюерами PR до и после неё
+func (uc *DeactivateTeamUsersUseCase) recordOperation(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+	originals map[string]domain.PullRequest,
+	updatedPRs []domain.PullRequest,
+) (domain.Operation, error) {
+	operation := domain.NewOperation(uc.ids.NewID(), domain.OperationTeamDeactivation, teamName, uc.clock.Now())
+	operation.UserIDs = userIDs
+
+	for _, pr := range updatedPRs {
+		original := originals[pr.ID]
+		operation.PullRequests = append(operation.PullRequests, domain.PullRequestSnapshot{
+			PullRequestID:   pr.ID,
+			ReviewersBefore: original.Reviewers,
+			RequiredBefore:  original.RequiredReviewers,
+			ReviewersAfter:  pr.Reviewers,
+		})
+	}
+
+	if err := uc.operations.CreateOperation(ctx, operation); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка сохранения операции", "team", teamName, "error", err)
+		return domain.Operation{}, err
+	}
+	return operation, nil
 }
 
-// getActiveUserIDs собирает ID активных пользователей команды
-func (uc *DeactivateTeamUsersUseCase) getActiveUserIDs(team domain.Team) []string {
+// getActiveUserIDs собирает ID активных пользователей команды, попавших под фильтр
+func (uc *DeactivateTeamUsersUseCase) getActiveUserIDs(team domain.Team, opts DeactivateOptions) ([]string, error) {
+	for _, userID := range append(append([]string(nil), opts.UserIDs...), opts.ExceptUserIDs...) {
+		if _, ok := findUser(team.Users, userID); !ok {
+			return nil, domain.ErrUserNotFound
+		}
+	}
+
 	userIDs := make([]string, 0, len(team.Users))
 	for _, user := range team.Users {
-		if user.IsActive {
-			userIDs = append(userIDs, user.ID)
+		if !user.IsActive {
+			continue
+		}
+		if len(opts.UserIDs) > 0 && !contains(opts.UserIDs, user.ID) {
+			continue
+		}
+		if contains(opts.ExceptUserIDs, user.ID) {
+			continue
 		}
+		userIDs = append(userIDs, user.ID)
 	}
-	return userIDs
+	return userIDs, nil
 }
 
 // filterAffectedPRs фильтрует открытые PR с ревьюверами из команды
@@ -110,7 +281,8 @@ func (uc *DeactivateTeamUsersUseCase) filterAffectedPRs(ctx context.Context, use
 
 	affectedPRs := make([]domain.PullRequest, 0)
 	for _, pr := range allPRs {
-		if pr.Status != domain.PRStatusOpen {
+		// черновики и закрытые PR на паузе, но после возврата в ревью деактивированный не должен остаться ревьюером
+		if pr.Status == domain.PRStatusMerged {
 			continue
 		}
 		if uc.hasAffectedReviewer(pr, userIDMap) {
@@ -132,124 +304,201 @@ func (uc *DeactivateTeamUsersUseCase) hasAffectedReviewer(pr domain.PullRequest,
 	return false
 }
 
-// reassignAffectedPRs переназначает ревьюверов во всех затронутых PR
-func (uc *DeactivateTeamUsersUseCase) reassignAffectedPRs(ctx context.Context, affectedPRs []domain.PullRequest, userIDMap map[string]bool) (int, error) {
-	reassignedCount := 0
+// planReassignments подбирает замены во всех затронутых PR, ничего не сохраняя.
+// Сначала подбираются все замены, чтобы нарушение политики команды не оставляло частичных изменений.
+func (uc *DeactivateTeamUsersUseCase) planReassignments(
+	ctx context.Context,
+	affectedPRs []domain.PullRequest,
+	userIDMap map[string]bool,
+) ([]domain.PullRequest, []PullRequestReport, error) {
+	updatedPRs := make([]domain.PullRequest, 0, len(affectedPRs))
+	reports := make([]PullRequestReport, 0, len(affectedPRs))
 	for _, pr := range affectedPRs {
-		updated, err := uc.reassignReviewersForPR(ctx, pr, userIDMap)
+		updated, replacements, err := uc.reassignReviewersForPR(ctx, pr, userIDMap)
 		if err != nil {
-			return 0, err
+			return nil, nil, err
 		}
-		if updated {
-			reassignedCount++
+		if len(replacements) == 0 {
+			continue
 		}
+		updatedPRs = append(updatedPRs, updated)
+		reports = append(reports, PullRequestReport{
+			PullRequestID:   pr.ID,
+			ReviewersBefore: pr.Reviewers,
+			ReviewersAfter:  updated.Reviewers,
+			Replacements:    replacements,
+		})
 	}
-	return reassignedCount, nil
+
+	return updatedPRs, reports, nil
 }
 
-// reassignReviewersForPR переназначает ревьюверов для одного PR
-func (uc *DeactivateTeamUsersUseCase) reassignReviewersForPR(ctx context.Context, pr domain.PullRequest, userIDMap map[string]bool) (bool, error) {
+// reassignReviewersForPR подбирает замены ревьюверов для одного PR, не сохраняя его.
+// Пустой список замен значит, что PR не меняется.
+func (uc *DeactivateTeamUsersUseCase) reassignReviewersForPR(
+	ctx context.Context,
+	pr domain.PullRequest,
+	userIDMap map[string]bool,
+) (domain.PullRequest, []ReviewerReplacement, error) {
 	authorTeam, err := uc.teams.GetTeam(ctx, pr.TeamName)
-	if err != nil {
+	authorTeamFound := err == nil
+	if !authorTeamFound {
 		uc.log.WarnContext(ctx, "не найдена команда автора PR", "pr_id", pr.ID, "team", pr.TeamName)
-		return false, nil
 	}
 
 	newReviewers := make([]string, 0, len(pr.Reviewers))
-	updated := false
+	replacements := make([]ReviewerReplacement, 0, len(pr.Reviewers))
 
-	for _, reviewerID := range pr.Reviewers {
+	for i, reviewerID := range pr.Reviewers {
 		if !userIDMap[reviewerID] {
 			newReviewers = append(newReviewers, reviewerID)
 			continue
 		}
 
-		replacement, found := uc.findReplacement(pr, reviewerID, authorTeam.Users, newReviewers)
-		if !found {
-			uc.log.WarnContext(ctx, "не найдена замена для ревьювера", "pr_id", pr.ID, "reviewer_id", reviewerID)
-			updated = true
+		if !authorTeamFound {
+			replacements = append(replacements, ReviewerReplacement{OldReviewerID: reviewerID, Reason: ReasonAuthorTeamNotFound})
+			continue
+		}
+
+		staying := append(append([]string(nil), newReviewers...), keptReviewers(pr.Reviewers[i+1:], userIDMap)...)
+		replacement, reason, err := uc.findReplacement(ctx, pr, reviewerID, authorTeam, staying, userIDMap)
+		if err != nil {
+			return pr, nil, err
+		}
+		if replacement == "" {
+			uc.log.WarnContext(ctx, "не найдена замена для ревьювера", "pr_id", pr.ID, "reviewer_id", reviewerID, "reason", reason)
+			replacements = append(replacements, ReviewerReplacement{OldReviewerID: reviewerID, Reason: reason})
 			continue
 		}
 
 		newReviewers = append(newReviewers, replacement)
-		updated = true
+		replacements = append(replacements, ReviewerReplacement{OldReviewerID: reviewerID, NewReviewerID: replacement})
 		uc.log.InfoContext(ctx, "ревьювер заменен", "pr_id", pr.ID, "old", reviewerID, "new", replacement)
 	}
 
-	if !updated {
-		return false, nil
+	if len(replacements) == 0 {
+		return pr, nil, nil
 	}
 
-	pr.Reviewers = newReviewers
-	if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
-		uc.log.ErrorContext(ctx, "ошибка обновления PR", "pr_id", pr.ID, "error", err)
-		return false, err
-	}
+	pr.AssignReviewers(newReviewers)
+	return pr, replacements, nil
+}
 
-	return true, nil
+// keptReviewers возвращает ревьюверов, которые не деактивируются
+func keptReviewers(reviewers []string, userIDMap map[string]bool) []string {
+	kept := make([]string, 0, len(reviewers))
+	for _, reviewerID := range reviewers {
+		if !userIDMap[reviewerID] {
+			kept = append(kept, reviewerID)
+		}
+	}
+	return kept
 }
 
 // deactivateUsers деактивирует всех указанных пользователей
-func (uc *DeactivateTeamUsersUseCase) deactivateUsers(ctx context.Context, userIDs []string) (int, error) {
+func (uc *DeactivateTeamUsersUseCase) deactivateUsers(ctx context.Context, userIDs []string, tracker *progress) (int, error) {
 	deactivatedCount := 0
 	for _, userID := range userIDs {
-		user, err := uc.users.GetUser(ctx, userID)
+		deactivated, err := uc.deactivateUser(ctx, userID)
 		if err != nil {
-			uc.log.WarnContext(ctx, "не найден пользователь", "user_id", userID, "error", err)
-			continue
-		}
-
-		if !user.IsActive {
-			continue
-		}
-
-		user.IsActive = false
-		if err := uc.users.UpdateUser(ctx, user); err != nil {
-			uc.log.ErrorContext(ctx, "ошибка деактивации пользователя", "user_id", userID, "error", err)
 			return 0, err
 		}
-		deactivatedCount++
+		if deactivated {
+			deactivatedCount++
+		}
+		tracker.step()
 	}
 	return deactivatedCount, nil
 }
 
-// findReplacement ищет подходящую замену для ревьювера
-func (uc *DeactivateTeamUsersUseCase) findReplacement(pr domain.PullRequest, oldReviewerID string, teamMembers []domain.User, currentReviewers []string) (string, bool) {
-	candidates := make([]string, 0)
+// deactivateUser деактивирует пользователя; false - не найден или уже неактивен
+func (uc *DeactivateTeamUsersUseCase) deactivateUser(ctx context.Context, userID string) (bool, error) {
+	user, err := uc.users.GetUser(ctx, userID)
+	if err != nil {
+		uc.log.WarnContext(ctx, "не найден пользователь", "user_id", userID, "error", err)
+		return false, nil
+	}
 
-	for _, member := range teamMembers {
+	if !user.IsActive {
+		return false, nil
+	}
+
+	before := user
+	user.IsActive = false
+	if err := uc.users.UpdateUser(ctx, user); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка деактивации пользователя", "user_id", userID, "error", err)
+		return false, err
+	}
+	if err := uc.audit.Record(ctx, domain.AuditUserDeactivated, domain.AuditEntityUser, user.ID, before, user); err != nil {
+		return false, err
+	}
+	if err := uc.events.Record(ctx, userActivityEvent(user)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findReplacement ищет подходящую замену для ревьювера с учётом политики команды автора.
+// staying - ревьюверы, которые останутся в PR после замены; деактивируемые из userIDMap
+// кандидатами не считаются. Если замены нет, возвращает причину.
+func (uc *DeactivateTeamUsersUseCase) findReplacement(
+	ctx context.Context,
+	pr domain.PullRequest,
+	oldReviewerID string,
+	authorTeam domain.Team,
+	staying []string,
+	userIDMap map[string]bool,
+) (string, string, error) {
+	candidates := make([]domain.User, 0)
+
+	for _, member := range authorTeam.Users {
 		if member.ID == pr.AuthorID {
 			continue
 		}
 		if !member.IsActive {
 			continue
 		}
-		if member.ID == oldReviewerID {
+		if member.ID == oldReviewerID || userIDMap[member.ID] {
 			continue
 		}
-		alreadyAssigned := false
-		for _, existingReviewer := range currentReviewers {
-			if existingReviewer == member.ID {
-				alreadyAssigned = true
-				break
-			}
-		}
-		if alreadyAssigned {
+		if contains(staying, member.ID) {
 			continue
 		}
 
-		candidates = append(candidates, member.ID)
+		candidates = append(candidates, member)
 	}
 
-	if len(candidates) == 0 {
-		return "", false
+	view := pr
+	view.Reviewers = staying
+	selected, err := selectWithSeniorPolicy(ctx, uc.selector, uc.users, authorTeam, view, candidates, 1)
+	if errors.Is(err, domain.ErrNoSeniorReviewer) {
+		uc.log.WarnContext(ctx, "не удалось выполнить политику senior-ревьюера", "pr_id", pr.ID, "reviewer_id", oldReviewerID)
+		return "", "", err
 	}
-
-	if uc.random != nil && len(candidates) > 1 {
-		uc.random.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка выбора замены ревьювера", "pr_id", pr.ID, "error", err)
+		return "", "", err
+	}
+	if len(selected) == 0 && len(candidates) == 0 {
+		return "", ReasonNoActiveCandidates, nil
 	}
+	if len(selected) == 0 {
+		// кандидаты есть, но стратегия никого не выбрала, например все с review_weight 0
+		return "", ReasonNoEligibleCandidates, nil
+	}
+
+	return selected[0].ID, "", nil
+}
 
-	return candidates[0], true
+// deactivationHistory описывает замены в PR для истории; замена без нового ревьюера - снятие.
+func deactivationHistory(prID string, replacements []ReviewerReplacement) []domain.PullRequestHistoryEntry {
+	entries := make([]domain.PullRequestHistoryEntry, 0, len(replacements))
+	for _, replacement := range replacements {
+		if replacement.NewReviewerID == "" {
+			entries = append(entries, historyRemoved(prID, replacement.OldReviewerID, domain.PRHistoryReasonDeactivation))
+			continue
+		}
+		entries = append(entries, historyReplaced(prID, replacement.OldReviewerID, replacement.NewReviewerID, domain.PRHistoryReasonDeactivation))
+	}
+	return entries
 }
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type DeleteUserIdentityUseCase struct {
+	identities UserIdentityStorage
+	tx         Transactor
+	audit      Auditor
+	log        *slog.Logger
+}
+
+func NewDeleteUserIdentityUseCase(identityStorage UserIdentityStorage, tx Transactor, audit Auditor, log *slog.Logger) *DeleteUserIdentityUseCase {
+	return &DeleteUserIdentityUseCase{
+		identities: identityStorage,
+		tx:         tx,
+		audit:      audit,
+		log:        log,
+	}
+}
+
+// Delete отвязывает учётную запись.
+func (uc *DeleteUserIdentityUseCase) Delete(ctx context.Context, id string) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		identity, err := uc.identities.GetUserIdentity(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "учётная запись не найдена", "identity_id", id, "error", err)
+			return err
+		}
+
+		if err := uc.identities.DeleteUserIdentity(ctx, id); err != nil {
+			uc.log.ErrorContext(ctx, "ошибка удаления учётной записи", "identity_id", id, "error", err)
+			return err
+		}
+
+		if err := uc.audit.Record(ctx, domain.AuditIdentityRemoved, domain.AuditEntityUser, identity.UserID, identity, nil); err != nil {
+			return err
+		}
+
+		uc.log.InfoContext(ctx, "учётная запись отвязана", "identity_id", id, "user_id", identity.UserID)
+		return nil
+	})
+}
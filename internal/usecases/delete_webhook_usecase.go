@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type DeleteWebhookUseCase struct {
+	webhooks WebhookStorage
+	tx       Transactor
+	audit    Auditor
+	log      *slog.Logger
+}
+
+func NewDeleteWebhookUseCase(webhookStorage WebhookStorage, tx Transactor, audit Auditor, log *slog.Logger) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{
+		webhooks: webhookStorage,
+		tx:       tx,
+		audit:    audit,
+		log:      log,
+	}
+}
+
+// Delete удаляет подписку вместе с журналом её доставок; недоставленное больше не отправляется.
+func (uc *DeleteWebhookUseCase) Delete(ctx context.Context, id string) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		subscription, err := uc.webhooks.GetWebhook(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "подписка на вебхук не найдена", "webhook_id", id, "error", err)
+			return err
+		}
+
+		if err := uc.webhooks.DeleteWebhook(ctx, id); err != nil {
+			uc.log.ErrorContext(ctx, "ошибка удаления подписки на вебхук", "webhook_id", id, "error", err)
+			return err
+		}
+
+		if err := uc.audit.Record(ctx, domain.AuditWebhookDeleted, domain.AuditEntityWebhook, id, subscription, nil); err != nil {
+			return err
+		}
+
+		uc.log.InfoContext(ctx, "подписка на вебхук удалена", "webhook_id", id)
+		return nil
+	})
+}
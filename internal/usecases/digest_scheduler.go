@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// digestAgeOrder порядок групп в дайджесте: самые залежавшиеся PR первыми
+var digestAgeOrder = []string{domain.DigestAgeStale, domain.DigestAgeWaiting, domain.DigestAgeFresh}
+
+// DigestScheduler раз в день, когда у подписанного пользователя наступает hour часов по его
+// часовому поясу, отправляет ему сводку открытых ревью. Дата отправки запоминается до отправки,
+// поэтому дайджест приходит не больше раза в день даже с несколькими экземплярами сервиса.
+type DigestScheduler struct {
+	users   UserStorage
+	prs     PullRequestStorage
+	digests ReviewDigestStorage
+	sender  notificationSender
+	clock   ClockAdapter
+	hour    int
+	poll    time.Duration
+	log     *slog.Logger
+}
+
+func NewDigestScheduler(
+	users UserStorage,
+	prs PullRequestStorage,
+	digests ReviewDigestStorage,
+	notifiers []Notifier,
+	identities IdentityResolver,
+	clock ClockAdapter,
+	hour int,
+	pollInterval time.Duration,
+	log *slog.Logger,
+) *DigestScheduler {
+	return &DigestScheduler{
+		users:   users,
+		prs:     prs,
+		digests: digests,
+		sender:  newNotificationSender(notifiers, identities, users, log),
+		clock:   clock,
+		hour:    hour,
+		poll:    pollInterval,
+		log:     log,
+	}
+}
+
+// Run проверяет, кому пора отправить дайджест, пока не отменён ctx.
+func (s *DigestScheduler) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if _, err := s.RunOnce(ctx); err != nil {
+			s.log.ErrorContext(ctx, "ошибка отправки дайджестов", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.poll):
+		}
+	}
+}
+
+// RunOnce отправляет дайджесты пользователям, у которых наступило время отправки.
+// Возвращает, сколько дайджестов отправлено.
+func (s *DigestScheduler) RunOnce(ctx context.Context) (int, error) {
+	users, err := s.users.ListUsers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	sent := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return sent, nil
+		}
+		local := user.LocalTime(now)
+		if !user.DigestEnabled || local.Hour() < s.hour {
+			continue
+		}
+
+		claimed, err := s.digests.MarkDigestSent(ctx, user.ID, local, now)
+		if err != nil {
+			s.log.ErrorContext(ctx, "ошибка сохранения дайджеста", "user_id", user.ID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		prs, err := s.prs.ListPullRequestsByReviewer(ctx, user.ID)
+		if err != nil {
+			s.log.ErrorContext(ctx, "не удалось получить ревью для дайджеста", "user_id", user.ID, "error", err)
+			continue
+		}
+		groups := s.groups(ctx, prs, now)
+		if len(groups) == 0 {
+			continue
+		}
+
+		s.sender.send(ctx, domain.Notification{
+			Kind:   domain.NotificationDigest,
+			UserID: user.ID,
+			Digest: groups,
+		})
+		sent++
+	}
+	return sent, nil
+}
+
+// groups раскладывает открытые PR по возрастным группам, внутри группы старые первыми.
+func (s *DigestScheduler) groups(ctx context.Context, prs []domain.PullRequest, now time.Time) []domain.DigestGroup {
+	byAge := make(map[string][]domain.DigestItem)
+	authors := make(map[string]string)
+	for _, pr := range prs {
+		if pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		if _, ok := authors[pr.AuthorID]; !ok {
+			authors[pr.AuthorID] = s.sender.userName(ctx, pr.AuthorID)
+		}
+
+		age := now.Sub(pr.CreatedAt)
+		group := domain.DigestAge(age)
+		byAge[group] = append(byAge[group], domain.DigestItem{
+			PullRequestID: pr.ID,
+			Title:         pr.Title,
+			AuthorName:    authors[pr.AuthorID],
+			Age:           age,
+		})
+	}
+
+	var groups []domain.DigestGroup
+	for _, age := range digestAgeOrder {
+		items := byAge[age]
+		if len(items) == 0 {
+			continue
+		}
+		slices.SortStableFunc(items, func(a, b domain.DigestItem) int {
+			return cmp.Compare(b.Age, a.Age)
+		})
+		groups = append(groups, domain.DigestGroup{Age: age, Items: items})
+	}
+	return groups
+}
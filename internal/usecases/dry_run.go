@@ -0,0 +1,24 @@
+package usecases
+
+import "context"
+
+type dryRunKey struct{}
+
+// dryRun состояние пробного запуска. Стратегии выбора не меняют своё сохранённое состояние,
+// а ведут его копию здесь, чтобы план по нескольким PR совпадал с реальным запуском.
+// Пробный запуск однопоточный, поэтому без блокировок.
+type dryRun struct {
+	// rotations курсоры round_robin по командам, сдвинутые в этом запуске
+	rotations map[string]string
+}
+
+// withDryRun помечает контекст как пробный запуск со своим состоянием стратегий.
+func withDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, &dryRun{rotations: make(map[string]string)})
+}
+
+// dryRunFrom состояние пробного запуска; nil - запуск настоящий.
+func dryRunFrom(ctx context.Context) *dryRun {
+	run, _ := ctx.Value(dryRunKey{}).(*dryRun)
+	return run
+}
@@ -0,0 +1,44 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type EnqueueJobUseCase struct {
+	jobs  JobStorage
+	clock ClockAdapter
+	ids   IDGenerator
+	log   *slog.Logger
+}
+
+func NewEnqueueJobUseCase(jobStorage JobStorage, clock ClockAdapter, ids IDGenerator, log *slog.Logger) *EnqueueJobUseCase {
+	return &EnqueueJobUseCase{
+		jobs:  jobStorage,
+		clock: clock,
+		ids:   ids,
+		log:   log,
+	}
+}
+
+// Enqueue ставит задачу в очередь; payload сохраняется в JSON и передаётся обработчику типа.
+// Задача выполняется от имени автора из ctx.
+func (uc *EnqueueJobUseCase) Enqueue(ctx context.Context, jobType string, payload any) (domain.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка сериализации параметров задачи", "type", jobType, "error", err)
+		return domain.Job{}, err
+	}
+
+	job := domain.NewJob(uc.ids.NewID(), jobType, ActorFromContext(ctx), data, uc.clock.Now())
+	if err := uc.jobs.CreateJob(ctx, job); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка постановки задачи", "type", jobType, "error", err)
+		return domain.Job{}, err
+	}
+
+	uc.log.InfoContext(ctx, "задача поставлена в очередь", "job_id", job.ID, "type", jobType)
+	return job, nil
+}
@@ -0,0 +1,262 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+const (
+	// eventStreamBatchSize сколько событий читается из журнала за раз
+	eventStreamBatchSize = 100
+	// eventStreamBuffer сколько событий ждёт медленного подписчика, прежде чем его отключат
+	eventStreamBuffer = 64
+)
+
+// EventStream раздаёт события outbox подписчикам потока в реальном времени.
+// Один опрос журнала на экземпляр сервиса, сколько бы ни было подписчиков; журналом служит
+// таблица outbox, поэтому поток видит события, записанные любым экземпляром.
+type EventStream struct {
+	events EventLogStorage
+	poll   time.Duration
+	log    *slog.Logger
+
+	mu sync.Mutex
+	// cursor id последнего разосланного события, позиция в порядке потока, а не максимум id;
+	// ready - курсор уже прочитан из журнала
+	cursor      int64
+	ready       bool
+	closed      bool
+	subscribers map[*EventSubscription]struct{}
+}
+
+func NewEventStream(events EventLogStorage, pollInterval time.Duration, log *slog.Logger) *EventStream {
+	return &EventStream{
+		events:      events,
+		poll:        pollInterval,
+		log:         log,
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// EventSubscription подписка на поток: сначала Backlog, затем события из Events.
+// Events закрывается, когда поток остановлен или подписчик не успевает читать;
+// клиенту достаточно переподключиться с id последнего полученного события.
+type EventSubscription struct {
+	Backlog []domain.OutboxMessage
+	Events  <-chan domain.OutboxMessage
+	// Reset lastEventID нет в журнале, поэтому пропущенное не восстановить:
+	// Backlog пуст, и клиенту нужно заново прочитать состояние
+	Reset bool
+
+	stream *EventStream
+	filter domain.EventFilter
+	events chan domain.OutboxMessage
+}
+
+// Close отписывается от потока; повторный вызов ничего не делает.
+func (s *EventSubscription) Close() {
+	s.stream.mu.Lock()
+	defer s.stream.mu.Unlock()
+	s.stream.remove(s)
+}
+
+// Subscribe подписывает на события по filter. Если lastEventID не 0, в Backlog попадают
+// подходящие события журнала после него, которые уже разосланы остальным подписчикам.
+// Неизвестный lastEventID не ошибка: подписка начинается с текущего момента с флагом Reset.
+func (s *EventStream) Subscribe(ctx context.Context, filter domain.EventFilter, lastEventID int64) (*EventSubscription, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, domain.ErrEventStreamClosed
+	}
+	if err := s.ensureCursor(ctx); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	events := make(chan domain.OutboxMessage, eventStreamBuffer)
+	sub := &EventSubscription{
+		Events: events,
+		stream: s,
+		filter: filter,
+		events: events,
+	}
+	s.subscribers[sub] = struct{}{}
+	cursor := s.cursor
+	s.mu.Unlock()
+
+	// в пустом потоке нет ни одного id, который мог получить клиент
+	if lastEventID > 0 && cursor == 0 {
+		sub.Reset = true
+	}
+
+	// события после cursor придут через Events, журнал нужен только до него включительно
+	for after := lastEventID; after > 0 && cursor > 0; {
+		messages, err := s.events.ListOutboxMessagesAfter(ctx, after, cursor, eventStreamBatchSize)
+		if errors.Is(err, domain.ErrEventNotFound) {
+			s.log.WarnContext(ctx, "Last-Event-ID нет в журнале, поток начинается заново", "last_event_id", lastEventID)
+			sub.Backlog, sub.Reset = nil, true
+			break
+		}
+		if err != nil {
+			sub.Close()
+			return nil, err
+		}
+		if len(messages) == 0 {
+			break
+		}
+		for _, message := range messages {
+			if eventMatches(filter, message) {
+				sub.Backlog = append(sub.Backlog, message)
+			}
+		}
+		after = messages[len(messages)-1].ID
+	}
+	return sub, nil
+}
+
+// Run рассылает новые события журнала, пока не отменён ctx; потом закрывает поток.
+func (s *EventStream) Run(ctx context.Context) {
+	defer s.Close()
+
+	for ctx.Err() == nil {
+		read, err := s.RunOnce(ctx)
+		if err != nil {
+			s.log.ErrorContext(ctx, "ошибка чтения журнала событий", "error", err)
+		}
+		if read > 0 && read == eventStreamBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.poll):
+		}
+	}
+}
+
+// RunOnce нумерует закоммиченные события, читает одну пачку новых и рассылает подписчикам.
+// Возвращает размер пачки.
+func (s *EventStream) RunOnce(ctx context.Context) (int, error) {
+	if _, err := s.events.SequenceOutboxMessages(ctx, eventStreamBatchSize); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	if err := s.ensureCursor(ctx); err != nil {
+		s.mu.Unlock()
+		return 0, err
+	}
+	cursor := s.cursor
+	s.mu.Unlock()
+
+	messages, err := s.events.ListOutboxMessagesAfter(ctx, cursor, 0, eventStreamBatchSize)
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, message := range messages {
+		for sub := range s.subscribers {
+			if !eventMatches(sub.filter, message) {
+				continue
+			}
+			select {
+			case sub.events <- message:
+			default:
+				s.log.WarnContext(ctx, "подписчик потока событий не успевает, отключаем", "id", message.ID)
+				s.remove(sub)
+			}
+		}
+	}
+	s.cursor = messages[len(messages)-1].ID
+	return len(messages), nil
+}
+
+// Close останавливает поток и закрывает все подписки, например при остановке сервера.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		s.remove(sub)
+	}
+}
+
+// Subscribers сколько сейчас подписчиков.
+func (s *EventStream) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers)
+}
+
+// ensureCursor при первом обращении начинает поток с конца журнала. Вызывается под mu.
+func (s *EventStream) ensureCursor(ctx context.Context) error {
+	if s.ready {
+		return nil
+	}
+	// события, закоммиченные до подключения, не должны прийти как новые
+	for {
+		sequenced, err := s.events.SequenceOutboxMessages(ctx, eventStreamBatchSize)
+		if err != nil {
+			return err
+		}
+		if sequenced < eventStreamBatchSize {
+			break
+		}
+	}
+	cursor, err := s.events.LastOutboxMessageID(ctx)
+	if err != nil {
+		return err
+	}
+	s.cursor = cursor
+	s.ready = true
+	return nil
+}
+
+// remove убирает подписчика и закрывает его канал. Вызывается под mu.
+func (s *EventStream) remove(sub *EventSubscription) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
+
+// eventParticipants поля событий, по которым фильтруется поток; у всех событий они называются одинаково.
+type eventParticipants struct {
+	TeamName      string   `json:"team_name"`
+	AuthorID      string   `json:"author_id"`
+	ReviewerID    string   `json:"reviewer_id"`
+	OldReviewerID string   `json:"old_reviewer_id"`
+	NewReviewerID string   `json:"new_reviewer_id"`
+	UserID        string   `json:"user_id"`
+	Reviewers     []string `json:"reviewers"`
+}
+
+// eventMatches подходит ли событие под фильтр потока.
+func eventMatches(filter domain.EventFilter, message domain.OutboxMessage) bool {
+	if filter.TeamName == "" && filter.UserID == "" {
+		return true
+	}
+
+	var event eventParticipants
+	if err := json.Unmarshal(message.Payload, &event); err != nil {
+		return false
+	}
+	if filter.TeamName != "" && event.TeamName != filter.TeamName {
+		return false
+	}
+	if filter.UserID == "" {
+		return true
+	}
+	ids := append([]string{event.AuthorID, event.ReviewerID, event.OldReviewerID, event.NewReviewerID, event.UserID}, event.Reviewers...)
+	return slices.Contains(ids, filter.UserID)
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type GetJobUseCase struct {
+	jobs JobStorage
+	log  *slog.Logger
+}
+
+func NewGetJobUseCase(jobStorage JobStorage, log *slog.Logger) *GetJobUseCase {
+	return &GetJobUseCase{
+		jobs: jobStorage,
+		log:  log,
+	}
+}
+
+// Get возвращает задачу с прогрессом и результатом.
+func (uc *GetJobUseCase) Get(ctx context.Context, id string) (domain.Job, error) {
+	job, err := uc.jobs.GetJob(ctx, id)
+	if err != nil {
+		uc.log.WarnContext(ctx, "задача не найдена", "job_id", id, "error", err)
+		return domain.Job{}, err
+	}
+	return job, nil
+}
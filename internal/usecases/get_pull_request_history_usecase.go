@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type GetPullRequestHistoryUseCase struct {
+	prs     PullRequestStorage
+	history PullRequestHistoryStorage
+	log     *slog.Logger
+}
+
+func NewGetPullRequestHistoryUseCase(prStorage PullRequestStorage, historyStorage PullRequestHistoryStorage, log *slog.Logger) *GetPullRequestHistoryUseCase {
+	return &GetPullRequestHistoryUseCase{
+		prs:     prStorage,
+		history: historyStorage,
+		log:     log,
+	}
+}
+
+// History возвращает события PR от создания до текущего состояния.
+func (uc *GetPullRequestHistoryUseCase) History(ctx context.Context, prID string) ([]domain.PullRequestHistoryEntry, error) {
+	if _, err := uc.prs.GetPullRequest(ctx, prID); err != nil {
+		uc.log.WarnContext(ctx, "pull request не найден", "pr_id", prID, "error", err)
+		return nil, err
+	}
+
+	entries, err := uc.history.ListPullRequestHistory(ctx, prID)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка чтения истории PR", "pr_id", prID, "error", err)
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// HistoryReviewerSelector снижает шанс ревьюеров, которые часто ревьюили автора
+// в последних window pull request, но не исключает их.
+type HistoryReviewerSelector struct {
+	prs    PullRequestStorage
+	rand   RandomAdapter
+	window int
+	log    *slog.Logger
+}
+
+func NewHistoryReviewerSelector(prStorage PullRequestStorage, random RandomAdapter, window int, log *slog.Logger) *HistoryReviewerSelector {
+	return &HistoryReviewerSelector{
+		prs:    prStorage,
+		rand:   random,
+		window: window,
+		log:    log,
+	}
+}
+
+// Select делает взвешенную выборку с весом ReviewWeight/(1+n), где n - число недавних пар
+// с автором: кто ревьюил автора дважды, выпадает втрое реже свежего. С seeded RandomAdapter
+// выбор детерминирован.
+func (s *HistoryReviewerSelector) Select(ctx context.Context, pr domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	if len(candidates) < 2 {
+		return firstUsers(append([]domain.User(nil), candidates...), count), nil
+	}
+
+	pairings, err := s.pairingCounts(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := weightedOrder(s.rand, candidates, func(user domain.User) float64 {
+		return user.ReviewWeight / float64(1+pairings[user.ID])
+	})
+	return firstUsers(ordered, count), nil
+}
+
+// pairingCounts считает, сколько раз каждый пользователь ревьюил автора в последних PR.
+func (s *HistoryReviewerSelector) pairingCounts(ctx context.Context, pr domain.PullRequest) (map[string]int, error) {
+	counts := make(map[string]int)
+	if s.window <= 0 {
+		return counts, nil
+	}
+
+	history, err := s.prs.ListRecentPullRequestsByAuthor(ctx, pr.AuthorID, s.window+1)
+	if err != nil {
+		s.log.ErrorContext(ctx, "ошибка получения истории ревью автора", "author_id", pr.AuthorID, "error", err)
+		return nil, err
+	}
+
+	seen := 0
+	for _, past := range history {
+		if past.ID == pr.ID {
+			continue
+		}
+		if seen == s.window {
+			break
+		}
+		seen++
+		for _, reviewerID := range past.Reviewers {
+			counts[reviewerID]++
+		}
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// IdentityLookup IdentityResolver поверх привязанных учётных записей; общий для всех интеграций.
+type IdentityLookup struct {
+	identities UserIdentityStorage
+}
+
+func NewIdentityLookup(identityStorage UserIdentityStorage) *IdentityLookup {
+	return &IdentityLookup{identities: identityStorage}
+}
+
+// ResolveUser возвращает ID пользователя по логину во внешней системе.
+func (l *IdentityLookup) ResolveUser(ctx context.Context, provider, login string) (string, error) {
+	identity, err := l.identities.FindUserIdentity(ctx, provider, login)
+	if err != nil {
+		return "", err
+	}
+	return identity.UserID, nil
+}
+
+// ResolveLogin возвращает логин пользователя во внешней системе.
+func (l *IdentityLookup) ResolveLogin(ctx context.Context, provider, userID string) (string, error) {
+	identities, err := l.identities.ListUserIdentities(ctx, domain.UserIdentityFilter{UserID: userID, Provider: provider})
+	if err != nil {
+		return "", err
+	}
+	if len(identities) == 0 {
+		return "", domain.ErrIdentityNotFound
+	}
+	return identities[0].Handle, nil
+}
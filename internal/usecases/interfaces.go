@@ -11,6 +11,8 @@ type UserStorage interface {
 	CreateUser(ctx context.Context, user domain.User) error
 	ListUsers(ctx context.Context) ([]domain.User, error)
 	GetUser(ctx context.Context, id string) (domain.User, error)
+	// LockUser возвращает пользователя и блокирует его до конца транзакции use case.
+	LockUser(ctx context.Context, id string) (domain.User, error)
 	UpdateUser(ctx context.Context, user domain.User) error
 }
 
@@ -18,6 +20,7 @@ type TeamStorage interface {
 	CreateTeam(ctx context.Context, team domain.Team) error
 	ListTeams(ctx context.Context) ([]domain.Team, error)
 	GetTeam(ctx context.Context, name string) (domain.Team, error)
+	UpdateTeam(ctx context.Context, team domain.Team) error
 }
 
 type PullRequestStorage interface {
@@ -26,6 +29,8 @@ type PullRequestStorage interface {
 	GetPullRequest(ctx context.Context, id string) (domain.PullRequest, error)
 	UpdatePullRequest(ctx context.Context, pr domain.PullRequest) error
 	ListPullRequestsByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error)
+	ListOpenPullRequestsByTeam(ctx context.Context, teamName string) ([]domain.PullRequest, error)
+	ListRecentPullRequestsByAuthor(ctx context.Context, authorID string, limit int) ([]domain.PullRequest, error)
 }
 
 type ClockAdapter interface {
@@ -34,4 +39,188 @@ type ClockAdapter interface {
 
 type RandomAdapter interface {
 	Shuffle(n int, swap func(i, j int))
+	Float64() float64
+}
+
+// ReviewerSelector упорядочивает кандидатов по предпочтению стратегии и возвращает первых count.
+// Use case'ы просят упорядочить всех кандидатов и вызывают Select один раз на выбор.
+type ReviewerSelector interface {
+	Select(ctx context.Context, pr domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error)
+}
+
+// ReviewerSelectionRecorder стратегия с состоянием, которой нужен итоговый выбор:
+// политика senior может заменить предложенного Select ревьюера. Получает только выбранных
+// по порядку стратегии, без добавленных политикой. Вызывается один раз на выбор, в той же транзакции.
+type ReviewerSelectionRecorder interface {
+	RecordSelection(ctx context.Context, pr domain.PullRequest, selected []domain.User) error
+}
+
+// RotationStorage хранит курсор ротации ревьюеров команды.
+type RotationStorage interface {
+	// GetRotation возвращает последнего назначенного без блокировки.
+	GetRotation(ctx context.Context, teamName string) (string, error)
+	// LockRotation возвращает последнего назначенного и блокирует курсор до конца транзакции use case,
+	// поэтому параллельные экземпляры сервиса выбирают по курсору строго по очереди.
+	LockRotation(ctx context.Context, teamName string) (string, error)
+	// SetRotation сохраняет последнего назначенного.
+	SetRotation(ctx context.Context, teamName, lastUserID string) error
+}
+
+// ReviewerTopUp дозаполняет открытые PR команды, где ревьюеров меньше нормы.
+type ReviewerTopUp interface {
+	TopUpTeam(ctx context.Context, teamName string) (int, error)
+}
+
+// AbsenceUpdater снимает закончившиеся отсутствия; реализуется SetUserAbsenceUseCase.
+type AbsenceUpdater interface {
+	ClearExpiredAbsence(ctx context.Context, id string) (bool, error)
+}
+
+// OperationStorage хранит массовые операции и их исходное состояние.
+type OperationStorage interface {
+	CreateOperation(ctx context.Context, operation domain.Operation) error
+	// LockOperation возвращает операцию и блокирует её до конца транзакции use case.
+	LockOperation(ctx context.Context, id string) (domain.Operation, error)
+	UpdateOperation(ctx context.Context, operation domain.Operation) error
+}
+
+// IDGenerator выдаёт уникальные идентификаторы.
+type IDGenerator interface {
+	NewID() string
+}
+
+// JobStorage очередь фоновых задач.
+type JobStorage interface {
+	CreateJob(ctx context.Context, job domain.Job) error
+	GetJob(ctx context.Context, id string) (domain.Job, error)
+	// ClaimJob переводит в RUNNING ожидающую задачу или зависшую, heartbeat которой старше staleBefore.
+	// false - задач нет.
+	ClaimJob(ctx context.Context, now, staleBefore time.Time) (domain.Job, bool, error)
+	UpdateJob(ctx context.Context, job domain.Job) error
+}
+
+// Transactor выполняет fn в транзакции; хранилища, получившие её ctx, пишут в ту же транзакцию.
+// Вложенный вызов использует внешнюю транзакцию.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// AuditStorage журнал аудита, только добавление и чтение.
+type AuditStorage interface {
+	CreateAuditEvent(ctx context.Context, event domain.AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error)
+}
+
+// Auditor записывает изменение сущности в журнал аудита от имени автора из контекста.
+// Вызывается внутри транзакции изменения, ошибка записи откатывает изменение.
+type Auditor interface {
+	Record(ctx context.Context, action, entityType, entityID string, before, after any) error
+}
+
+// PullRequestHistoryStorage история назначений PR, только добавление и чтение.
+type PullRequestHistoryStorage interface {
+	AddPullRequestHistory(ctx context.Context, entries []domain.PullRequestHistoryEntry) error
+	ListPullRequestHistory(ctx context.Context, prID string) ([]domain.PullRequestHistoryEntry, error)
+}
+
+// HistoryRecorder дописывает события в историю PR от имени автора из контекста.
+// Как и Auditor, вызывается внутри транзакции изменения PR.
+type HistoryRecorder interface {
+	Record(ctx context.Context, entries ...domain.PullRequestHistoryEntry) error
+}
+
+// OutboxStorage очередь доменных событий на доставку.
+type OutboxStorage interface {
+	AddOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error
+	ClaimOutboxMessages(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error)
+	MarkOutboxDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	MarkOutboxFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+}
+
+// EventLogStorage чтение outbox как журнала событий в порядке потока. Порядок устойчивый:
+// новое сообщение всегда встаёт после уже прочитанных, хотя его id может быть меньше.
+// В поток сообщение попадает после SequenceOutboxMessages. Позиция задаётся id сообщения;
+// untilID 0 - без верхней границы, неизвестный afterID - domain.ErrEventNotFound.
+type EventLogStorage interface {
+	SequenceOutboxMessages(ctx context.Context, limit int) (int, error)
+	ListOutboxMessagesAfter(ctx context.Context, afterID, untilID int64, limit int) ([]domain.OutboxMessage, error)
+	LastOutboxMessageID(ctx context.Context) (int64, error)
+}
+
+// EventRecorder записывает доменные события в outbox от имени автора из контекста.
+// Вызывается внутри транзакции изменения, поэтому событие появляется только вместе с ним.
+type EventRecorder interface {
+	Record(ctx context.Context, events ...domain.Event) error
+}
+
+// EventPublisher доставляет событие из outbox во внешнюю систему.
+// Доставка "хотя бы один раз": получатель должен отбрасывать повторы по ID сообщения.
+type EventPublisher interface {
+	Publish(ctx context.Context, message domain.OutboxMessage) error
+}
+
+// WebhookStorage подписки на вебхуки и очередь их доставок.
+type WebhookStorage interface {
+	CreateWebhook(ctx context.Context, subscription domain.WebhookSubscription) error
+	GetWebhook(ctx context.Context, id string) (domain.WebhookSubscription, error)
+	ListWebhooks(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	CreateWebhookDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	ClaimWebhookDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// WebhookSender отправляет POST на url и возвращает код ответа.
+// Ответ не из 2xx возвращается вместе с ошибкой.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, body []byte, headers map[string]string) (int, error)
+}
+
+// UserIdentityStorage учётные записи пользователей во внешних системах.
+type UserIdentityStorage interface {
+	CreateUserIdentity(ctx context.Context, identity domain.UserIdentity) error
+	GetUserIdentity(ctx context.Context, id string) (domain.UserIdentity, error)
+	FindUserIdentity(ctx context.Context, provider, handle string) (domain.UserIdentity, error)
+	ListUserIdentities(ctx context.Context, filter domain.UserIdentityFilter) ([]domain.UserIdentity, error)
+	UpdateUserIdentity(ctx context.Context, identity domain.UserIdentity) error
+	DeleteUserIdentity(ctx context.Context, id string) error
+}
+
+// IdentityResolver сопоставляет логины во внешних системах с ID пользователей сервиса.
+// Без привязанной учётной записи возвращает domain.ErrIdentityNotFound.
+type IdentityResolver interface {
+	ResolveUser(ctx context.Context, provider, login string) (string, error)
+	ResolveLogin(ctx context.Context, provider, userID string) (string, error)
+}
+
+// ReviewerPublisher запрашивает и снимает ревью в pull request на хостинге кода.
+// Ошибка с domain.ErrReviewRequestRejected означает, что повтор не поможет.
+type ReviewerPublisher interface {
+	RequestReviewers(ctx context.Context, repository string, number int, logins []string) error
+	RemoveReviewRequests(ctx context.Context, repository string, number int, logins []string) error
+}
+
+// Notifier канал личных уведомлений: чат или почта.
+// Получатель адресуется учётной записью пользователя в Provider().
+type Notifier interface {
+	Provider() string
+	Notify(ctx context.Context, handle string, notification domain.Notification) error
+}
+
+// NotificationDeliveryStorage журнал доставленных уведомлений о назначениях.
+type NotificationDeliveryStorage interface {
+	ListNotificationDeliveries(ctx context.Context, messageID int64) ([]domain.NotificationDelivery, error)
+	CreateNotificationDelivery(ctx context.Context, delivery domain.NotificationDelivery) error
+}
+
+// ReviewReminderStorage просроченные ревью и отправленные по ним напоминания.
+type ReviewReminderStorage interface {
+	ListOverdueReviews(ctx context.Context, assignedBefore time.Time, limit int) ([]domain.OverdueReview, error)
+	MarkReviewReminded(ctx context.Context, review domain.OverdueReview, sentAt time.Time) (bool, error)
+}
+
+// ReviewDigestStorage отметки об отправленных ежедневных дайджестах.
+type ReviewDigestStorage interface {
+	MarkDigestSent(ctx context.Context, userID string, localDate, sentAt time.Time) (bool, error)
 }
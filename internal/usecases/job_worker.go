@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// JobFunc выполняет задачу одного типа по её параметрам; результат сохраняется в JSON.
+type JobFunc func(ctx context.Context, payload []byte) (any, error)
+
+const (
+	// maxJobAttempts сколько раз задачу забирают в работу, прежде чем признать её неисполнимой
+	maxJobAttempts = 3
+	// jobLeaseMargin запас сверх таймаута, после которого RUNNING-задача считается брошенной
+	jobLeaseMargin = 30 * time.Second
+)
+
+// JobWorker забирает задачи из очереди и выполняет их с таймаутом.
+// Run безопасно запускать в нескольких горутинах и экземплярах сервиса.
+type JobWorker struct {
+	jobs     JobStorage
+	handlers map[string]JobFunc
+	clock    ClockAdapter
+	timeout  time.Duration
+	poll     time.Duration
+	log      *slog.Logger
+}
+
+func NewJobWorker(
+	jobStorage JobStorage,
+	handlers map[string]JobFunc,
+	clock ClockAdapter,
+	timeout time.Duration,
+	pollInterval time.Duration,
+	log *slog.Logger,
+) *JobWorker {
+	return &JobWorker{
+		jobs:     jobStorage,
+		handlers: handlers,
+		clock:    clock,
+		timeout:  timeout,
+		poll:     pollInterval,
+		log:      log,
+	}
+}
+
+// Run выполняет задачи, пока не отменён ctx; при пустой очереди ждёт poll.
+func (w *JobWorker) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := w.RunOnce(ctx)
+		if err != nil {
+			w.log.ErrorContext(ctx, "ошибка получения задачи", "error", err)
+		}
+		if processed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.poll):
+		}
+	}
+}
+
+// RunOnce забирает и выполняет одну задачу; false - очередь пуста.
+// Задача, прерванная отменой ctx, остаётся RUNNING и после истечения аренды
+// достаётся следующему обработчику, в том числе после перезапуска сервиса.
+func (w *JobWorker) RunOnce(ctx context.Context) (bool, error) {
+	now := w.clock.Now()
+	job, found, err := w.jobs.ClaimJob(ctx, now, now.Add(-(w.timeout + jobLeaseMargin)))
+	if err != nil || !found {
+		return false, err
+	}
+
+	w.log.InfoContext(ctx, "выполняем задачу", "job_id", job.ID, "type", job.Type, "attempt", job.Attempts)
+
+	if job.Attempts > maxJobAttempts {
+		w.finish(ctx, job, nil, fmt.Errorf("задача не завершилась за %d попытки", maxJobAttempts))
+		return true, nil
+	}
+
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		w.finish(ctx, job, nil, fmt.Errorf("неизвестный тип задачи %q", job.Type))
+		return true, nil
+	}
+
+	runCtx, cancel := context.WithTimeout(WithActor(ctx, job.Actor), w.timeout)
+	defer cancel()
+	runCtx = withProgress(runCtx, func(done, total int) {
+		job.SetProgress(done, total, w.clock.Now())
+		if err := w.jobs.UpdateJob(ctx, job); err != nil {
+			w.log.WarnContext(ctx, "ошибка сохранения прогресса задачи", "job_id", job.ID, "error", err)
+			if errors.Is(err, domain.ErrJobLeaseLost) {
+				cancel()
+			}
+		}
+	})
+
+	result, err := runJob(runCtx, handler, job.Payload)
+	if ctx.Err() != nil {
+		w.log.WarnContext(ctx, "задача прервана остановкой обработчика", "job_id", job.ID)
+		return true, nil
+	}
+	if err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("превышено время выполнения %s: %w", w.timeout, err)
+	}
+
+	w.finish(ctx, job, result, err)
+	return true, nil
+}
+
+// runJob вызывает обработчик, превращая панику в ошибку задачи.
+func runJob(ctx context.Context, handler JobFunc, payload []byte) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("паника в обработчике задачи: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// finish сохраняет итог задачи.
+func (w *JobWorker) finish(ctx context.Context, job domain.Job, result any, runErr error) {
+	now := w.clock.Now()
+	if runErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			runErr = fmt.Errorf("ошибка сериализации результата: %w", err)
+		} else {
+			job.Succeed(data, now)
+		}
+	}
+	if runErr != nil {
+		job.Fail(runErr.Error(), now)
+	}
+
+	if err := w.jobs.UpdateJob(ctx, job); err != nil {
+		w.log.ErrorContext(ctx, "ошибка сохранения итога задачи", "job_id", job.ID, "error", err)
+		return
+	}
+
+	if runErr != nil {
+		w.log.WarnContext(ctx, "задача завершилась с ошибкой", "job_id", job.ID, "type", job.Type, "error", runErr)
+		return
+	}
+	w.log.InfoContext(ctx, "задача выполнена", "job_id", job.ID, "type", job.Type)
+}
@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type ListAuditEventsUseCase struct {
+	audit AuditStorage
+	log   *slog.Logger
+}
+
+func NewListAuditEventsUseCase(auditStorage AuditStorage, log *slog.Logger) *ListAuditEventsUseCase {
+	return &ListAuditEventsUseCase{
+		audit: auditStorage,
+		log:   log,
+	}
+}
+
+// List возвращает события журнала аудита по фильтру, новые первыми.
+func (uc *ListAuditEventsUseCase) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		uc.log.WarnContext(ctx, "некорректный лимит аудита", "limit", filter.Limit)
+		return nil, domain.ErrInvalidAuditFilter
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		uc.log.WarnContext(ctx, "некорректный период аудита", "from", filter.From, "to", filter.To)
+		return nil, domain.ErrInvalidAuditFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+
+	events, err := uc.audit.ListAuditEvents(ctx, filter)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка чтения журнала аудита", "error", err)
+		return nil, err
+	}
+	return events, nil
+}
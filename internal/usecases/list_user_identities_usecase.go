@@ -0,0 +1,36 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type ListUserIdentitiesUseCase struct {
+	identities UserIdentityStorage
+	log        *slog.Logger
+}
+
+func NewListUserIdentitiesUseCase(identityStorage UserIdentityStorage, log *slog.Logger) *ListUserIdentitiesUseCase {
+	return &ListUserIdentitiesUseCase{
+		identities: identityStorage,
+		log:        log,
+	}
+}
+
+// List возвращает учётные записи по пользователю и системе.
+func (uc *ListUserIdentitiesUseCase) List(ctx context.Context, filter domain.UserIdentityFilter) ([]domain.UserIdentity, error) {
+	if filter.Provider != "" && !slices.Contains(domain.IdentityProviders, filter.Provider) {
+		uc.log.WarnContext(ctx, "неизвестная внешняя система", "provider", filter.Provider)
+		return nil, domain.ErrInvalidIdentity
+	}
+
+	identities, err := uc.identities.ListUserIdentities(ctx, filter)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения учётных записей", "error", err)
+		return nil, err
+	}
+	return identities, nil
+}
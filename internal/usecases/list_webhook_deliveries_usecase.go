@@ -0,0 +1,48 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 500
+)
+
+type ListWebhookDeliveriesUseCase struct {
+	webhooks WebhookStorage
+	log      *slog.Logger
+}
+
+func NewListWebhookDeliveriesUseCase(webhookStorage WebhookStorage, log *slog.Logger) *ListWebhookDeliveriesUseCase {
+	return &ListWebhookDeliveriesUseCase{
+		webhooks: webhookStorage,
+		log:      log,
+	}
+}
+
+// List возвращает журнал доставок подписки, новые первыми.
+func (uc *ListWebhookDeliveriesUseCase) List(ctx context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	if limit < 0 || limit > maxWebhookDeliveriesLimit {
+		uc.log.WarnContext(ctx, "некорректный лимит доставок", "limit", limit)
+		return nil, domain.ErrInvalidWebhook
+	}
+	if limit == 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+
+	if _, err := uc.webhooks.GetWebhook(ctx, subscriptionID); err != nil {
+		uc.log.WarnContext(ctx, "подписка на вебхук не найдена", "webhook_id", subscriptionID, "error", err)
+		return nil, err
+	}
+
+	deliveries, err := uc.webhooks.ListWebhookDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения доставок вебхука", "webhook_id", subscriptionID, "error", err)
+		return nil, err
+	}
+	return deliveries, nil
+}
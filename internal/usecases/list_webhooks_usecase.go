@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type ListWebhooksUseCase struct {
+	webhooks WebhookStorage
+	log      *slog.Logger
+}
+
+func NewListWebhooksUseCase(webhookStorage WebhookStorage, log *slog.Logger) *ListWebhooksUseCase {
+	return &ListWebhooksUseCase{
+		webhooks: webhookStorage,
+		log:      log,
+	}
+}
+
+// List возвращает все подписки на вебхуки.
+func (uc *ListWebhooksUseCase) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	subscriptions, err := uc.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения подписок на вебхуки", "error", err)
+		return nil, err
+	}
+	return subscriptions, nil
+}
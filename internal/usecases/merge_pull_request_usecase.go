@@ -8,16 +8,32 @@ import (
 )
 
 type MergePullRequestUseCase struct {
-	prs   PullRequestStorage
-	clock ClockAdapter
-	log   *slog.Logger
+	prs     PullRequestStorage
+	clock   ClockAdapter
+	tx      Transactor
+	audit   Auditor
+	history HistoryRecorder
+	events  EventRecorder
+	log     *slog.Logger
 }
 
-func NewMergePullRequestUseCase(prStorage PullRequestStorage, clock ClockAdapter, log *slog.Logger) *MergePullRequestUseCase {
+func NewMergePullRequestUseCase(
+	prStorage PullRequestStorage,
+	clock ClockAdapter,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
+	log *slog.Logger,
+) *MergePullRequestUseCase {
 	return &MergePullRequestUseCase{
-		prs:   prStorage,
-		clock: clock,
-		log:   log,
+		prs:     prStorage,
+		clock:   clock,
+		tx:      tx,
+		audit:   audit,
+		history: history,
+		events:  events,
+		log:     log,
 	}
 }
 
@@ -25,18 +41,38 @@ func NewMergePullRequestUseCase(prStorage PullRequestStorage, clock ClockAdapter
 func (uc *MergePullRequestUseCase) Merge(ctx context.Context, id string) (domain.PullRequest, error) {
 	uc.log.InfoContext(ctx, "merge pull request", "pr_id", id)
 
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		return uc.merge(ctx, id)
+	})
+}
+
+func (uc *MergePullRequestUseCase) merge(ctx context.Context, id string) (domain.PullRequest, error) {
 	pr, err := uc.prs.GetPullRequest(ctx, id)
 	if err != nil {
 		uc.log.WarnContext(ctx, "pull request не найден", "pr_id", id, "error", err)
 		return domain.PullRequest{}, err
 	}
 
+	before := pr
 	pr.MarkMerged(uc.clock.Now())
 
 	if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
 		uc.log.ErrorContext(ctx, "не удалось обновить pull request", "pr_id", id, "error", err)
 		return domain.PullRequest{}, err
 	}
+	// повторный merge ничего не меняет и в аудит, историю и outbox не пишется
+	if before.Status != domain.PRStatusMerged {
+		if err := uc.audit.Record(ctx, domain.AuditPullRequestMerged, domain.AuditEntityPullRequest, pr.ID, before, pr); err != nil {
+			return domain.PullRequest{}, err
+		}
+		if err := uc.history.Record(ctx, historyEvent(pr.ID, domain.PRHistoryMerged)); err != nil {
+			return domain.PullRequest{}, err
+		}
+		merged := domain.PullRequestMerged{PullRequestID: pr.ID, AuthorID: pr.AuthorID, TeamName: pr.TeamName, MergedAt: *pr.MergedAt}
+		if err := uc.events.Record(ctx, merged); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
 
 	uc.log.InfoContext(ctx, "pull request в статусе MERGED", "pr_id", id)
 	return pr, nil
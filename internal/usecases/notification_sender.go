@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// notificationSender рассылает уведомление во все каналы, где у пользователя есть учётная запись.
+// send только логирует ошибки каналов, sendPending возвращает их, чтобы недоставленное можно было повторить.
+type notificationSender struct {
+	notifiers  []Notifier
+	identities IdentityResolver
+	users      UserStorage
+	log        *slog.Logger
+}
+
+func newNotificationSender(notifiers []Notifier, identities IdentityResolver, users UserStorage, log *slog.Logger) notificationSender {
+	return notificationSender{
+		notifiers:  notifiers,
+		identities: identities,
+		users:      users,
+		log:        log,
+	}
+}
+
+// send отправляет уведомление не больше одного раза: ошибки каналов только логируются.
+func (s notificationSender) send(ctx context.Context, notification domain.Notification) {
+	_ = s.sendPending(ctx, notification, func(string) bool { return true }, func(context.Context, string) error { return nil })
+}
+
+// sendPending отправляет уведомление в каналы, для которых pending вернул true, и после каждой
+// удачной отправки вызывает sent. Возвращает ошибки каналов и sent.
+func (s notificationSender) sendPending(
+	ctx context.Context,
+	notification domain.Notification,
+	pending func(provider string) bool,
+	sent func(ctx context.Context, provider string) error,
+) error {
+	var errs []error
+	for _, notifier := range s.notifiers {
+		if !pending(notifier.Provider()) {
+			continue
+		}
+		handle, err := s.identities.ResolveLogin(ctx, notifier.Provider(), notification.UserID)
+		if errors.Is(err, domain.ErrIdentityNotFound) {
+			continue
+		}
+		if err != nil {
+			s.log.ErrorContext(ctx, "ошибка поиска учётной записи", "user_id", notification.UserID, "provider", notifier.Provider(), "error", err)
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := notifier.Notify(ctx, handle, notification); err != nil {
+			s.log.WarnContext(ctx, "не удалось отправить уведомление",
+				"user_id", notification.UserID,
+				"provider", notifier.Provider(),
+				"kind", notification.Kind,
+				"pr_id", notification.PullRequestID,
+				"error", err,
+			)
+			errs = append(errs, err)
+			continue
+		}
+		s.log.InfoContext(ctx, "уведомление отправлено", "user_id", notification.UserID, "provider", notifier.Provider(), "kind", notification.Kind)
+		if err := sent(ctx, notifier.Provider()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// userName имя пользователя для текста уведомления; если его не найти, подставляется ID.
+func (s notificationSender) userName(ctx context.Context, userID string) string {
+	user, err := s.users.GetUser(ctx, userID)
+	if err != nil || user.Name == "" {
+		return userID
+	}
+	return user.Name
+}
@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// Outbox EventRecorder поверх OutboxStorage: сериализует события и ставит время и автора.
+type Outbox struct {
+	messages OutboxStorage
+	clock    ClockAdapter
+}
+
+func NewOutbox(outboxStorage OutboxStorage, clock ClockAdapter) *Outbox {
+	return &Outbox{
+		messages: outboxStorage,
+		clock:    clock,
+	}
+}
+
+// Record кладёт события в outbox в переданном порядке; доставить их можно сразу.
+func (o *Outbox) Record(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	actor := ActorFromContext(ctx)
+	now := o.clock.Now()
+	messages := make([]domain.OutboxMessage, 0, len(events))
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, domain.OutboxMessage{
+			EventType:     event.EventType(),
+			AggregateID:   event.AggregateID(),
+			Payload:       payload,
+			Actor:         actor,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+	}
+	return o.messages.AddOutboxMessages(ctx, messages)
+}
+
+// reviewerEvents переводит изменения ревьюеров из истории PR в доменные события.
+// Создание и merge описываются отдельными событиями с данными PR и здесь пропускаются.
+func reviewerEvents(pr domain.PullRequest, entries []domain.PullRequestHistoryEntry) []domain.Event {
+	events := make([]domain.Event, 0, len(entries))
+	for _, entry := range entries {
+		switch entry.Event {
+		case domain.PRHistoryReviewerAssigned:
+			events = append(events, domain.ReviewerAssigned{
+				PullRequestID: pr.ID,
+				TeamName:      pr.TeamName,
+				ReviewerID:    entry.ReviewerID,
+				Reason:        entry.Reason,
+			})
+		case domain.PRHistoryReviewerReplaced:
+			events = append(events, domain.ReviewerReplaced{
+				PullRequestID: pr.ID,
+				TeamName:      pr.TeamName,
+				OldReviewerID: entry.PreviousReviewerID,
+				NewReviewerID: entry.ReviewerID,
+				Reason:        entry.Reason,
+			})
+		case domain.PRHistoryReviewerRemoved:
+			events = append(events, domain.ReviewerRemoved{
+				PullRequestID: pr.ID,
+				TeamName:      pr.TeamName,
+				ReviewerID:    entry.PreviousReviewerID,
+				Reason:        entry.Reason,
+			})
+		}
+	}
+	return events
+}
+
+// userActivityEvent событие смены активности пользователя.
+func userActivityEvent(user domain.User) domain.Event {
+	if user.IsActive {
+		return domain.UserActivated{UserID: user.ID, TeamName: user.TeamName}
+	}
+	return domain.UserDeactivated{UserID: user.ID, TeamName: user.TeamName}
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+const (
+	// outboxLease на сколько откладывается забранное сообщение; не доставленное
+	// за это время (релей упал) заберут снова
+	outboxLease = time.Minute
+	// outboxPublishTimeout сколько ждём одного публикатора
+	outboxPublishTimeout = 30 * time.Second
+	// outboxBaseBackoff и outboxMaxBackoff границы паузы между попытками доставки
+	outboxBaseBackoff = time.Second
+	outboxMaxBackoff  = 10 * time.Minute
+)
+
+// OutboxRelay доставляет сообщения outbox всем публикаторам.
+// Сообщение считается доставленным, когда его приняли все публикаторы; при ошибке
+// повторяется целиком, поэтому публикаторы могут получить его повторно.
+type OutboxRelay struct {
+	outbox     OutboxStorage
+	publishers []EventPublisher
+	clock      ClockAdapter
+	batchSize  int
+	poll       time.Duration
+	log        *slog.Logger
+}
+
+func NewOutboxRelay(
+	outboxStorage OutboxStorage,
+	publishers []EventPublisher,
+	clock ClockAdapter,
+	batchSize int,
+	pollInterval time.Duration,
+	log *slog.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:     outboxStorage,
+		publishers: publishers,
+		clock:      clock,
+		batchSize:  batchSize,
+		poll:       pollInterval,
+		log:        log,
+	}
+}
+
+// Run доставляет сообщения, пока не отменён ctx; когда доставлять нечего, ждёт poll.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivered, err := r.RunOnce(ctx)
+		if err != nil {
+			r.log.ErrorContext(ctx, "ошибка получения сообщений outbox", "error", err)
+		}
+		if delivered > 0 && delivered == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.poll):
+		}
+	}
+}
+
+// RunOnce забирает одну пачку сообщений и пытается их доставить.
+// Возвращает размер пачки.
+func (r *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	now := r.clock.Now()
+	messages, err := r.outbox.ClaimOutboxMessages(ctx, now, now.Add(outboxLease), r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			// оставшиеся сообщения заберут после истечения аренды
+			return len(messages), nil
+		}
+		r.deliver(ctx, message)
+	}
+	return len(messages), nil
+}
+
+func (r *OutboxRelay) deliver(ctx context.Context, message domain.OutboxMessage) {
+	if err := r.publish(ctx, message); err != nil {
+		next := r.clock.Now().Add(outboxBackoff(message.Attempts))
+		r.log.WarnContext(ctx, "не удалось доставить событие",
+			"id", message.ID,
+			"event_type", message.EventType,
+			"attempts", message.Attempts,
+			"next_attempt_at", next,
+			"error", err,
+		)
+		if err := r.outbox.MarkOutboxFailed(ctx, message.ID, next, err.Error()); err != nil {
+			r.log.ErrorContext(ctx, "ошибка сохранения неудачной доставки", "id", message.ID, "error", err)
+		}
+		return
+	}
+
+	if err := r.outbox.MarkOutboxDelivered(ctx, message.ID, r.clock.Now()); err != nil {
+		r.log.ErrorContext(ctx, "ошибка отметки доставки", "id", message.ID, "error", err)
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, message domain.OutboxMessage) error {
+	var errs []error
+	for _, publisher := range r.publishers {
+		publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+		err := publisher.Publish(publishCtx, message)
+		cancel()
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// outboxBackoff пауза перед следующей попыткой доставки после attempts неудачных.
+func outboxBackoff(attempts int) time.Duration {
+	return exponentialBackoff(attempts, outboxBaseBackoff, outboxMaxBackoff)
+}
+
+// exponentialBackoff удваивает base после каждой неудачной попытки, но не больше limit.
+func exponentialBackoff(attempts int, base, limit time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < limit; i++ {
+		backoff *= 2
+	}
+	return min(backoff, limit)
+}
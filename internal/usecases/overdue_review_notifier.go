@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// overdueBatchSize сколько просроченных ревью обрабатывается за проход
+const overdueBatchSize = 100
+
+// OverdueReviewNotifier напоминает ревьюерам о ревью, которые ждут дольше after.
+// Напоминание отправляется один раз на назначение: повторное назначение того же ревьюера
+// снова запускает отсчёт.
+type OverdueReviewNotifier struct {
+	reminders ReviewReminderStorage
+	sender    notificationSender
+	clock     ClockAdapter
+	after     time.Duration
+	poll      time.Duration
+	log       *slog.Logger
+}
+
+func NewOverdueReviewNotifier(
+	reminders ReviewReminderStorage,
+	notifiers []Notifier,
+	identities IdentityResolver,
+	users UserStorage,
+	clock ClockAdapter,
+	after time.Duration,
+	pollInterval time.Duration,
+	log *slog.Logger,
+) *OverdueReviewNotifier {
+	return &OverdueReviewNotifier{
+		reminders: reminders,
+		sender:    newNotificationSender(notifiers, identities, users, log),
+		clock:     clock,
+		after:     after,
+		poll:      pollInterval,
+		log:       log,
+	}
+}
+
+// Run проверяет просроченные ревью, пока не отменён ctx; между проверками ждёт poll.
+func (n *OverdueReviewNotifier) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		reminded, err := n.RunOnce(ctx)
+		if err != nil {
+			n.log.ErrorContext(ctx, "ошибка поиска просроченных ревью", "error", err)
+		}
+		if reminded > 0 && reminded == overdueBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(n.poll):
+		}
+	}
+}
+
+// RunOnce отправляет напоминания по одной пачке просроченных ревью. Возвращает размер пачки.
+func (n *OverdueReviewNotifier) RunOnce(ctx context.Context) (int, error) {
+	now := n.clock.Now()
+	reviews, err := n.reminders.ListOverdueReviews(ctx, now.Add(-n.after), overdueBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, review := range reviews {
+		if ctx.Err() != nil {
+			return len(reviews), nil
+		}
+
+		// отметка до отправки: при сбое напоминание потеряется, но не придёт дважды
+		claimed, err := n.reminders.MarkReviewReminded(ctx, review, now)
+		if err != nil {
+			n.log.ErrorContext(ctx, "ошибка сохранения напоминания", "pr_id", review.PullRequestID, "reviewer_id", review.ReviewerID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		n.sender.send(ctx, domain.Notification{
+			Kind:          domain.NotificationOverdue,
+			UserID:        review.ReviewerID,
+			PullRequestID: review.PullRequestID,
+			Title:         review.Title,
+			AuthorName:    n.sender.userName(ctx, review.AuthorID),
+			Waiting:       now.Sub(review.AssignedAt),
+		})
+	}
+	return len(reviews), nil
+}
@@ -0,0 +1,39 @@
+package usecases
+
+import "context"
+
+type progressKey struct{}
+
+// progressFunc получает число выполненных шагов и общее число шагов.
+type progressFunc func(done, total int)
+
+// withProgress подписывает контекст на прогресс долгой операции.
+func withProgress(ctx context.Context, report progressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// progress счётчик шагов долгой операции; без подписчика в контексте ничего не делает.
+type progress struct {
+	report progressFunc
+	done   int
+	total  int
+}
+
+func startProgress(ctx context.Context, total int) *progress {
+	report, _ := ctx.Value(progressKey{}).(progressFunc)
+	p := &progress{report: report, total: total}
+	p.notify()
+	return p
+}
+
+// step отмечает выполненный шаг.
+func (p *progress) step() {
+	p.done++
+	p.notify()
+}
+
+func (p *progress) notify() {
+	if p.report != nil {
+		p.report(p.done, p.total)
+	}
+}
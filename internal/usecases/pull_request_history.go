@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// PullRequestHistory HistoryRecorder поверх PullRequestHistoryStorage: ставит время и автора.
+type PullRequestHistory struct {
+	entries PullRequestHistoryStorage
+	clock   ClockAdapter
+}
+
+func NewPullRequestHistory(historyStorage PullRequestHistoryStorage, clock ClockAdapter) *PullRequestHistory {
+	return &PullRequestHistory{
+		entries: historyStorage,
+		clock:   clock,
+	}
+}
+
+// Record пишет события одним временем, порядок событий сохраняется.
+func (h *PullRequestHistory) Record(ctx context.Context, entries ...domain.PullRequestHistoryEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	actor := ActorFromContext(ctx)
+	now := h.clock.Now()
+	stamped := make([]domain.PullRequestHistoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Actor = actor
+		entry.CreatedAt = now
+		stamped = append(stamped, entry)
+	}
+	return h.entries.AddPullRequestHistory(ctx, stamped)
+}
+
+func historyEvent(prID, event string) domain.PullRequestHistoryEntry {
+	return domain.PullRequestHistoryEntry{PullRequestID: prID, Event: event}
+}
+
+func historyAssigned(prID, reviewerID, reason string) domain.PullRequestHistoryEntry {
+	return domain.PullRequestHistoryEntry{PullRequestID: prID, Event: domain.PRHistoryReviewerAssigned, ReviewerID: reviewerID, Reason: reason}
+}
+
+func historyReplaced(prID, oldReviewerID, newReviewerID, reason string) domain.PullRequestHistoryEntry {
+	return domain.PullRequestHistoryEntry{
+		PullRequestID:      prID,
+		Event:              domain.PRHistoryReviewerReplaced,
+		ReviewerID:         newReviewerID,
+		PreviousReviewerID: oldReviewerID,
+		Reason:             reason,
+	}
+}
+
+func historyRemoved(prID, reviewerID, reason string) domain.PullRequestHistoryEntry {
+	return domain.PullRequestHistoryEntry{PullRequestID: prID, Event: domain.PRHistoryReviewerRemoved, PreviousReviewerID: reviewerID, Reason: reason}
+}
+
+// reviewerChanges описывает разницу ревьюеров как снятия и назначения, когда пары замен неизвестны.
+func reviewerChanges(before, after domain.PullRequest, reason string) []domain.PullRequestHistoryEntry {
+	var entries []domain.PullRequestHistoryEntry
+	for _, reviewerID := range before.Reviewers {
+		if !after.HasReviewer(reviewerID) {
+			entries = append(entries, historyRemoved(after.ID, reviewerID, reason))
+		}
+	}
+	for _, reviewerID := range after.Reviewers {
+		if !before.HasReviewer(reviewerID) {
+			entries = append(entries, historyAssigned(after.ID, reviewerID, reason))
+		}
+	}
+	return entries
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// PullRequestLifecycleUseCase меняет статус PR по событиям внешней системы.
+type PullRequestLifecycleUseCase struct {
+	prs     PullRequestStorage
+	topUp   ReviewerTopUp
+	tx      Transactor
+	audit   Auditor
+	history HistoryRecorder
+	log     *slog.Logger
+}
+
+func NewPullRequestLifecycleUseCase(
+	prStorage PullRequestStorage,
+	topUp ReviewerTopUp,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	log *slog.Logger,
+) *PullRequestLifecycleUseCase {
+	return &PullRequestLifecycleUseCase{
+		prs:     prStorage,
+		topUp:   topUp,
+		tx:      tx,
+		audit:   audit,
+		history: history,
+		log:     log,
+	}
+}
+
+// ConvertToDraft ставит ревью на паузу: ревьюеры остаются назначенными,
+// но черновик не попадает в напоминания, дайджесты, дозаполнение и ребалансировку.
+// Закрытый PR черновиком не делается.
+func (uc *PullRequestLifecycleUseCase) ConvertToDraft(ctx context.Context, id string) (domain.PullRequest, error) {
+	pr, _, err := uc.transition(ctx, id, (*domain.PullRequest).MarkDraft, domain.AuditPullRequestDrafted, domain.PRHistoryConvertedToDraft)
+	return pr, err
+}
+
+// MarkReady возвращает черновик в ревью и дозаполняет PR команды,
+// если за время паузы ревьюеров стало меньше нужного.
+func (uc *PullRequestLifecycleUseCase) MarkReady(ctx context.Context, id string) (domain.PullRequest, error) {
+	pr, changed, err := uc.transition(ctx, id, (*domain.PullRequest).MarkReady, domain.AuditPullRequestReady, domain.PRHistoryReadyForReview)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	uc.resume(ctx, pr, changed)
+	return pr, nil
+}
+
+// Close закрывает PR без merge. Как и у черновика, ревьюеры остаются, но PR выпадает из ревью.
+func (uc *PullRequestLifecycleUseCase) Close(ctx context.Context, id string) (domain.PullRequest, error) {
+	pr, _, err := uc.transition(ctx, id, (*domain.PullRequest).MarkClosed, domain.AuditPullRequestClosed, domain.PRHistoryClosed)
+	return pr, err
+}
+
+// Reopen снова открывает закрытый PR, черновиком или в ревью; во втором случае PR дозаполняется как в MarkReady.
+func (uc *PullRequestLifecycleUseCase) Reopen(ctx context.Context, id string, draft bool) (domain.PullRequest, error) {
+	reopen := func(pr *domain.PullRequest) error {
+		return pr.Reopen(draft)
+	}
+	pr, changed, err := uc.transition(ctx, id, reopen, domain.AuditPullRequestReopened, domain.PRHistoryReopened)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	uc.resume(ctx, pr, changed)
+	return pr, nil
+}
+
+// resume дозаполняет вернувшийся в ревью PR; ошибка дозаполнения не отменяет смену статуса.
+func (uc *PullRequestLifecycleUseCase) resume(ctx context.Context, pr domain.PullRequest, changed bool) {
+	if !changed || pr.MissingReviewers() == 0 {
+		return
+	}
+	if _, err := uc.topUp.TopUpTeam(ctx, pr.TeamName); err != nil {
+		uc.log.WarnContext(ctx, "не удалось дозаполнить PR команды", "team_name", pr.TeamName, "error", err)
+	}
+}
+
+// transition применяет смену статуса в транзакции; повтор ничего не меняет и в аудит и историю не пишется.
+func (uc *PullRequestLifecycleUseCase) transition(
+	ctx context.Context,
+	id string,
+	apply func(*domain.PullRequest) error,
+	action, event string,
+) (domain.PullRequest, bool, error) {
+	var changed bool
+	pr, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		pr, err := uc.prs.GetPullRequest(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "pull request не найден", "pr_id", id, "error", err)
+			return domain.PullRequest{}, err
+		}
+
+		before := pr
+		if err := apply(&pr); err != nil {
+			uc.log.WarnContext(ctx, "статус pull request не меняется", "pr_id", id, "status", pr.Status, "error", err)
+			return domain.PullRequest{}, err
+		}
+		if pr.Status == before.Status {
+			return pr, nil
+		}
+
+		if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить pull request", "pr_id", id, "error", err)
+			return domain.PullRequest{}, err
+		}
+		if err := uc.audit.Record(ctx, action, domain.AuditEntityPullRequest, pr.ID, before, pr); err != nil {
+			return domain.PullRequest{}, err
+		}
+		if err := uc.history.Record(ctx, historyEvent(pr.ID, event)); err != nil {
+			return domain.PullRequest{}, err
+		}
+
+		changed = true
+		uc.log.InfoContext(ctx, "статус pull request изменён", "pr_id", id, "from", before.Status, "to", pr.Status)
+		return pr, nil
+	})
+	if err != nil {
+		return domain.PullRequest{}, false, err
+	}
+	return pr, changed, nil
+}
@@ -0,0 +1,58 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// RandomReviewerSelector выбирает ревьюеров равновероятно, а если веса ревью
+// у кандидатов различаются - пропорционально весу.
+type RandomReviewerSelector struct {
+	rand RandomAdapter
+}
+
+func NewRandomReviewerSelector(random RandomAdapter) *RandomReviewerSelector {
+	return &RandomReviewerSelector{rand: random}
+}
+
+// Select перемешивает кандидатов и берёт первых count. Если у кого-то вес не 1,
+// перемешивание взвешенное, как в WeightedReviewerSelector.
+func (s *RandomReviewerSelector) Select(_ context.Context, _ domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	if hasCustomWeight(candidates) {
+		return firstUsers(weightedOrder(s.rand, candidates, reviewWeight), count), nil
+	}
+
+	selected := append([]domain.User(nil), candidates...)
+	shuffleUsers(s.rand, selected)
+	return firstUsers(selected, count), nil
+}
+
+// hasCustomWeight есть ли среди кандидатов вес ревью, отличный от обычного.
+func hasCustomWeight(users []domain.User) bool {
+	for _, user := range users {
+		if user.ReviewWeight != 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func shuffleUsers(random RandomAdapter, users []domain.User) {
+	if random == nil || len(users) < 2 {
+		return
+	}
+	random.Shuffle(len(users), func(i, j int) {
+		users[i], users[j] = users[j], users[i]
+	})
+}
+
+func firstUsers(users []domain.User, count int) []domain.User {
+	if count < 0 {
+		count = 0
+	}
+	if len(users) > count {
+		return users[:count]
+	}
+	return users
+}
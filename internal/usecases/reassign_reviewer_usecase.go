@@ -9,39 +9,73 @@ import (
 )
 
 type ReassignReviewerUseCase struct {
-	prs   PullRequestStorage
-	teams TeamStorage
-	users UserStorage
-	rand  RandomAdapter
-	log   *slog.Logger
+	prs      PullRequestStorage
+	teams    TeamStorage
+	users    UserStorage
+	selector ReviewerSelector
+	tx       Transactor
+	audit    Auditor
+	history  HistoryRecorder
+	events   EventRecorder
+	log      *slog.Logger
 }
 
 func NewReassignReviewerUseCase(
 	prStorage PullRequestStorage,
 	teamStorage TeamStorage,
 	userStorage UserStorage,
-	random RandomAdapter,
+	selector ReviewerSelector,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
 	log *slog.Logger,
 ) *ReassignReviewerUseCase {
 	return &ReassignReviewerUseCase{
-		prs:   prStorage,
-		teams: teamStorage,
-		users: userStorage,
-		rand:  random,
-		log:   log,
+		prs:      prStorage,
+		teams:    teamStorage,
+		users:    userStorage,
+		selector: selector,
+		tx:       tx,
+		audit:    audit,
+		history:  history,
+		events:   events,
+		log:      log,
 	}
 }
 
 // Reassign переназначает ревьюера и возвращает идентификатор заменяющего.
-func (uc *ReassignReviewerUseCase) Reassign(ctx context.Context, prID, oldReviewerID string, desiredNew *string) (domain.PullRequest, string, error) {
+// Обязательного ревьюера можно заменить только с overrideRequired.
+func (uc *ReassignReviewerUseCase) Reassign(ctx context.Context, prID, oldReviewerID string, desiredNew *string, overrideRequired bool) (domain.PullRequest, string, error) {
 	uc.log.InfoContext(ctx, "переназначаем ревьюера", "pr_id", prID, "old_reviewer", oldReviewerID)
 
+	var newReviewerID string
+	pr, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.PullRequest, error) {
+		var (
+			pr  domain.PullRequest
+			err error
+		)
+		pr, newReviewerID, err = uc.reassign(ctx, prID, oldReviewerID, desiredNew, overrideRequired)
+		return pr, err
+	})
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	return pr, newReviewerID, nil
+}
+
+func (uc *ReassignReviewerUseCase) reassign(ctx context.Context, prID, oldReviewerID string, desiredNew *string, overrideRequired bool) (domain.PullRequest, string, error) {
 	pr, err := uc.prs.GetPullRequest(ctx, prID)
 	if err != nil {
 		uc.log.WarnContext(ctx, "pull request не найден", "pr_id", prID, "error", err)
 		return domain.PullRequest{}, "", err
 	}
 
+	if pr.IsRequiredReviewer(oldReviewerID) && !overrideRequired {
+		uc.log.WarnContext(ctx, "попытка заменить обязательного ревьюера без подтверждения", "pr_id", prID, "reviewer_id", oldReviewerID)
+		return domain.PullRequest{}, "", domain.ErrReviewerRequired
+	}
+
 	oldReviewer, err := uc.users.GetUser(ctx, oldReviewerID)
 	if err != nil {
 		uc.log.WarnContext(ctx, "заменяемый ревьювер не найден", "reviewer_id", oldReviewerID, "error", err)
@@ -54,8 +88,13 @@ func (uc *ReassignReviewerUseCase) Reassign(ctx context.Context, prID, oldReview
 		return domain.PullRequest{}, "", err
 	}
 
+	policyTeam, err := uc.getPolicyTeam(ctx, pr, team)
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+
 	candidates := uc.getCandidates(pr, oldReviewerID, team)
-	newReviewerID, err := uc.selectNewReviewer(ctx, prID, candidates, desiredNew)
+	newReviewerID, err := uc.selectNewReviewer(ctx, withoutReviewer(pr, oldReviewerID), policyTeam, candidates, desiredNew)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
@@ -64,6 +103,9 @@ func (uc *ReassignReviewerUseCase) Reassign(ctx context.Context, prID, oldReview
 		return domain.PullRequest{}, "", err
 	}
 
+	before := pr
+	before.Reviewers = append([]string(nil), pr.Reviewers...)
+	before.RequiredReviewers = append([]string(nil), pr.RequiredReviewers...)
 	if err := pr.ReplaceReviewer(oldReviewerID, newReviewerID); err != nil {
 		uc.log.WarnContext(ctx, "ошибка ReplaceReviewer", "error", err, "pr_id", prID)
 		return domain.PullRequest{}, "", err
@@ -73,11 +115,34 @@ func (uc *ReassignReviewerUseCase) Reassign(ctx context.Context, prID, oldReview
 		uc.log.ErrorContext(ctx, "не удалось сохранить pull request", "error", err, "pr_id", prID)
 		return domain.PullRequest{}, "", err
 	}
+	if err := uc.audit.Record(ctx, domain.AuditReviewerReassigned, domain.AuditEntityPullRequest, pr.ID, before, pr); err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	entry := historyReplaced(pr.ID, oldReviewerID, newReviewerID, domain.PRHistoryReasonManual)
+	if err := uc.history.Record(ctx, entry); err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	if err := uc.events.Record(ctx, reviewerEvents(pr, []domain.PullRequestHistoryEntry{entry})...); err != nil {
+		return domain.PullRequest{}, "", err
+	}
 
 	uc.log.InfoContext(ctx, "переназначение выполнено", "pr_id", prID, "new_reviewer", newReviewerID)
 	return pr, newReviewerID, nil
 }
 
+// getPolicyTeam возвращает команду PR, политика которой применяется к замене
+func (uc *ReassignReviewerUseCase) getPolicyTeam(ctx context.Context, pr domain.PullRequest, reviewerTeam domain.Team) (domain.Team, error) {
+	if pr.TeamName == reviewerTeam.Name {
+		return reviewerTeam, nil
+	}
+	team, err := uc.teams.GetTeam(ctx, pr.TeamName)
+	if err != nil {
+		uc.log.WarnContext(ctx, "команда pull request не найдена", "team_name", pr.TeamName, "error", err)
+		return domain.Team{}, err
+	}
+	return team, nil
+}
+
 // getCandidates получает кандидатов на замену из команды заменяемого ревьювера
 func (uc *ReassignReviewerUseCase) getCandidates(pr domain.PullRequest, oldReviewerID string, team domain.Team) []domain.User {
 	candidates := make([]domain.User, 0, len(team.Users))
@@ -96,29 +161,60 @@ func (uc *ReassignReviewerUseCase) getCandidates(pr domain.PullRequest, oldRevie
 	return candidates
 }
 
-// selectNewReviewer выбирает нового ревьювера (указанного или случайного)
-func (uc *ReassignReviewerUseCase) selectNewReviewer(ctx context.Context, prID string, candidates []domain.User, desiredNew *string) (string, error) {
+// selectNewReviewer выбирает нового ревьювера (указанного или через selector).
+// pr передаётся без заменяемого ревьювера, чтобы проверить политику senior по оставшимся.
+func (uc *ReassignReviewerUseCase) selectNewReviewer(
+	ctx context.Context,
+	pr domain.PullRequest,
+	policyTeam domain.Team,
+	candidates []domain.User,
+	desiredNew *string,
+) (string, error) {
 	if desiredNew != nil && *desiredNew != "" {
 		uc.log.InfoContext(ctx, "используем указанного нового ревьюера", "candidate_id", *desiredNew)
-		if !containsUser(candidates, *desiredNew) {
+		desired, ok := findUser(candidates, *desiredNew)
+		if !ok {
 			uc.log.WarnContext(ctx, "указанный кандидат недоступен", "candidate_id", *desiredNew)
 			return "", domain.ErrNoReviewerCandidates
 		}
-		return *desiredNew, nil
+		if err := uc.checkSeniorPolicy(ctx, pr, policyTeam, desired); err != nil {
+			return "", err
+		}
+		return desired.ID, nil
 	}
 
-	if len(candidates) == 0 {
-		uc.log.WarnContext(ctx, "нет доступных кандидатов для переназначения", "pr_id", prID)
+	selected, err := selectWithSeniorPolicy(ctx, uc.selector, uc.users, policyTeam, pr, candidates, 1)
+	if errors.Is(err, domain.ErrNoSeniorReviewer) {
+		uc.log.WarnContext(ctx, "не удалось выполнить политику senior-ревьюера", "pr_id", pr.ID, "team_name", policyTeam.Name)
+		return "", err
+	}
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка выбора ревьюера", "pr_id", pr.ID, "error", err)
+		return "", err
+	}
+	if len(selected) == 0 {
+		uc.log.WarnContext(ctx, "нет доступных кандидатов для переназначения", "pr_id", pr.ID)
 		return "", domain.ErrNoReviewerCandidates
 	}
 
-	if uc.rand != nil && len(candidates) > 1 {
-		uc.rand.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
-		})
-	}
+	return selected[0].ID, nil
+}
 
-	return candidates[0].ID, nil
+// checkSeniorPolicy проверяет, что после замены на desired политика команды соблюдена
+func (uc *ReassignReviewerUseCase) checkSeniorPolicy(ctx context.Context, pr domain.PullRequest, policyTeam domain.Team, desired domain.User) error {
+	if !policyTeam.RequireSeniorReviewer || desired.IsSenior() {
+		return nil
+	}
+	covered, err := hasSeniorReviewer(ctx, uc.users, pr.Reviewers)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка проверки уровня ревьюеров", "pr_id", pr.ID, "error", err)
+		return err
+	}
+	if !covered {
+		uc.log.WarnContext(ctx, "указанный кандидат нарушает политику senior-ревьюера", "candidate_id", desired.ID)
+		return domain.ErrNoSeniorReviewer
+	}
+	return nil
 }
 
 // validateNewReviewer проверяет что новый ревьювер существует
@@ -144,10 +240,28 @@ func contains(list []string, target string) bool {
 }
 
 func containsUser(list []domain.User, target string) bool {
+	_, ok := findUser(list, target)
+	return ok
+}
+
+func findUser(list []domain.User, target string) (domain.User, bool) {
 	for _, user := range list {
 		if user.ID == target {
-			return true
+			return user, true
 		}
 	}
-	return false
+	return domain.User{}, false
+}
+
+// withoutReviewer возвращает копию PR без указанного ревьюера.
+func withoutReviewer(pr domain.PullRequest, reviewerID string) domain.PullRequest {
+	reviewers := make([]string, 0, len(pr.Reviewers))
+	for _, id := range pr.Reviewers {
+		if id != reviewerID {
+			reviewers = append(reviewers, id)
+		}
+	}
+	pr.Reviewers = reviewers
+	pr.RequiredReviewers = nil
+	return pr
 }
@@ -0,0 +1,332 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// ReviewMove перенос одного ревью с перегруженного участника на недогруженного.
+type ReviewMove struct {
+	PullRequestID string `json:"pull_request_id"`
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+}
+
+// MemberLoad число открытых ревью участника до и после ребалансировки.
+type MemberLoad struct {
+	UserID string `json:"user_id"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
+
+type RebalanceResult struct {
+	DryRun bool         `json:"dry_run"`
+	Moves  []ReviewMove `json:"moves"`
+	Loads  []MemberLoad `json:"loads"`
+}
+
+type RebalanceTeamUseCase struct {
+	teams   TeamStorage
+	users   UserStorage
+	prs     PullRequestStorage
+	clock   ClockAdapter
+	tx      Transactor
+	audit   Auditor
+	history HistoryRecorder
+	events  EventRecorder
+	log     *slog.Logger
+}
+
+func NewRebalanceTeamUseCase(
+	teamStorage TeamStorage,
+	userStorage UserStorage,
+	prStorage PullRequestStorage,
+	clock ClockAdapter,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
+	log *slog.Logger,
+) *RebalanceTeamUseCase {
+	return &RebalanceTeamUseCase{
+		teams:   teamStorage,
+		users:   userStorage,
+		prs:     prStorage,
+		clock:   clock,
+		tx:      tx,
+		audit:   audit,
+		history: history,
+		events:  events,
+		log:     log,
+	}
+}
+
+// rebalancePlan рабочее состояние расчёта: нагрузка участников, их целевая нагрузка
+// и изменяемые копии PR. Получатели - участники, которых сейчас можно назначать;
+// working отмечает тех, у кого сейчас рабочее время.
+type rebalancePlan struct {
+	load        map[string]int
+	target      map[string]float64
+	receivers   []string
+	working     map[string]bool
+	prs         []*domain.PullRequest
+	originals   map[string]domain.PullRequest
+	changed     map[string]bool
+	policyTeams map[string]domain.Team
+}
+
+// Rebalance распределяет открытые ревью активных участников команды пропорционально
+// весу ревью. Получают ревью только те, кого сейчас можно назначать: вес больше 0
+// и нет отсутствия; у остальных цель 0, и их ревью переходят другим. Каждый перенос
+// снимает ревью с самого перегруженного относительно цели и отдаёт самому недогруженному,
+// пока перенос уменьшает отклонение от целей, поэтому переносов минимум.
+// С dryRun ничего не сохраняется.
+func (uc *RebalanceTeamUseCase) Rebalance(ctx context.Context, teamName string, dryRun bool) (RebalanceResult, error) {
+	uc.log.InfoContext(ctx, "ребалансировка ревью команды", "team_name", teamName, "dry_run", dryRun)
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (RebalanceResult, error) {
+		return uc.rebalance(ctx, teamName, dryRun)
+	})
+}
+
+func (uc *RebalanceTeamUseCase) rebalance(ctx context.Context, teamName string, dryRun bool) (RebalanceResult, error) {
+	team, err := uc.teams.GetTeam(ctx, teamName)
+	if err != nil {
+		uc.log.WarnContext(ctx, "команда не найдена", "team_name", teamName, "error", err)
+		return RebalanceResult{}, err
+	}
+
+	plan, err := uc.loadPlan(ctx, team)
+	if err != nil {
+		return RebalanceResult{}, err
+	}
+
+	members := make([]string, 0, len(plan.load))
+	for userID := range plan.load {
+		members = append(members, userID)
+	}
+	sort.Strings(members)
+
+	before := make(map[string]int, len(plan.load))
+	for userID, load := range plan.load {
+		before[userID] = load
+	}
+
+	moves := make([]ReviewMove, 0)
+	for {
+		move, found, err := uc.nextMove(ctx, plan, members)
+		if err != nil {
+			return RebalanceResult{}, err
+		}
+		if !found {
+			break
+		}
+		moves = append(moves, move)
+	}
+
+	if !dryRun {
+		movesByPR := make(map[string][]domain.PullRequestHistoryEntry, len(plan.changed))
+		for _, move := range moves {
+			movesByPR[move.PullRequestID] = append(movesByPR[move.PullRequestID],
+				historyReplaced(move.PullRequestID, move.FromUserID, move.ToUserID, domain.PRHistoryReasonRebalance))
+		}
+
+		tracker := startProgress(ctx, len(plan.changed))
+		for _, pr := range plan.prs {
+			if !plan.changed[pr.ID] {
+				continue
+			}
+			if err := uc.prs.UpdatePullRequest(ctx, *pr); err != nil {
+				uc.log.ErrorContext(ctx, "ошибка обновления PR", "pr_id", pr.ID, "error", err)
+				return RebalanceResult{}, err
+			}
+			if err := uc.audit.Record(ctx, domain.AuditReviewersRebalanced, domain.AuditEntityPullRequest, pr.ID, plan.originals[pr.ID], *pr); err != nil {
+				return RebalanceResult{}, err
+			}
+			if err := uc.history.Record(ctx, movesByPR[pr.ID]...); err != nil {
+				return RebalanceResult{}, err
+			}
+			if err := uc.events.Record(ctx, reviewerEvents(*pr, movesByPR[pr.ID])...); err != nil {
+				return RebalanceResult{}, err
+			}
+			tracker.step()
+		}
+	}
+
+	loads := make([]MemberLoad, 0, len(members))
+	for _, userID := range members {
+		loads = append(loads, MemberLoad{UserID: userID, Before: before[userID], After: plan.load[userID]})
+	}
+
+	uc.log.InfoContext(ctx, "ребалансировка завершена", "team_name", teamName, "moves", len(moves), "dry_run", dryRun)
+	return RebalanceResult{DryRun: dryRun, Moves: moves, Loads: loads}, nil
+}
+
+// loadPlan считает нагрузку активных участников по открытым PR и их целевую нагрузку.
+func (uc *RebalanceTeamUseCase) loadPlan(ctx context.Context, team domain.Team) (*rebalancePlan, error) {
+	plan := &rebalancePlan{
+		load:        make(map[string]int, len(team.Users)),
+		target:      make(map[string]float64, len(team.Users)),
+		working:     make(map[string]bool, len(team.Users)),
+		originals:   make(map[string]domain.PullRequest),
+		changed:     make(map[string]bool),
+		policyTeams: map[string]domain.Team{team.Name: team},
+	}
+
+	now := uc.clock.Now()
+	totalWeight := 0.0
+	for _, member := range team.Users {
+		if !member.IsActive {
+			continue
+		}
+		plan.load[member.ID] = 0
+		if member.IsAvailableAt(now) {
+			plan.receivers = append(plan.receivers, member.ID)
+			plan.working[member.ID] = member.IsWorkingAt(now)
+			totalWeight += member.ReviewWeight
+		}
+	}
+
+	allPRs, err := uc.prs.ListPullRequests(ctx)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения PR", "error", err)
+		return nil, err
+	}
+	sort.SliceStable(allPRs, func(i, j int) bool {
+		return allPRs[i].CreatedAt.Before(allPRs[j].CreatedAt)
+	})
+
+	for i := range allPRs {
+		pr := allPRs[i]
+		if pr.Status != domain.PRStatusOpen {
+			continue
+		}
+		reviewed := false
+		for _, reviewerID := range pr.Reviewers {
+			if _, ok := plan.load[reviewerID]; ok {
+				plan.load[reviewerID]++
+				reviewed = true
+			}
+		}
+		if reviewed {
+			plan.prs = append(plan.prs, &pr)
+			plan.originals[pr.ID] = pr
+		}
+	}
+
+	total := 0
+	for _, load := range plan.load {
+		total += load
+	}
+	for _, member := range team.Users {
+		if slices.Contains(plan.receivers, member.ID) {
+			plan.target[member.ID] = float64(total) * member.ReviewWeight / totalWeight
+		}
+	}
+
+	return plan, nil
+}
+
+// excess насколько нагрузка участника выше его цели; отрицательная - недогружен.
+func (p *rebalancePlan) excess(userID string) float64 {
+	return float64(p.load[userID]) - p.target[userID]
+}
+
+// nextMove находит и применяет к плану следующий перенос от самых перегруженных к самым
+// недогруженным. Перенос делается, только если разница отклонений от цели больше 1:
+// тогда он строго уменьшает суммарное отклонение. Среди получателей сначала те,
+// у кого сейчас рабочее время.
+func (uc *RebalanceTeamUseCase) nextMove(ctx context.Context, plan *rebalancePlan, members []string) (ReviewMove, bool, error) {
+	donors := append([]string(nil), members...)
+	sort.SliceStable(donors, func(i, j int) bool {
+		return plan.excess(donors[i]) > plan.excess(donors[j])
+	})
+
+	receivers := make([]string, 0, len(plan.receivers))
+	for i := len(donors) - 1; i >= 0; i-- {
+		if slices.Contains(plan.receivers, donors[i]) {
+			receivers = append(receivers, donors[i])
+		}
+	}
+	sort.SliceStable(receivers, func(i, j int) bool {
+		return plan.working[receivers[i]] && !plan.working[receivers[j]]
+	})
+
+	for _, donor := range donors {
+		for _, receiver := range receivers {
+			if plan.excess(donor)-plan.excess(receiver) <= 1 {
+				continue
+			}
+
+			move, found, err := uc.movePullRequest(ctx, plan, donor, receiver)
+			if err != nil || found {
+				return move, found, err
+			}
+		}
+	}
+
+	return ReviewMove{}, false, nil
+}
+
+// movePullRequest переносит первое подходящее ревью donor на receiver.
+// Обязательных ревьюеров не трогает, автора не назначает, политику senior сохраняет.
+func (uc *RebalanceTeamUseCase) movePullRequest(ctx context.Context, plan *rebalancePlan, donor, receiver string) (ReviewMove, bool, error) {
+	for _, pr := range plan.prs {
+		if !pr.HasReviewer(donor) || pr.HasReviewer(receiver) || pr.IsRequiredReviewer(donor) || pr.AuthorID == receiver {
+			continue
+		}
+
+		moved := *pr
+		moved.Reviewers = append([]string(nil), pr.Reviewers...)
+		moved.RequiredReviewers = append([]string(nil), pr.RequiredReviewers...)
+		if err := moved.ReplaceReviewer(donor, receiver); err != nil {
+			continue
+		}
+
+		allowed, err := uc.keepsSeniorPolicy(ctx, plan, *pr, moved)
+		if err != nil {
+			return ReviewMove{}, false, err
+		}
+		if !allowed {
+			continue
+		}
+
+		*pr = moved
+		plan.changed[pr.ID] = true
+		plan.load[donor]--
+		plan.load[receiver]++
+		uc.log.InfoContext(ctx, "ревью перенесено", "pr_id", pr.ID, "from", donor, "to", receiver)
+		return ReviewMove{PullRequestID: pr.ID, FromUserID: donor, ToUserID: receiver}, true, nil
+	}
+
+	return ReviewMove{}, false, nil
+}
+
+// keepsSeniorPolicy проверяет, что перенос не лишает PR единственного senior-ревьюера.
+func (uc *RebalanceTeamUseCase) keepsSeniorPolicy(ctx context.Context, plan *rebalancePlan, before, after domain.PullRequest) (bool, error) {
+	team, ok := plan.policyTeams[before.TeamName]
+	if !ok {
+		loaded, err := uc.teams.GetTeam(ctx, before.TeamName)
+		if err != nil {
+			uc.log.ErrorContext(ctx, "не найдена команда автора PR", "pr_id", before.ID, "team_name", before.TeamName, "error", err)
+			return false, err
+		}
+		plan.policyTeams[before.TeamName] = loaded
+		team = loaded
+	}
+	if !team.RequireSeniorReviewer {
+		return true, nil
+	}
+
+	covered, err := hasSeniorReviewer(ctx, uc.users, after.Reviewers)
+	if err != nil || covered {
+		return covered, err
+	}
+	// политика уже нарушена до переноса - перенос её не ухудшает
+	wasCovered, err := hasSeniorReviewer(ctx, uc.users, before.Reviewers)
+	return !wasCovered, err
+}
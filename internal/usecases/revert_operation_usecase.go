@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// Причины, по которым PR не восстановлен при откате
+const (
+	RevertSkipNotFound = "not_found"
+	RevertSkipNotOpen  = "not_open"
+	RevertSkipChanged  = "changed"
+)
+
+// SkippedPullRequest PR, ревьюеров которого не вернули
+type SkippedPullRequest struct {
+	PullRequestID string
+	Reason        string
+}
+
+type RevertResult struct {
+	OperationID      string
+	ReactivatedCount int
+	RestoredPRIDs    []string
+	SkippedPRs       []SkippedPullRequest
+}
+
+type RevertOperationUseCase struct {
+	operations OperationStorage
+	users      UserStorage
+	prs        PullRequestStorage
+	clock      ClockAdapter
+	tx         Transactor
+	audit      Auditor
+	history    HistoryRecorder
+	events     EventRecorder
+	log        *slog.Logger
+}
+
+func NewRevertOperationUseCase(
+	operationStorage OperationStorage,
+	userStorage UserStorage,
+	prStorage PullRequestStorage,
+	clock ClockAdapter,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
+	log *slog.Logger,
+) *RevertOperationUseCase {
+	return &RevertOperationUseCase{
+		operations: operationStorage,
+		users:      userStorage,
+		prs:        prStorage,
+		clock:      clock,
+		tx:         tx,
+		audit:      audit,
+		history:    history,
+		events:     events,
+		log:        log,
+	}
+}
+
+// Revert откатывает массовую деактивацию: активирует пользователей и возвращает
+// прежних ревьюеров в PR, которые всё ещё OPEN и не менялись после операции.
+func (uc *RevertOperationUseCase) Revert(ctx context.Context, operationID string) (RevertResult, error) {
+	uc.log.InfoContext(ctx, "откатываем операцию", "operation_id", operationID)
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (RevertResult, error) {
+		return uc.revert(ctx, operationID)
+	})
+}
+
+func (uc *RevertOperationUseCase) revert(ctx context.Context, operationID string) (RevertResult, error) {
+	operation, err := uc.operations.LockOperation(ctx, operationID)
+	if err != nil {
+		uc.log.WarnContext(ctx, "операция не найдена", "operation_id", operationID, "error", err)
+		return RevertResult{}, err
+	}
+	if operation.Status == domain.OperationStatusReverted {
+		uc.log.WarnContext(ctx, "операция уже откачена", "operation_id", operationID)
+		return RevertResult{}, domain.ErrOperationReverted
+	}
+
+	result := RevertResult{OperationID: operationID}
+
+	result.ReactivatedCount, err = uc.reactivateUsers(ctx, operation.UserIDs)
+	if err != nil {
+		return RevertResult{}, err
+	}
+
+	for _, snapshot := range operation.PullRequests {
+		reason, err := uc.restorePullRequest(ctx, snapshot)
+		if err != nil {
+			return RevertResult{}, err
+		}
+		if reason != "" {
+			uc.log.InfoContext(ctx, "PR не восстановлен", "pr_id", snapshot.PullRequestID, "reason", reason)
+			result.SkippedPRs = append(result.SkippedPRs, SkippedPullRequest{PullRequestID: snapshot.PullRequestID, Reason: reason})
+			continue
+		}
+		result.RestoredPRIDs = append(result.RestoredPRIDs, snapshot.PullRequestID)
+	}
+
+	before := operation
+	if err := operation.MarkReverted(uc.clock.Now()); err != nil {
+		return RevertResult{}, err
+	}
+	if err := uc.operations.UpdateOperation(ctx, operation); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка обновления операции", "operation_id", operationID, "error", err)
+		return RevertResult{}, err
+	}
+	if err := uc.audit.Record(ctx, domain.AuditOperationReverted, domain.AuditEntityOperation, operation.ID, before, operation); err != nil {
+		return RevertResult{}, err
+	}
+
+	uc.log.InfoContext(ctx, "операция откачена",
+		"operation_id", operationID,
+		"reactivated", result.ReactivatedCount,
+		"restored_prs", len(result.RestoredPRIDs),
+		"skipped_prs", len(result.SkippedPRs),
+	)
+	return result, nil
+}
+
+// reactivateUsers активирует пользователей операции, уже активных пропускает
+func (uc *RevertOperationUseCase) reactivateUsers(ctx context.Context, userIDs []string) (int, error) {
+	reactivated := 0
+	for _, userID := range userIDs {
+		user, err := uc.users.GetUser(ctx, userID)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			uc.log.WarnContext(ctx, "не найден пользователь", "user_id", userID)
+			continue
+		}
+		if err != nil {
+			uc.log.ErrorContext(ctx, "ошибка получения пользователя", "user_id", userID, "error", err)
+			return 0, err
+		}
+		if user.IsActive {
+			continue
+		}
+
+		before := user
+		user.IsActive = true
+		if err := uc.users.UpdateUser(ctx, user); err != nil {
+			uc.log.ErrorContext(ctx, "ошибка активации пользователя", "user_id", userID, "error", err)
+			return 0, err
+		}
+		if err := uc.audit.Record(ctx, domain.AuditUserActivated, domain.AuditEntityUser, user.ID, before, user); err != nil {
+			return 0, err
+		}
+		if err := uc.events.Record(ctx, userActivityEvent(user)); err != nil {
+			return 0, err
+		}
+		reactivated++
+	}
+	return reactivated, nil
+}
+
+// restorePullRequest возвращает ревьюеров PR; непустая причина значит, что PR пропущен
+func (uc *RevertOperationUseCase) restorePullRequest(ctx context.Context, snapshot domain.PullRequestSnapshot) (string, error) {
+	pr, err := uc.prs.GetPullRequest(ctx, snapshot.PullRequestID)
+	if errors.Is(err, domain.ErrPullRequestNotFound) {
+		return RevertSkipNotFound, nil
+	}
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения PR", "pr_id", snapshot.PullRequestID, "error", err)
+		return "", err
+	}
+	if pr.Status != domain.PRStatusOpen {
+		return RevertSkipNotOpen, nil
+	}
+	if !snapshot.Unchanged(pr) {
+		return RevertSkipChanged, nil
+	}
+
+	before := pr
+	pr.RequiredReviewers = append([]string(nil), snapshot.RequiredBefore...)
+	pr.AssignReviewers(append([]string(nil), snapshot.ReviewersBefore...))
+	if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка восстановления PR", "pr_id", pr.ID, "error", err)
+		return "", err
+	}
+	if err := uc.audit.Record(ctx, domain.AuditReviewersRestored, domain.AuditEntityPullRequest, pr.ID, before, pr); err != nil {
+		return "", err
+	}
+	entries := reviewerChanges(before, pr, domain.PRHistoryReasonRevert)
+	if err := uc.history.Record(ctx, entries...); err != nil {
+		return "", err
+	}
+	if err := uc.events.Record(ctx, reviewerEvents(pr, entries)...); err != nil {
+		return "", err
+	}
+
+	uc.log.InfoContext(ctx, "ревьюеры PR восстановлены", "pr_id", pr.ID, "reviewers", pr.Reviewers)
+	return "", nil
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// maxNotificationAttempts после стольких попыток доставки сообщения outbox недоставленные уведомления
+// больше не повторяются: канал, который столько раз не принял сообщение, скорее всего настроен неверно.
+const maxNotificationAttempts = 8
+
+// ReviewNotifier EventPublisher, который шлёт ревьюерам личные уведомления о назначениях.
+// Доставка учитывается по каждому получателю и каналу в NotificationDeliveryStorage: ошибка канала
+// возвращается релею, и при повторе сообщения outbox досылается только недоставленное.
+// Уведомление никогда не откатывает само назначение, оно уходит уже после коммита.
+type ReviewNotifier struct {
+	sender     notificationSender
+	prs        PullRequestStorage
+	deliveries NotificationDeliveryStorage
+	clock      ClockAdapter
+	log        *slog.Logger
+}
+
+func NewReviewNotifier(
+	notifiers []Notifier,
+	identities IdentityResolver,
+	prs PullRequestStorage,
+	users UserStorage,
+	deliveries NotificationDeliveryStorage,
+	clock ClockAdapter,
+	log *slog.Logger,
+) *ReviewNotifier {
+	return &ReviewNotifier{
+		sender:     newNotificationSender(notifiers, identities, users, log),
+		prs:        prs,
+		deliveries: deliveries,
+		clock:      clock,
+		log:        log,
+	}
+}
+
+// recipient кому и какое уведомление отправить; otherID - второй ревьюер замены.
+type recipient struct {
+	kind    string
+	userID  string
+	otherID string
+}
+
+func (n *ReviewNotifier) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	prID, recipients, err := notificationRecipients(message)
+	if err != nil {
+		n.log.ErrorContext(ctx, "не удалось разобрать событие для уведомлений", "id", message.ID, "error", err)
+		return nil
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	pr, err := n.prs.GetPullRequest(ctx, prID)
+	if errors.Is(err, domain.ErrPullRequestNotFound) {
+		n.log.WarnContext(ctx, "PR для уведомлений не найден", "pr_id", prID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := n.deliveries.ListNotificationDeliveries(ctx, message.ID)
+	if err != nil {
+		return err
+	}
+	delivered := make(map[string]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		delivered[delivery.UserID+":"+delivery.Provider] = true
+	}
+
+	var errs []error
+	for _, r := range recipients {
+		notification := domain.Notification{
+			Kind:          r.kind,
+			UserID:        r.userID,
+			PullRequestID: pr.ID,
+			Title:         pr.Title,
+			AuthorName:    n.sender.userName(ctx, pr.AuthorID),
+		}
+		if r.otherID != "" {
+			notification.OtherReviewerName = n.sender.userName(ctx, r.otherID)
+		}
+
+		pending := func(provider string) bool {
+			return !delivered[r.userID+":"+provider]
+		}
+		sent := func(ctx context.Context, provider string) error {
+			return n.deliveries.CreateNotificationDelivery(ctx, domain.NotificationDelivery{
+				MessageID:   message.ID,
+				UserID:      r.userID,
+				Provider:    provider,
+				DeliveredAt: n.clock.Now(),
+			})
+		}
+		if err := n.sender.sendPending(ctx, notification, pending, sent); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		if message.Attempts >= maxNotificationAttempts {
+			n.log.ErrorContext(ctx, "уведомления не доставлены, попытки исчерпаны", "id", message.ID, "attempts", message.Attempts, "error", err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// notificationRecipients получатели уведомлений по событию outbox.
+func notificationRecipients(message domain.OutboxMessage) (string, []recipient, error) {
+	switch message.EventType {
+	case domain.EventReviewerAssigned:
+		var event domain.ReviewerAssigned
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return "", nil, err
+		}
+		return event.PullRequestID, []recipient{
+			{kind: domain.NotificationAssigned, userID: event.ReviewerID},
+		}, nil
+	case domain.EventReviewerReplaced:
+		var event domain.ReviewerReplaced
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return "", nil, err
+		}
+		return event.PullRequestID, []recipient{
+			{kind: domain.NotificationReassigned, userID: event.NewReviewerID, otherID: event.OldReviewerID},
+			{kind: domain.NotificationUnassigned, userID: event.OldReviewerID, otherID: event.NewReviewerID},
+		}, nil
+	case domain.EventReviewerRemoved:
+		var event domain.ReviewerRemoved
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return "", nil, err
+		}
+		return event.PullRequestID, []recipient{
+			{kind: domain.NotificationUnassigned, userID: event.ReviewerID},
+		}, nil
+	default:
+		return "", nil, nil
+	}
+}
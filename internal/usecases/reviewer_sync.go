@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// maxReviewerSyncAttempts после стольких доставок события запрос ревью считается проваленным
+const maxReviewerSyncAttempts = 10
+
+// ReviewerSync EventPublisher, который переносит назначения ревьюеров в pull request на GitHub.
+// Повторы - это повторы outbox с его паузами. Если хостинг отказал окончательно или попытки кончились,
+// событие подтверждается, а в аудит пишется pull_request.review_request_failed.
+type ReviewerSync struct {
+	publisher  ReviewerPublisher
+	identities IdentityResolver
+	audit      Auditor
+	log        *slog.Logger
+}
+
+func NewReviewerSync(publisher ReviewerPublisher, identities IdentityResolver, audit Auditor, log *slog.Logger) *ReviewerSync {
+	return &ReviewerSync{
+		publisher:  publisher,
+		identities: identities,
+		audit:      audit,
+		log:        log,
+	}
+}
+
+// reviewRequestFailure подробности проваленного запроса ревью для аудита
+type reviewRequestFailure struct {
+	Requested []string `json:"requested,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Attempts  int      `json:"attempts"`
+	Error     string   `json:"error"`
+}
+
+func (s *ReviewerSync) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	var prID string
+	var requested, removed []string
+	switch message.EventType {
+	case domain.EventReviewerAssigned:
+		var event domain.ReviewerAssigned
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return err
+		}
+		prID, requested = event.PullRequestID, []string{event.ReviewerID}
+	case domain.EventReviewerReplaced:
+		var event domain.ReviewerReplaced
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return err
+		}
+		prID, requested, removed = event.PullRequestID, []string{event.NewReviewerID}, []string{event.OldReviewerID}
+	case domain.EventReviewerRemoved:
+		var event domain.ReviewerRemoved
+		if err := json.Unmarshal(message.Payload, &event); err != nil {
+			return err
+		}
+		prID, removed = event.PullRequestID, []string{event.ReviewerID}
+	default:
+		return nil
+	}
+
+	repository, number, ok := ParseGitHubPullRequestID(prID)
+	if !ok {
+		return nil
+	}
+
+	err := s.sync(ctx, repository, number, requested, removed)
+	if err == nil {
+		s.log.InfoContext(ctx, "ревьюеры отправлены в GitHub", "pr_id", prID, "requested", requested, "removed", removed)
+		return nil
+	}
+	if !errors.Is(err, domain.ErrReviewRequestRejected) && message.Attempts < maxReviewerSyncAttempts {
+		return err
+	}
+
+	s.log.ErrorContext(ctx, "не удалось отправить ревьюеров в GitHub", "pr_id", prID, "attempts", message.Attempts, "error", err)
+	return s.audit.Record(ctx, domain.AuditReviewRequestFailed, domain.AuditEntityPullRequest, prID, nil, reviewRequestFailure{
+		Requested: requested,
+		Removed:   removed,
+		Attempts:  message.Attempts,
+		Error:     err.Error(),
+	})
+}
+
+func (s *ReviewerSync) sync(ctx context.Context, repository string, number int, requested, removed []string) error {
+	removedLogins, err := s.logins(ctx, removed)
+	if err != nil {
+		return err
+	}
+	if len(removedLogins) > 0 {
+		if err := s.publisher.RemoveReviewRequests(ctx, repository, number, removedLogins); err != nil {
+			return err
+		}
+	}
+
+	requestedLogins, err := s.logins(ctx, requested)
+	if err != nil {
+		return err
+	}
+	if len(requestedLogins) > 0 {
+		if err := s.publisher.RequestReviewers(ctx, repository, number, requestedLogins); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logins возвращает логины GitHub; пользователи без привязанного логина пропускаются.
+func (s *ReviewerSync) logins(ctx context.Context, userIDs []string) ([]string, error) {
+	logins := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		login, err := s.identities.ResolveLogin(ctx, domain.IdentityProviderGitHub, userID)
+		if errors.Is(err, domain.ErrIdentityNotFound) {
+			s.log.WarnContext(ctx, "у ревьюера нет логина GitHub", "user_id", userID)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, login)
+	}
+	return logins, nil
+}
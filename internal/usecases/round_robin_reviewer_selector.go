@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// RoundRobinReviewerSelector назначает участников команды строго по очереди.
+// Курсор хранит последнего назначенного и общий для всех экземпляров сервиса.
+type RoundRobinReviewerSelector struct {
+	rotations RotationStorage
+	log       *slog.Logger
+}
+
+func NewRoundRobinReviewerSelector(rotations RotationStorage, log *slog.Logger) *RoundRobinReviewerSelector {
+	return &RoundRobinReviewerSelector{
+		rotations: rotations,
+		log:       log,
+	}
+}
+
+// Select берёт count кандидатов, следующих по user_id за последним назначенным,
+// с переходом в начало списка. Кандидаты уже без автора, неактивных и отсутствующих.
+// Курсор блокируется до конца транзакции, а сдвигается в RecordSelection по итоговому выбору.
+// В пробном запуске сохранённый курсор только читается, а сдвигается копия в состоянии запуска.
+func (s *RoundRobinReviewerSelector) Select(ctx context.Context, _ domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	if count <= 0 || len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ordered := append([]domain.User(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	// кандидаты всегда из одной команды: автора или заменяемого ревьюера
+	teamName := ordered[0].TeamName
+
+	lastUserID, err := s.rotation(ctx, teamName, s.rotations.LockRotation)
+	if err != nil {
+		s.log.ErrorContext(ctx, "ошибка чтения ротации", "team_name", teamName, "error", err)
+		return nil, err
+	}
+
+	return nextInRotation(ordered, lastUserID, count), nil
+}
+
+// RecordSelection сдвигает курсор на того из выбранных, кто стоит в очереди дальше всех.
+// Курсор уже заблокирован в Select этой же транзакции.
+func (s *RoundRobinReviewerSelector) RecordSelection(ctx context.Context, _ domain.PullRequest, selected []domain.User) error {
+	if len(selected) == 0 {
+		return nil
+	}
+
+	teamName := selected[0].TeamName
+	lastUserID, err := s.rotation(ctx, teamName, s.rotations.GetRotation)
+	if err != nil {
+		s.log.ErrorContext(ctx, "ошибка чтения ротации", "team_name", teamName, "error", err)
+		return err
+	}
+
+	next := selected[0].ID
+	for _, user := range selected[1:] {
+		if rotationBefore(next, user.ID, lastUserID) {
+			next = user.ID
+		}
+	}
+
+	if run := dryRunFrom(ctx); run != nil {
+		run.rotations[teamName] = next
+		return nil
+	}
+	if err := s.rotations.SetRotation(ctx, teamName, next); err != nil {
+		s.log.ErrorContext(ctx, "ошибка продвижения ротации", "team_name", teamName, "error", err)
+		return err
+	}
+	return nil
+}
+
+// rotation возвращает последнего назначенного через read. В пробном запуске сохранённый курсор
+// читается без блокировки, а если запуск уже сдвигал курсор команды, берётся его копия.
+func (s *RoundRobinReviewerSelector) rotation(ctx context.Context, teamName string, read func(context.Context, string) (string, error)) (string, error) {
+	run := dryRunFrom(ctx)
+	if run == nil {
+		return read(ctx, teamName)
+	}
+	if lastUserID, ok := run.rotations[teamName]; ok {
+		return lastUserID, nil
+	}
+	return s.rotations.GetRotation(ctx, teamName)
+}
+
+// nextInRotation возвращает до count пользователей после lastUserID по кругу.
+func nextInRotation(ordered []domain.User, lastUserID string, count int) []domain.User {
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].ID > lastUserID
+	})
+
+	selected := make([]domain.User, 0, count)
+	for i := 0; i < len(ordered) && len(selected) < count; i++ {
+		selected = append(selected, ordered[(start+i)%len(ordered)])
+	}
+	return selected
+}
+
+// rotationBefore стоит ли a в очереди после lastUserID раньше b.
+func rotationBefore(a, b, lastUserID string) bool {
+	aAfter, bAfter := a > lastUserID, b > lastUserID
+	if aAfter != bAfter {
+		return aAfter
+	}
+	return a < b
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"slices"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// selectWithSeniorPolicy выбирает count ревьюеров через selector и, если команда требует senior,
+// гарантирует его среди уже назначенных pr.Reviewers и выбранных. Стратегия вызывается один раз
+// и упорядочивает всех кандидатов, senior берётся из того же порядка. Итоговый выбор
+// передаётся стратегии с состоянием, например ротации; senior, добавленный политикой
+// вне очереди, в него не входит и очередь не сдвигает.
+func selectWithSeniorPolicy(
+	ctx context.Context,
+	selector ReviewerSelector,
+	users UserStorage,
+	team domain.Team,
+	pr domain.PullRequest,
+	candidates []domain.User,
+	count int,
+) ([]domain.User, error) {
+	ranked, err := selector.Select(ctx, pr, candidates, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+	inTurn := firstUsers(ranked, count)
+
+	selected := slices.Clone(inTurn)
+	if team.RequireSeniorReviewer {
+		selected, err = withSeniorReviewer(ctx, users, pr, ranked, selected, count)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recorded := slices.DeleteFunc(slices.Clone(selected), func(user domain.User) bool {
+		return !containsUser(inTurn, user.ID)
+	})
+	if err := recordSelection(ctx, selector, pr, recorded); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// withSeniorReviewer заменяет последнего выбранного первым senior из ranked,
+// если senior нет ни среди назначенных, ни среди выбранных.
+func withSeniorReviewer(
+	ctx context.Context,
+	users UserStorage,
+	pr domain.PullRequest,
+	ranked []domain.User,
+	selected []domain.User,
+	count int,
+) ([]domain.User, error) {
+	covered, err := hasSeniorReviewer(ctx, users, pr.Reviewers)
+	if err != nil {
+		return nil, err
+	}
+	if covered || containsSenior(selected) {
+		return selected, nil
+	}
+
+	index := slices.IndexFunc(ranked, domain.User.IsSenior)
+	if index < 0 || count <= 0 {
+		return nil, domain.ErrNoSeniorReviewer
+	}
+
+	if len(selected) < count {
+		return append(selected, ranked[index]), nil
+	}
+	selected[len(selected)-1] = ranked[index]
+	return selected, nil
+}
+
+// recordSelection сообщает итоговый выбор стратегии с состоянием.
+func recordSelection(ctx context.Context, selector ReviewerSelector, pr domain.PullRequest, selected []domain.User) error {
+	recorder, ok := selector.(ReviewerSelectionRecorder)
+	if !ok {
+		return nil
+	}
+	return recorder.RecordSelection(ctx, pr, selected)
+}
+
+// hasSeniorReviewer проверяет, есть ли senior среди указанных ревьюеров.
+func hasSeniorReviewer(ctx context.Context, users UserStorage, reviewerIDs []string) (bool, error) {
+	for _, reviewerID := range reviewerIDs {
+		user, err := users.GetUser(ctx, reviewerID)
+		if err != nil {
+			return false, err
+		}
+		if user.IsSenior() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func containsSenior(users []domain.User) bool {
+	for _, user := range users {
+		if user.IsSenior() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type SetUserAbsenceUseCase struct {
+	users UserStorage
+	topUp ReviewerTopUp
+	clock ClockAdapter
+	tx    Transactor
+	audit Auditor
+	log   *slog.Logger
+}
+
+func NewSetUserAbsenceUseCase(
+	storage UserStorage,
+	topUp ReviewerTopUp,
+	clock ClockAdapter,
+	tx Transactor,
+	audit Auditor,
+	log *slog.Logger,
+) *SetUserAbsenceUseCase {
+	return &SetUserAbsenceUseCase{
+		users: storage,
+		topUp: topUp,
+		clock: clock,
+		tx:    tx,
+		audit: audit,
+		log:   log,
+	}
+}
+
+// SetAbsence планирует отсутствие пользователя [from, until) или снимает его, если оба nil.
+// Отсутствующих не назначают ревьюерами, уже назначенные ревью остаются за ними.
+// Если после снятия отсутствия пользователь снова доступен, PR его команды дозаполняются.
+func (uc *SetUserAbsenceUseCase) SetAbsence(ctx context.Context, id string, from, until *time.Time) (domain.User, error) {
+	uc.log.InfoContext(ctx, "изменяем отсутствие пользователя", "user_id", id, "absent_from", from, "absent_until", until)
+
+	if err := domain.ValidateAbsence(from, until); err != nil {
+		uc.log.WarnContext(ctx, "некорректное отсутствие", "user_id", id, "error", err)
+		return domain.User{}, err
+	}
+
+	user, _, err := uc.update(ctx, id, func(domain.User) (*time.Time, *time.Time, bool) {
+		return from, until, true
+	})
+	return user, err
+}
+
+// ClearExpiredAbsence снимает отсутствие, только если оно уже закончилось. Условие проверяется
+// под блокировкой строки, поэтому отсутствие, продлённое после выборки кандидатов, не снимается.
+// Возвращает, было ли отсутствие снято.
+func (uc *SetUserAbsenceUseCase) ClearExpiredAbsence(ctx context.Context, id string) (bool, error) {
+	_, changed, err := uc.update(ctx, id, func(user domain.User) (*time.Time, *time.Time, bool) {
+		expired := user.AbsentUntil != nil && !uc.clock.Now().Before(*user.AbsentUntil)
+		return nil, nil, expired
+	})
+	return changed, err
+}
+
+// update меняет отсутствие заблокированного пользователя на то, что вернёт change, и пишет аудит.
+// change видит текущее состояние и может отказаться от изменения.
+func (uc *SetUserAbsenceUseCase) update(
+	ctx context.Context,
+	id string,
+	change func(domain.User) (from, until *time.Time, ok bool),
+) (domain.User, bool, error) {
+	var changed, returned bool
+	user, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.User, error) {
+		user, err := uc.users.LockUser(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "пользователь не найден", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+		from, until, ok := change(user)
+		if !ok || sameTime(user.AbsentFrom, from) && sameTime(user.AbsentUntil, until) {
+			return user, nil
+		}
+
+		before := user
+		user.AbsentFrom, user.AbsentUntil = from, until
+		if err := uc.users.UpdateUser(ctx, user); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить пользователя", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+		if err := uc.audit.Record(ctx, domain.AuditUserAbsenceUpdated, domain.AuditEntityUser, user.ID, before, user); err != nil {
+			return domain.User{}, err
+		}
+
+		// начавшееся отсутствие могло уже закончиться, но дозаполнения после него ещё не было
+		now := uc.clock.Now()
+		started := before.AbsentFrom != nil && !now.Before(*before.AbsentFrom)
+		returned = started && !user.IsAbsentAt(now)
+		changed = true
+		return user, nil
+	})
+	if err != nil {
+		return domain.User{}, false, err
+	}
+
+	// вернувшийся пользователь может забрать ревью в недоукомплектованных PR;
+	// ошибка дозаполнения не отменяет изменение
+	if returned && user.IsActive && user.TeamName != "" {
+		if _, err := uc.topUp.TopUpTeam(ctx, user.TeamName); err != nil {
+			uc.log.WarnContext(ctx, "не удалось дозаполнить PR команды", "team_name", user.TeamName, "error", err)
+		}
+	}
+	return user, changed, nil
+}
+
+// sameTime сравнивает необязательные моменты времени.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
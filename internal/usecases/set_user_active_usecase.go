@@ -8,14 +8,29 @@ import (
 )
 
 type SetUserActiveUseCase struct {
-	users UserStorage
-	log   *slog.Logger
+	users  UserStorage
+	topUp  ReviewerTopUp
+	tx     Transactor
+	audit  Auditor
+	events EventRecorder
+	log    *slog.Logger
 }
 
-func NewSetUserActiveUseCase(storage UserStorage, log *slog.Logger) *SetUserActiveUseCase {
+func NewSetUserActiveUseCase(
+	storage UserStorage,
+	topUp ReviewerTopUp,
+	tx Transactor,
+	audit Auditor,
+	events EventRecorder,
+	log *slog.Logger,
+) *SetUserActiveUseCase {
 	return &SetUserActiveUseCase{
-		users: storage,
-		log:   log,
+		users:  storage,
+		topUp:  topUp,
+		tx:     tx,
+		audit:  audit,
+		events: events,
+		log:    log,
 	}
 }
 
@@ -23,16 +38,44 @@ func NewSetUserActiveUseCase(storage UserStorage, log *slog.Logger) *SetUserActi
 func (uc *SetUserActiveUseCase) SetActive(ctx context.Context, id string, isActive bool) (domain.User, error) {
 	uc.log.InfoContext(ctx, "изменяем активность пользователя", "user_id", id, "is_active", isActive)
 
-	user, err := uc.users.GetUser(ctx, id)
+	var wasActive bool
+	user, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.User, error) {
+		user, err := uc.users.GetUser(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "пользователь не найден", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+
+		before := user
+		wasActive = user.IsActive
+		user.IsActive = isActive
+		if err := uc.users.UpdateUser(ctx, user); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить пользователя", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+		if wasActive == isActive {
+			return user, nil
+		}
+
+		action := domain.AuditUserDeactivated
+		if isActive {
+			action = domain.AuditUserActivated
+		}
+		if err := uc.audit.Record(ctx, action, domain.AuditEntityUser, user.ID, before, user); err != nil {
+			return domain.User{}, err
+		}
+		return user, uc.events.Record(ctx, userActivityEvent(user))
+	})
 	if err != nil {
-		uc.log.WarnContext(ctx, "пользователь не найден", "user_id", id, "error", err)
 		return domain.User{}, err
 	}
 
-	user.IsActive = isActive
-	if err := uc.users.UpdateUser(ctx, user); err != nil {
-		uc.log.ErrorContext(ctx, "не удалось обновить пользователя", "user_id", id, "error", err)
-		return domain.User{}, err
+	// активированный пользователь может забрать ревью в недоукомплектованных PR;
+	// ошибка дозаполнения не отменяет активацию
+	if isActive && !wasActive && user.TeamName != "" {
+		if _, err := uc.topUp.TopUpTeam(ctx, user.TeamName); err != nil {
+			uc.log.WarnContext(ctx, "не удалось дозаполнить PR команды", "team_name", user.TeamName, "error", err)
+		}
 	}
 
 	uc.log.InfoContext(ctx, "статус пользователя изменён", "user_id", id, "is_active", user.IsActive)
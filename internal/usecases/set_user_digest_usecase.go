@@ -0,0 +1,51 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type SetUserDigestUseCase struct {
+	users UserStorage
+	tx    Transactor
+	audit Auditor
+	log   *slog.Logger
+}
+
+func NewSetUserDigestUseCase(storage UserStorage, tx Transactor, audit Auditor, log *slog.Logger) *SetUserDigestUseCase {
+	return &SetUserDigestUseCase{
+		users: storage,
+		tx:    tx,
+		audit: audit,
+		log:   log,
+	}
+}
+
+// SetDigest подписывает пользователя на ежедневный дайджест или отписывает от него.
+func (uc *SetUserDigestUseCase) SetDigest(ctx context.Context, id string, enabled bool) (domain.User, error) {
+	uc.log.InfoContext(ctx, "изменяем подписку на дайджест", "user_id", id, "enabled", enabled)
+
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.User, error) {
+		user, err := uc.users.GetUser(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "пользователь не найден", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+		if user.DigestEnabled == enabled {
+			return user, nil
+		}
+
+		before := user
+		user.DigestEnabled = enabled
+		if err := uc.users.UpdateUser(ctx, user); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить пользователя", "user_id", id, "error", err)
+			return domain.User{}, err
+		}
+		if err := uc.audit.Record(ctx, domain.AuditUserDigestUpdated, domain.AuditEntityUser, user.ID, before, user); err != nil {
+			return domain.User{}, err
+		}
+		return user, nil
+	})
+}
@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// Действия с pull request во внешней системе; контроллеры приводят к ним события GitHub и GitLab
+const (
+	ExternalActionOpened           = "opened"
+	ExternalActionReopened         = "reopened"
+	ExternalActionReadyForReview   = "ready_for_review"
+	ExternalActionConvertedToDraft = "converted_to_draft"
+	ExternalActionMerged           = "merged"
+	ExternalActionClosed           = "closed"
+)
+
+// Итог синхронизации события
+const (
+	SyncResultCreated  = "created"
+	SyncResultMerged   = "merged"
+	SyncResultDraft    = "draft"
+	SyncResultReady    = "ready"
+	SyncResultClosed   = "closed"
+	SyncResultReopened = "reopened"
+	SyncResultIgnored  = "ignored"
+)
+
+// ExternalPullRequestEvent изменение pull request во внешней системе, только нужные поля.
+type ExternalPullRequestEvent struct {
+	Provider      string
+	Action        string
+	PullRequestID string
+	Title         string
+	// AuthorLogin пустой, если внешняя система не сообщила, кто автор
+	AuthorLogin string
+	Draft       bool
+}
+
+// GitHubPullRequestID ID pull request из GitHub: "<owner>/<repo>#<number>".
+func GitHubPullRequestID(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", repository, number)
+}
+
+// ParseGitHubPullRequestID разбирает ID из GitHubPullRequestID; false - PR заведён не из GitHub.
+func ParseGitHubPullRequestID(id string) (string, int, bool) {
+	repository, rawNumber, ok := strings.Cut(id, "#")
+	if !ok || strings.Count(repository, "/") != 1 {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(rawNumber)
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return repository, number, true
+}
+
+// GitLabMergeRequestID ID merge request из GitLab: "<group>/<project>!<iid>".
+func GitLabMergeRequestID(project string, iid int) string {
+	return fmt.Sprintf("%s!%d", project, iid)
+}
+
+// SyncPullRequestUseCase переводит события pull request из внешних систем в вызовы use case'ов.
+type SyncPullRequestUseCase struct {
+	create     *CreatePullRequestUseCase
+	merge      *MergePullRequestUseCase
+	lifecycle  *PullRequestLifecycleUseCase
+	identities IdentityResolver
+	log        *slog.Logger
+}
+
+func NewSyncPullRequestUseCase(
+	create *CreatePullRequestUseCase,
+	merge *MergePullRequestUseCase,
+	lifecycle *PullRequestLifecycleUseCase,
+	identities IdentityResolver,
+	log *slog.Logger,
+) *SyncPullRequestUseCase {
+	return &SyncPullRequestUseCase{
+		create:     create,
+		merge:      merge,
+		lifecycle:  lifecycle,
+		identities: identities,
+		log:        log,
+	}
+}
+
+// Sync обрабатывает событие и возвращает, что сделано.
+// PR, открытый черновиком, заводится при переводе в ready_for_review. Возврат в черновик ставит ревью
+// на паузу (статус DRAFT), ready_for_review снимает паузу. Закрытие без merge переводит PR в CLOSED,
+// повторное открытие возвращает его. Повторная доставка события ошибкой не считается.
+func (uc *SyncPullRequestUseCase) Sync(ctx context.Context, event ExternalPullRequestEvent) (string, error) {
+	switch {
+	case event.Action == ExternalActionOpened && !event.Draft:
+		return uc.createPullRequest(ctx, event)
+	case event.Action == ExternalActionReopened:
+		return uc.reopen(ctx, event)
+	case event.Action == ExternalActionClosed:
+		return uc.close(ctx, event.PullRequestID)
+	case event.Action == ExternalActionReadyForReview:
+		return uc.readyForReview(ctx, event)
+	case event.Action == ExternalActionConvertedToDraft:
+		return uc.convertToDraft(ctx, event.PullRequestID)
+	case event.Action == ExternalActionMerged:
+		return uc.mergePullRequest(ctx, event.PullRequestID)
+	default:
+		uc.log.DebugContext(ctx, "событие внешней системы пропущено",
+			"provider", event.Provider,
+			"pr_id", event.PullRequestID,
+			"action", event.Action,
+			"draft", event.Draft,
+		)
+		return SyncResultIgnored, nil
+	}
+}
+
+// createPullRequest заводит PR от сопоставленного автора. Без автора PR не заводится:
+// назначить ревьюеров, не зная автора, нельзя.
+func (uc *SyncPullRequestUseCase) createPullRequest(ctx context.Context, event ExternalPullRequestEvent) (string, error) {
+	if event.AuthorLogin == "" {
+		uc.log.WarnContext(ctx, "автор pull request неизвестен, PR не заводится", "provider", event.Provider, "pr_id", event.PullRequestID)
+		return SyncResultIgnored, nil
+	}
+
+	authorID, err := uc.identities.ResolveUser(ctx, event.Provider, event.AuthorLogin)
+	if err != nil {
+		uc.log.WarnContext(ctx, "не удалось сопоставить автора", "provider", event.Provider, "login", event.AuthorLogin, "error", err)
+		return "", err
+	}
+
+	_, err = uc.create.Create(ctx, event.PullRequestID, event.Title, authorID, nil)
+	if errors.Is(err, domain.ErrPullRequestExists) {
+		return SyncResultIgnored, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return SyncResultCreated, nil
+}
+
+// readyForReview снимает с паузы известный черновик, а неизвестный PR заводит.
+func (uc *SyncPullRequestUseCase) readyForReview(ctx context.Context, event ExternalPullRequestEvent) (string, error) {
+	_, err := uc.lifecycle.MarkReady(ctx, event.PullRequestID)
+	switch {
+	case errors.Is(err, domain.ErrPullRequestNotFound):
+		return uc.createPullRequest(ctx, event)
+	case errors.Is(err, domain.ErrPullRequestMerged), errors.Is(err, domain.ErrPullRequestClosed):
+		return SyncResultIgnored, nil
+	case err != nil:
+		return "", err
+	}
+	return SyncResultReady, nil
+}
+
+func (uc *SyncPullRequestUseCase) convertToDraft(ctx context.Context, prID string) (string, error) {
+	_, err := uc.lifecycle.ConvertToDraft(ctx, prID)
+	if isSkippedTransition(err) {
+		uc.log.WarnContext(ctx, "возврат в черновик пропущен", "pr_id", prID, "error", err)
+		return SyncResultIgnored, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return SyncResultDraft, nil
+}
+
+func (uc *SyncPullRequestUseCase) close(ctx context.Context, prID string) (string, error) {
+	_, err := uc.lifecycle.Close(ctx, prID)
+	if isSkippedTransition(err) {
+		uc.log.WarnContext(ctx, "закрытие pull request пропущено", "pr_id", prID, "error", err)
+		return SyncResultIgnored, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return SyncResultClosed, nil
+}
+
+// reopen открывает закрытый PR; неизвестный заводится как при opened, это PR из времени до подключения интеграции.
+func (uc *SyncPullRequestUseCase) reopen(ctx context.Context, event ExternalPullRequestEvent) (string, error) {
+	_, err := uc.lifecycle.Reopen(ctx, event.PullRequestID, event.Draft)
+	switch {
+	case errors.Is(err, domain.ErrPullRequestNotFound) && !event.Draft:
+		return uc.createPullRequest(ctx, event)
+	case isSkippedTransition(err):
+		return SyncResultIgnored, nil
+	case err != nil:
+		return "", err
+	}
+	return SyncResultReopened, nil
+}
+
+// isSkippedTransition смена статуса невозможна, но для внешней системы это не ошибка.
+func isSkippedTransition(err error) bool {
+	return errors.Is(err, domain.ErrPullRequestNotFound) ||
+		errors.Is(err, domain.ErrPullRequestMerged) ||
+		errors.Is(err, domain.ErrPullRequestClosed)
+}
+
+func (uc *SyncPullRequestUseCase) mergePullRequest(ctx context.Context, prID string) (string, error) {
+	_, err := uc.merge.Merge(ctx, prID)
+	if errors.Is(err, domain.ErrPullRequestNotFound) {
+		// PR открыли до подключения интеграции или черновиком
+		uc.log.WarnContext(ctx, "merge неизвестного pull request", "pr_id", prID)
+		return SyncResultIgnored, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return SyncResultMerged, nil
+}
@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// TeamDeactivationParams параметры фоновой массовой деактивации.
+type TeamDeactivationParams struct {
+	TeamName string            `json:"team_name"`
+	Options  DeactivateOptions `json:"options"`
+}
+
+// TeamRebalanceParams параметры фоновой ребалансировки.
+type TeamRebalanceParams struct {
+	TeamName string `json:"team_name"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// TeamDeactivationJob выполняет массовую деактивацию как фоновую задачу.
+// Повтор после сбоя безопасен: уже неактивные пользователи и переназначенные PR пропускаются.
+func TeamDeactivationJob(uc *DeactivateTeamUsersUseCase) JobFunc {
+	return func(ctx context.Context, payload []byte) (any, error) {
+		var params TeamDeactivationParams
+		if err := json.Unmarshal(payload, &params); err != nil {
+			return nil, err
+		}
+		return uc.DeactivateTeamUsers(ctx, params.TeamName, params.Options)
+	}
+}
+
+// TeamRebalanceJob выполняет ребалансировку как фоновую задачу.
+func TeamRebalanceJob(uc *RebalanceTeamUseCase) JobFunc {
+	return func(ctx context.Context, payload []byte) (any, error) {
+		var params TeamRebalanceParams
+		if err := json.Unmarshal(payload, &params); err != nil {
+			return nil, err
+		}
+		return uc.Rebalance(ctx, params.TeamName, params.DryRun)
+	}
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// TopUpReviewersUseCase дозаполняет открытые PR, созданные при нехватке кандидатов.
+type TopUpReviewersUseCase struct {
+	prs      PullRequestStorage
+	teams    TeamStorage
+	users    UserStorage
+	selector ReviewerSelector
+	tx       Transactor
+	audit    Auditor
+	history  HistoryRecorder
+	events   EventRecorder
+	log      *slog.Logger
+}
+
+func NewTopUpReviewersUseCase(
+	prStorage PullRequestStorage,
+	teamStorage TeamStorage,
+	userStorage UserStorage,
+	selector ReviewerSelector,
+	tx Transactor,
+	audit Auditor,
+	history HistoryRecorder,
+	events EventRecorder,
+	log *slog.Logger,
+) *TopUpReviewersUseCase {
+	return &TopUpReviewersUseCase{
+		prs:      prStorage,
+		teams:    teamStorage,
+		users:    userStorage,
+		selector: selector,
+		tx:       tx,
+		audit:    audit,
+		history:  history,
+		events:   events,
+		log:      log,
+	}
+}
+
+// TopUpTeam добавляет ревьюеров в открытые PR команды, где их меньше domain.MaxReviewers.
+// Возвращает количество обновлённых PR.
+func (uc *TopUpReviewersUseCase) TopUpTeam(ctx context.Context, teamName string) (int, error) {
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (int, error) {
+		return uc.topUpTeam(ctx, teamName)
+	})
+}
+
+func (uc *TopUpReviewersUseCase) topUpTeam(ctx context.Context, teamName string) (int, error) {
+	team, err := uc.teams.GetTeam(ctx, teamName)
+	if err != nil {
+		uc.log.WarnContext(ctx, "команда для дозаполнения не найдена", "team_name", teamName, "error", err)
+		return 0, err
+	}
+
+	prs, err := uc.prs.ListOpenPullRequestsByTeam(ctx, teamName)
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка получения открытых PR команды", "team_name", teamName, "error", err)
+		return 0, err
+	}
+
+	toppedUp := 0
+	for _, pr := range prs {
+		if pr.MissingReviewers() == 0 {
+			continue
+		}
+
+		updated, err := uc.topUpPullRequest(ctx, team, pr)
+		if err != nil {
+			return toppedUp, err
+		}
+		if updated {
+			toppedUp++
+		}
+	}
+
+	if toppedUp > 0 {
+		uc.log.InfoContext(ctx, "недоукомплектованные PR дозаполнены", "team_name", teamName, "pr_count", toppedUp)
+	}
+	return toppedUp, nil
+}
+
+// topUpPullRequest подбирает недостающих ревьюеров; PR без подходящих кандидатов пропускается.
+func (uc *TopUpReviewersUseCase) topUpPullRequest(ctx context.Context, team domain.Team, pr domain.PullRequest) (bool, error) {
+	candidates := make([]domain.User, 0, len(team.Users))
+	for _, candidate := range team.ActiveReviewersExcluding(pr.AuthorID) {
+		if !pr.HasReviewer(candidate.ID) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	selected, err := selectWithSeniorPolicy(ctx, uc.selector, uc.users, team, pr, candidates, pr.MissingReviewers())
+	if errors.Is(err, domain.ErrNoSeniorReviewer) {
+		uc.log.WarnContext(ctx, "не удалось выполнить политику senior-ревьюера при дозаполнении", "pr_id", pr.ID, "team_name", team.Name)
+		return false, nil
+	}
+	if err != nil {
+		uc.log.ErrorContext(ctx, "ошибка выбора ревьюеров для дозаполнения", "pr_id", pr.ID, "error", err)
+		return false, err
+	}
+	if len(selected) == 0 {
+		return false, nil
+	}
+
+	before := pr
+	before.Reviewers = append([]string(nil), pr.Reviewers...)
+	for _, reviewer := range selected {
+		if err := pr.AddReviewer(reviewer.ID); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось добавить ревьюера", "pr_id", pr.ID, "reviewer_id", reviewer.ID, "error", err)
+			return false, err
+		}
+	}
+
+	if err := uc.prs.UpdatePullRequest(ctx, pr); err != nil {
+		uc.log.ErrorContext(ctx, "ошибка сохранения дозаполненного PR", "pr_id", pr.ID, "error", err)
+		return false, err
+	}
+	if err := uc.audit.Record(ctx, domain.AuditReviewersToppedUp, domain.AuditEntityPullRequest, pr.ID, before, pr); err != nil {
+		return false, err
+	}
+	entries := reviewerChanges(before, pr, domain.PRHistoryReasonTopUp)
+	if err := uc.history.Record(ctx, entries...); err != nil {
+		return false, err
+	}
+	if err := uc.events.Record(ctx, reviewerEvents(pr, entries)...); err != nil {
+		return false, err
+	}
+
+	uc.log.InfoContext(ctx, "PR дозаполнен", "pr_id", pr.ID, "reviewers", pr.Reviewers)
+	return true, nil
+}
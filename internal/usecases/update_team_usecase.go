@@ -0,0 +1,243 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// TeamUpdate изменения настроек команды; nil и пустые поля не меняются.
+// AddMembers добавляет в команду новых или переводит существующих пользователей.
+type TeamUpdate struct {
+	RequireSeniorReviewer *bool
+	Members               map[string]MemberUpdate
+	AddMembers            []NewMember
+}
+
+// NewMember участник, добавляемый в команду. Settings применяются поверх текущих
+// настроек существующего пользователя или значений по умолчанию для нового.
+type NewMember struct {
+	ID       string
+	Name     string
+	IsActive bool
+	Settings MemberUpdate
+}
+
+// MemberUpdate изменения настроек участника; nil и пустые поля не меняются.
+type MemberUpdate struct {
+	Level         string
+	Timezone      string
+	WorkStartHour *int
+	WorkEndHour   *int
+	ReviewWeight  *float64
+}
+
+type UpdateTeamUseCase struct {
+	teams TeamStorage
+	users UserStorage
+	topUp ReviewerTopUp
+	tx    Transactor
+	audit Auditor
+	log   *slog.Logger
+}
+
+func NewUpdateTeamUseCase(
+	teamStorage TeamStorage,
+	userStorage UserStorage,
+	topUp ReviewerTopUp,
+	tx Transactor,
+	audit Auditor,
+	log *slog.Logger,
+) *UpdateTeamUseCase {
+	return &UpdateTeamUseCase{
+		teams: teamStorage,
+		users: userStorage,
+		topUp: topUp,
+		tx:    tx,
+		audit: audit,
+		log:   log,
+	}
+}
+
+// Update меняет политику команды, состав и настройки её участников.
+func (uc *UpdateTeamUseCase) Update(ctx context.Context, teamName string, update TeamUpdate) (domain.Team, error) {
+	uc.log.InfoContext(ctx, "обновляем настройки команды", "team_name", teamName)
+
+	var before domain.Team
+	team, err := inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.Team, error) {
+		team, err := uc.teams.GetTeam(ctx, teamName)
+		if err != nil {
+			uc.log.WarnContext(ctx, "команда не найдена", "team_name", teamName, "error", err)
+			return domain.Team{}, err
+		}
+		before = team
+		before.Users = append([]domain.User(nil), team.Users...)
+		return uc.update(ctx, team, update)
+	})
+	if err != nil {
+		return domain.Team{}, err
+	}
+
+	// новые участники или вес больше 0 - новая ёмкость: дозаполняем PR команды
+	// после фиксации, ошибка не отменяет обновление
+	if gainedReviewers(before.Users, team.Users) {
+		if _, err := uc.topUp.TopUpTeam(ctx, team.Name); err != nil {
+			uc.log.WarnContext(ctx, "не удалось дозаполнить PR команды", "team_name", team.Name, "error", err)
+		}
+	}
+
+	return team, nil
+}
+
+func (uc *UpdateTeamUseCase) update(ctx context.Context, team domain.Team, update TeamUpdate) (domain.Team, error) {
+	teamName := team.Name
+	before := team
+	before.Users = append([]domain.User(nil), team.Users...)
+
+	for _, added := range update.AddMembers {
+		member, err := upsertMember(ctx, uc.users, uc.audit, uc.log, teamName, added)
+		if err != nil {
+			return domain.Team{}, err
+		}
+		if i := slices.IndexFunc(team.Users, func(user domain.User) bool { return user.ID == member.ID }); i >= 0 {
+			team.Users[i] = member
+		} else {
+			team.Users = append(team.Users, member)
+		}
+	}
+
+	updatedMembers := make(map[string]domain.User, len(update.Members))
+	for userID, changes := range update.Members {
+		member, ok := findUser(team.Users, userID)
+		if !ok {
+			uc.log.WarnContext(ctx, "пользователь не состоит в команде", "user_id", userID, "team_name", teamName)
+			return domain.Team{}, domain.ErrUserNotFound
+		}
+		member, err := applyMemberUpdate(member, changes)
+		if err != nil {
+			uc.log.WarnContext(ctx, "некорректные настройки участника", "user_id", userID, "error", err)
+			return domain.Team{}, err
+		}
+		updatedMembers[userID] = member
+	}
+
+	if update.RequireSeniorReviewer != nil {
+		team.RequireSeniorReviewer = *update.RequireSeniorReviewer
+		if err := uc.teams.UpdateTeam(ctx, team); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить команду", "team_name", teamName, "error", err)
+			return domain.Team{}, err
+		}
+	}
+
+	for i, member := range team.Users {
+		updated, ok := updatedMembers[member.ID]
+		if !ok {
+			continue
+		}
+		if err := uc.users.UpdateUser(ctx, updated); err != nil {
+			uc.log.ErrorContext(ctx, "не удалось обновить участника", "user_id", member.ID, "error", err)
+			return domain.Team{}, err
+		}
+		team.Users[i] = updated
+	}
+
+	if err := uc.audit.Record(ctx, domain.AuditTeamUpdated, domain.AuditEntityTeam, team.Name, before, team); err != nil {
+		return domain.Team{}, err
+	}
+
+	uc.log.InfoContext(ctx, "настройки команды обновлены", "team_name", teamName, "require_senior_reviewer", team.RequireSeniorReviewer)
+	return team, nil
+}
+
+// upsertMember создаёт пользователя в команде teamName или переводит туда существующего.
+// Настройки существующего меняются только теми полями, что заданы в member.Settings.
+func upsertMember(ctx context.Context, users UserStorage, audit Auditor, log *slog.Logger, teamName string, member NewMember) (domain.User, error) {
+	existing, err := users.GetUser(ctx, member.ID)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		user, err := applyMemberUpdate(domain.NewUser(member.ID, member.Name, teamName, member.IsActive), member.Settings)
+		if err != nil {
+			log.WarnContext(ctx, "некорректные настройки участника", "user_id", member.ID, "error", err)
+			return domain.User{}, err
+		}
+		if err := users.CreateUser(ctx, user); err != nil {
+			log.ErrorContext(ctx, "не удалось создать пользователя", "error", err, "user_id", member.ID)
+			return domain.User{}, err
+		}
+		if err := audit.Record(ctx, domain.AuditUserCreated, domain.AuditEntityUser, user.ID, nil, user); err != nil {
+			return domain.User{}, err
+		}
+		log.InfoContext(ctx, "создали нового пользователя", "user_id", user.ID, "team_name", teamName)
+		return user, nil
+	case err != nil:
+		log.ErrorContext(ctx, "не удалось получить пользователя", "error", err, "user_id", member.ID)
+		return domain.User{}, err
+	}
+
+	before := existing
+	existing.Name = member.Name
+	existing.TeamName = teamName
+	existing.IsActive = member.IsActive
+	user, err := applyMemberUpdate(existing, member.Settings)
+	if err != nil {
+		log.WarnContext(ctx, "некорректные настройки участника", "user_id", member.ID, "error", err)
+		return domain.User{}, err
+	}
+	if err := users.UpdateUser(ctx, user); err != nil {
+		log.ErrorContext(ctx, "не удалось обновить пользователя", "error", err, "user_id", member.ID)
+		return domain.User{}, err
+	}
+	if err := audit.Record(ctx, domain.AuditUserUpdated, domain.AuditEntityUser, user.ID, before, user); err != nil {
+		return domain.User{}, err
+	}
+	log.InfoContext(ctx, "обновили пользователя", "user_id", user.ID, "team_name", teamName)
+	return user, nil
+}
+
+// gainedReviewers появился ли в after активный участник с весом больше 0, которого не было в before.
+func gainedReviewers(before, after []domain.User) bool {
+	assignable := func(user domain.User) bool {
+		return user.IsActive && user.ReviewWeight > 0
+	}
+	for _, user := range after {
+		if !assignable(user) {
+			continue
+		}
+		if !slices.ContainsFunc(before, func(old domain.User) bool { return old.ID == user.ID && assignable(old) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMemberUpdate применяет и проверяет изменения настроек участника.
+func applyMemberUpdate(member domain.User, changes MemberUpdate) (domain.User, error) {
+	if changes.Level != "" {
+		if !domain.IsValidLevel(changes.Level) {
+			return domain.User{}, domain.ErrInvalidLevel
+		}
+		member.Level = changes.Level
+	}
+	if changes.Timezone != "" {
+		member.Timezone = changes.Timezone
+	}
+	if changes.WorkStartHour != nil {
+		member.WorkStartHour = *changes.WorkStartHour
+	}
+	if changes.WorkEndHour != nil {
+		member.WorkEndHour = *changes.WorkEndHour
+	}
+	if changes.ReviewWeight != nil {
+		if !domain.IsValidReviewWeight(*changes.ReviewWeight) {
+			return domain.User{}, domain.ErrInvalidReviewWeight
+		}
+		member.ReviewWeight = *changes.ReviewWeight
+	}
+	if err := domain.ValidateSchedule(member.Timezone, member.WorkStartHour, member.WorkEndHour); err != nil {
+		return domain.User{}, err
+	}
+	return member, nil
+}
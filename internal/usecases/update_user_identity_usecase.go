@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+type UpdateUserIdentityUseCase struct {
+	identities UserIdentityStorage
+	tx         Transactor
+	audit      Auditor
+	log        *slog.Logger
+}
+
+func NewUpdateUserIdentityUseCase(identityStorage UserIdentityStorage, tx Transactor, audit Auditor, log *slog.Logger) *UpdateUserIdentityUseCase {
+	return &UpdateUserIdentityUseCase{
+		identities: identityStorage,
+		tx:         tx,
+		audit:      audit,
+		log:        log,
+	}
+}
+
+// Update меняет логин учётной записи; пользователь и система не меняются.
+func (uc *UpdateUserIdentityUseCase) Update(ctx context.Context, id, handle string) (domain.UserIdentity, error) {
+	return inTransaction(ctx, uc.tx, func(ctx context.Context) (domain.UserIdentity, error) {
+		before, err := uc.identities.GetUserIdentity(ctx, id)
+		if err != nil {
+			uc.log.WarnContext(ctx, "учётная запись не найдена", "identity_id", id, "error", err)
+			return domain.UserIdentity{}, err
+		}
+
+		identity, err := domain.NewUserIdentity(before.ID, before.UserID, before.Provider, handle, before.CreatedAt)
+		if err != nil {
+			uc.log.WarnContext(ctx, "некорректная учётная запись", "identity_id", id, "provider", before.Provider)
+			return domain.UserIdentity{}, err
+		}
+
+		if err := uc.identities.UpdateUserIdentity(ctx, identity); err != nil {
+			uc.log.WarnContext(ctx, "не удалось обновить учётную запись", "identity_id", id, "error", err)
+			return domain.UserIdentity{}, err
+		}
+
+		if err := uc.audit.Record(ctx, domain.AuditIdentityUpdated, domain.AuditEntityUser, identity.UserID, before, identity); err != nil {
+			return domain.UserIdentity{}, err
+		}
+
+		uc.log.InfoContext(ctx, "учётная запись обновлена", "identity_id", id, "user_id", identity.UserID)
+		return identity, nil
+	})
+}
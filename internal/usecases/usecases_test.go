@@ -2,14 +2,25 @@ package usecases
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
 	"github.com/che1nov/Pr-reviewer-assignment-service/internal/dto"
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/random"
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/signature"
 )
 
 func TestCreateTeamUseCase_Create(t *testing.T) {
@@ -23,11 +34,22 @@ func TestCreateTeamUseCase_Create(t *testing.T) {
 	errUpdateUser := errors.New("update user failure")
 	errCreateTeam := errors.New("create team failure")
 
+	legacySenior := func() domain.User {
+		user := domain.NewUser("u1", "Legacy", "legacy", false)
+		user.Level = domain.LevelSenior
+		user.Timezone = "Europe/Moscow"
+		user.WorkStartHour, user.WorkEndHour = 10, 19
+		user.ReviewWeight = 0.5
+		user.DigestEnabled = true
+		return user
+	}
+
 	tests := []struct {
 		name          string
 		initialTeams  []domain.Team
 		initialUsers  []domain.User
 		input         domain.Team
+		settings      map[string]MemberUpdate
 		wantErr       error
 		configure     func(teamStorage *fakeTeamStorage, userStorage *fakeUserStorage)
 		verifySuccess func(t *testing.T, team domain.Team, users *fakeUserStorage)
@@ -59,6 +81,46 @@ func TestCreateTeamUseCase_Create(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:         "existing member keeps settings missing from request",
+			initialUsers: []domain.User{legacySenior()},
+			input: domain.Team{
+				Name:  "backend",
+				Users: []domain.User{domain.NewUser("u1", "Alice", "", true)},
+			},
+			settings: map[string]MemberUpdate{"u1": {WorkEndHour: intPtr(20)}},
+			verifySuccess: func(t *testing.T, _ domain.Team, users *fakeUserStorage) {
+				t.Helper()
+				user := users.users["u1"]
+				if user.TeamName != "backend" || user.Name != "Alice" || !user.IsActive {
+					t.Fatalf("expected member moved and renamed, got %#v", user)
+				}
+				if !user.IsSenior() || user.Timezone != "Europe/Moscow" || user.WorkStartHour != 10 ||
+					user.WorkEndHour != 20 || user.ReviewWeight != 0.5 || !user.DigestEnabled {
+					t.Fatalf("expected settings kept except work_end_hour, got %#v", user)
+				}
+			},
+		},
+		{
+			name:  "new member gets defaults and provided settings",
+			input: domain.Team{Name: "backend", Users: []domain.User{domain.NewUser("u1", "Alice", "", true)}},
+			settings: map[string]MemberUpdate{
+				"u1": {Level: domain.LevelSenior, ReviewWeight: float64Ptr(2)},
+			},
+			verifySuccess: func(t *testing.T, _ domain.Team, users *fakeUserStorage) {
+				t.Helper()
+				user := users.users["u1"]
+				if !user.IsSenior() || user.ReviewWeight != 2 || user.Timezone != "UTC" || user.WorkEndHour != 24 {
+					t.Fatalf("unexpected new member %#v", user)
+				}
+			},
+		},
+		{
+			name:     "invalid member settings",
+			input:    domain.Team{Name: "backend", Users: []domain.User{domain.NewUser("u1", "Alice", "", true)}},
+			settings: map[string]MemberUpdate{"u1": {ReviewWeight: float64Ptr(-1)}},
+			wantErr:  domain.ErrInvalidReviewWeight,
+		},
 		{
 			name:         "team already exists",
 			initialTeams: []domain.Team{domain.NewTeam("backend", nil)},
@@ -126,13 +188,13 @@ func TestCreateTeamUseCase_Create(t *testing.T) {
 
 			userStorage := newFakeUserStorage(tt.initialUsers...)
 			teamStorage := newFakeTeamStorage(tt.initialTeams...)
-			uc := NewCreateTeamUseCase(teamStorage, userStorage, testLogger())
+			uc := NewCreateTeamUseCase(teamStorage, userStorage, &fakeTransactor{}, &fakeAuditor{}, testLogger())
 
 			if tt.configure != nil {
 				tt.configure(teamStorage, userStorage)
 			}
 
-			result, err := uc.Create(ctx, tt.input)
+			result, err := uc.Create(ctx, tt.input, tt.settings)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
@@ -144,6 +206,206 @@ func TestCreateTeamUseCase_Create(t *testing.T) {
 	}
 }
 
+func TestUpdateTeamUseCase_Update(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	errUpdateTeam := errors.New("update team failure")
+
+	baseTeam := func() domain.Team {
+		return domain.NewTeam("backend", []domain.User{
+			domain.NewUser("u1", "Alice", "backend", true),
+			domain.NewUser("u2", "Bob", "backend", true),
+		})
+	}
+
+	tests := []struct {
+		name      string
+		update    TeamUpdate
+		configure func(teams *fakeTeamStorage)
+		teamName  string
+		wantErr   error
+		wantTopUp bool
+		verify    func(t *testing.T, team domain.Team, teams *fakeTeamStorage, users *fakeUserStorage)
+	}{
+		{
+			name:     "enables senior policy and updates level",
+			teamName: "backend",
+			update: TeamUpdate{
+				RequireSeniorReviewer: boolPtr(true),
+				Members:               map[string]MemberUpdate{"u2": {Level: domain.LevelSenior}},
+			},
+			verify: func(t *testing.T, team domain.Team, teams *fakeTeamStorage, users *fakeUserStorage) {
+				t.Helper()
+				if !team.RequireSeniorReviewer || !teams.teams["backend"].RequireSeniorReviewer {
+					t.Fatalf("expected senior policy enabled")
+				}
+				if !users.users["u2"].IsSenior() {
+					t.Fatalf("expected u2 senior, got %q", users.users["u2"].Level)
+				}
+				if users.users["u1"].Level != domain.LevelMiddle {
+					t.Fatalf("expected u1 untouched, got %q", users.users["u1"].Level)
+				}
+			},
+		},
+		{
+			name:     "unknown level",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"u1": {Level: "guru"}}},
+			wantErr:  domain.ErrInvalidLevel,
+		},
+		{
+			name:     "sets review weight",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"u1": {ReviewWeight: float64Ptr(0.5)}}},
+			verify: func(t *testing.T, _ domain.Team, _ *fakeTeamStorage, users *fakeUserStorage) {
+				t.Helper()
+				if got := users.users["u1"].ReviewWeight; got != 0.5 {
+					t.Fatalf("expected weight 0.5, got %v", got)
+				}
+			},
+		},
+		{
+			name:     "negative review weight",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"u1": {ReviewWeight: float64Ptr(-1)}}},
+			wantErr:  domain.ErrInvalidReviewWeight,
+		},
+		{
+			name:     "sets timezone and working hours",
+			teamName: "backend",
+			update: TeamUpdate{Members: map[string]MemberUpdate{
+				"u1": {Timezone: "Asia/Vladivostok", WorkStartHour: intPtr(10), WorkEndHour: intPtr(19)},
+			}},
+			verify: func(t *testing.T, team domain.Team, teams *fakeTeamStorage, users *fakeUserStorage) {
+				t.Helper()
+				user := users.users["u1"]
+				if user.Timezone != "Asia/Vladivostok" || user.WorkStartHour != 10 || user.WorkEndHour != 19 {
+					t.Fatalf("unexpected schedule %#v", user)
+				}
+			},
+		},
+		{
+			name:     "unknown timezone",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"u1": {Timezone: "Mars/Olympus"}}},
+			wantErr:  domain.ErrInvalidSchedule,
+		},
+		{
+			name:     "empty working hours",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"u1": {WorkStartHour: intPtr(9), WorkEndHour: intPtr(9)}}},
+			wantErr:  domain.ErrInvalidSchedule,
+		},
+		{
+			name:     "adds new member and tops up",
+			teamName: "backend",
+			update: TeamUpdate{AddMembers: []NewMember{
+				{ID: "u3", Name: "Carol", IsActive: true, Settings: MemberUpdate{Level: domain.LevelSenior}},
+			}},
+			wantTopUp: true,
+			verify: func(t *testing.T, team domain.Team, _ *fakeTeamStorage, users *fakeUserStorage) {
+				t.Helper()
+				user := users.users["u3"]
+				if user.TeamName != "backend" || !user.IsSenior() || user.ReviewWeight != 1 {
+					t.Fatalf("unexpected new member %#v", user)
+				}
+				if len(team.Users) != 3 {
+					t.Fatalf("expected 3 members, got %v", team.Users)
+				}
+			},
+		},
+		{
+			name:     "moves existing user keeping settings",
+			teamName: "backend",
+			update: TeamUpdate{AddMembers: []NewMember{
+				{ID: "stranger", Name: "Stranger", IsActive: true, Settings: MemberUpdate{WorkEndHour: intPtr(20)}},
+			}},
+			wantTopUp: true,
+			verify: func(t *testing.T, _ domain.Team, _ *fakeTeamStorage, users *fakeUserStorage) {
+				t.Helper()
+				user := users.users["stranger"]
+				if user.TeamName != "backend" || !user.IsSenior() || user.Timezone != "Europe/Moscow" ||
+					user.WorkStartHour != 10 || user.WorkEndHour != 20 || user.ReviewWeight != 0.5 {
+					t.Fatalf("expected settings kept except work_end_hour, got %#v", user)
+				}
+			},
+		},
+		{
+			name:     "inactive new member does not top up",
+			teamName: "backend",
+			update:   TeamUpdate{AddMembers: []NewMember{{ID: "u3", Name: "Carol"}}},
+		},
+		{
+			name:      "raising weight from zero tops up",
+			teamName:  "backend",
+			update:    TeamUpdate{Members: map[string]MemberUpdate{"u2": {ReviewWeight: float64Ptr(1)}}},
+			wantTopUp: true,
+		},
+		{
+			name:     "invalid settings of new member",
+			teamName: "backend",
+			update:   TeamUpdate{AddMembers: []NewMember{{ID: "u3", Name: "Carol", Settings: MemberUpdate{Level: "guru"}}}},
+			wantErr:  domain.ErrInvalidLevel,
+		},
+		{
+			name:     "member of another team",
+			teamName: "backend",
+			update:   TeamUpdate{Members: map[string]MemberUpdate{"stranger": {Level: domain.LevelSenior}}},
+			wantErr:  domain.ErrUserNotFound,
+		},
+		{
+			name:     "team not found",
+			teamName: "unknown",
+			update:   TeamUpdate{RequireSeniorReviewer: boolPtr(true)},
+			wantErr:  domain.ErrTeamNotFound,
+		},
+		{
+			name:     "update team failure",
+			teamName: "backend",
+			update:   TeamUpdate{RequireSeniorReviewer: boolPtr(true)},
+			configure: func(teams *fakeTeamStorage) {
+				teams.updateErr = errUpdateTeam
+			},
+			wantErr: errUpdateTeam,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			team := baseTeam()
+			team.Users[1].ReviewWeight = 0
+			stranger := domain.NewUser("stranger", "Stranger", "frontend", false)
+			stranger.Level = domain.LevelSenior
+			stranger.Timezone = "Europe/Moscow"
+			stranger.WorkStartHour, stranger.WorkEndHour = 10, 19
+			stranger.ReviewWeight = 0.5
+			teamStorage := newFakeTeamStorage(team)
+			userStorage := newFakeUserStorage(append(team.Users, stranger)...)
+			if tt.configure != nil {
+				tt.configure(teamStorage)
+			}
+
+			topUp := &fakeTopUp{}
+			uc := NewUpdateTeamUseCase(teamStorage, userStorage, topUp, &fakeTransactor{}, &fakeAuditor{}, testLogger())
+			result, err := uc.Update(ctx, tt.teamName, tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got := len(topUp.teams) > 0; got != tt.wantTopUp {
+				t.Fatalf("expected top up %v, got %v", tt.wantTopUp, topUp.teams)
+			}
+			if tt.wantErr == nil && tt.verify != nil {
+				tt.verify(t, result, teamStorage, userStorage)
+			}
+		})
+	}
+}
+
 func TestGetTeamUseCase_Get(t *testing.T) {
 	t.Parallel()
 
@@ -241,6 +503,7 @@ func TestCreatePullRequestUseCase_Create(t *testing.T) {
 		users      []domain.User
 		team       domain.Team
 		initialPRs []domain.PullRequest
+		required   []string
 		wantErr    error
 		verify     func(t *testing.T, pr domain.PullRequest, storage *fakePullRequestStorage)
 	}{
@@ -348,6 +611,163 @@ func TestCreatePullRequestUseCase_Create(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "required reviewer placed first and rest filled randomly",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("lead", "Eve", "backend", true),
+			},
+			team: domain.NewTeam("backend", []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("lead", "Eve", "backend", true),
+			}),
+			required: []string{"lead"},
+			verify: func(t *testing.T, pr domain.PullRequest, storage *fakePullRequestStorage) {
+				t.Helper()
+				if len(pr.Reviewers) != 2 {
+					t.Fatalf("expected two reviewers, got %v", pr.Reviewers)
+				}
+				if pr.Reviewers[0] != "lead" {
+					t.Fatalf("expected required reviewer first, got %v", pr.Reviewers)
+				}
+				if pr.Reviewers[1] == "lead" || pr.Reviewers[1] == "author" {
+					t.Fatalf("unexpected second reviewer %q", pr.Reviewers[1])
+				}
+				if !pr.IsRequiredReviewer("lead") || pr.IsRequiredReviewer(pr.Reviewers[1]) {
+					t.Fatalf("expected only lead required, got %v", pr.RequiredReviewers)
+				}
+			},
+		},
+		{
+			name: "required reviewers fill all slots",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("r3", "Dave", "backend", true),
+			},
+			team: domain.NewTeam("backend", []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("r3", "Dave", "backend", true),
+			}),
+			required: []string{"r3", "r2"},
+			verify: func(t *testing.T, pr domain.PullRequest, storage *fakePullRequestStorage) {
+				t.Helper()
+				if len(pr.Reviewers) != 2 || pr.Reviewers[0] != "r3" || pr.Reviewers[1] != "r2" {
+					t.Fatalf("expected reviewers [r3 r2], got %v", pr.Reviewers)
+				}
+			},
+		},
+		{
+			name:     "required reviewer is author",
+			users:    []domain.User{baseAuthor},
+			team:     baseTeam,
+			required: []string{"author"},
+			wantErr:  domain.ErrReviewerIsAuthor,
+		},
+		{
+			name: "required reviewer inactive",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", false),
+			},
+			team: domain.NewTeam("backend", []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", false),
+			}),
+			required: []string{"r1"},
+			wantErr:  domain.ErrReviewerInactive,
+		},
+		{
+			name:     "required reviewer from another team",
+			users:    []domain.User{baseAuthor, domain.NewUser("ext", "Mallory", "frontend", true)},
+			team:     baseTeam,
+			required: []string{"ext"},
+			wantErr:  domain.ErrReviewerNotInTeam,
+		},
+		{
+			name: "too many required reviewers",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("r3", "Dave", "backend", true),
+			},
+			team: domain.NewTeam("backend", []domain.User{
+				baseAuthor,
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Charlie", "backend", true),
+				domain.NewUser("r3", "Dave", "backend", true),
+			}),
+			required: []string{"r1", "r2", "r3"},
+			wantErr:  domain.ErrReviewerLimitReached,
+		},
+		{
+			name: "senior policy adds senior reviewer",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("m1", "Bob", "backend", true),
+				domain.NewUser("m2", "Charlie", "backend", true),
+				domain.NewUser("m3", "Dave", "backend", true),
+				seniorUser("s1", "Eve", "backend"),
+			},
+			team: seniorPolicyTeam("backend",
+				baseAuthor,
+				seniorUser("s1", "Eve", "backend"),
+				domain.NewUser("m1", "Bob", "backend", true),
+				domain.NewUser("m2", "Charlie", "backend", true),
+				domain.NewUser("m3", "Dave", "backend", true),
+			),
+			verify: func(t *testing.T, pr domain.PullRequest, storage *fakePullRequestStorage) {
+				t.Helper()
+				if len(pr.Reviewers) != 2 {
+					t.Fatalf("expected two reviewers, got %v", pr.Reviewers)
+				}
+				if !contains(pr.Reviewers, "s1") {
+					t.Fatalf("expected senior reviewer assigned, got %v", pr.Reviewers)
+				}
+			},
+		},
+		{
+			name: "senior policy satisfied by required reviewer",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("m1", "Bob", "backend", true),
+				seniorUser("s1", "Eve", "backend"),
+			},
+			team: seniorPolicyTeam("backend",
+				baseAuthor,
+				domain.NewUser("m1", "Bob", "backend", true),
+				seniorUser("s1", "Eve", "backend"),
+			),
+			required: []string{"s1"},
+			verify: func(t *testing.T, pr domain.PullRequest, storage *fakePullRequestStorage) {
+				t.Helper()
+				if len(pr.Reviewers) != 2 || pr.Reviewers[0] != "s1" || pr.Reviewers[1] != "m1" {
+					t.Fatalf("expected reviewers [s1 m1], got %v", pr.Reviewers)
+				}
+			},
+		},
+		{
+			name: "senior policy without senior candidates",
+			users: []domain.User{
+				baseAuthor,
+				domain.NewUser("m1", "Bob", "backend", true),
+				domain.NewUser("m2", "Charlie", "backend", true),
+			},
+			team: seniorPolicyTeam("backend",
+				baseAuthor,
+				domain.NewUser("m1", "Bob", "backend", true),
+				domain.NewUser("m2", "Charlie", "backend", true),
+			),
+			wantErr: domain.ErrNoSeniorReviewer,
+		},
 	}
 
 	for _, tt := range tests {
@@ -362,8 +782,8 @@ func TestCreatePullRequestUseCase_Create(t *testing.T) {
 				teamStorage = newFakeTeamStorage(tt.team)
 			}
 
-			uc := NewCreatePullRequestUseCase(prStorage, teamStorage, userStorage, clock, random, testLogger())
-			pr, err := uc.Create(ctx, "pr-1", "Feature", "author")
+			uc := NewCreatePullRequestUseCase(prStorage, teamStorage, userStorage, clock, NewRandomReviewerSelector(random), &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+			pr, err := uc.Create(ctx, "pr-1", "Feature", "author", tt.required)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
@@ -385,12 +805,14 @@ func TestMergePullRequestUseCase_Merge(t *testing.T) {
 		initialPRs []domain.PullRequest
 		id         string
 		wantErr    error
+		wantAudit  []string
 		verify     func(t *testing.T, pr domain.PullRequest)
 	}{
 		{
 			name:       "merge success",
 			initialPRs: []domain.PullRequest{domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())},
 			id:         "pr-1",
+			wantAudit:  []string{domain.AuditPullRequestMerged},
 			verify: func(t *testing.T, pr domain.PullRequest) {
 				t.Helper()
 				if pr.Status != "MERGED" || pr.MergedAt == nil {
@@ -440,12 +862,33 @@ func TestMergePullRequestUseCase_Merge(t *testing.T) {
 			t.Parallel()
 
 			prStorage := newFakePullRequestStorage(tt.initialPRs...)
-			uc := NewMergePullRequestUseCase(prStorage, clock, testLogger())
+			tx := &fakeTransactor{}
+			audit := &fakeAuditor{}
+			history := &fakeHistoryRecorder{}
+			events := &fakeEventRecorder{}
+			uc := NewMergePullRequestUseCase(prStorage, clock, tx, audit, history, events, testLogger())
 
-			pr, err := uc.Merge(ctx, tt.id)
+			pr, err := uc.Merge(WithActor(ctx, "admin:alice"), tt.id)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
+			if tx.calls != 1 {
+				t.Fatalf("expected merge in one transaction, got %d", tx.calls)
+			}
+			if actions := audit.actions(); !slices.Equal(actions, tt.wantAudit) {
+				t.Fatalf("expected audit %v, got %v", tt.wantAudit, actions)
+			}
+			for _, entry := range audit.entries {
+				if entry.actor != "admin:alice" || entry.entityID != tt.id {
+					t.Fatalf("unexpected audit entry %+v", entry)
+				}
+			}
+			if len(history.entries) != len(tt.wantAudit) {
+				t.Fatalf("expected %d history entries, got %+v", len(tt.wantAudit), history.entries)
+			}
+			if len(events.events) != len(tt.wantAudit) {
+				t.Fatalf("expected %d events, got %+v", len(tt.wantAudit), events.events)
+			}
 			if tt.wantErr == nil && tt.verify != nil {
 				tt.verify(t, pr)
 			}
@@ -453,6 +896,123 @@ func TestMergePullRequestUseCase_Merge(t *testing.T) {
 	}
 }
 
+func TestPullRequestHistory_Timeline(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithActor(context.Background(), "admin")
+	users := []domain.User{
+		domain.NewUser("author", "Alice", "backend", true),
+		domain.NewUser("r1", "Bob", "backend", true),
+		domain.NewUser("r2", "Carol", "backend", true),
+		domain.NewUser("r3", "Dave", "backend", true),
+	}
+	prStorage := newFakePullRequestStorage()
+	teamStorage := newFakeTeamStorage(domain.NewTeam("backend", users))
+	userStorage := newFakeUserStorage(users...)
+	historyStorage := &fakeHistoryStorage{}
+	history := NewPullRequestHistory(historyStorage, fakeClock{now: time.Unix(100, 0)})
+	outboxStorage := &fakeOutboxStorage{}
+	outbox := NewOutbox(outboxStorage, fakeClock{now: time.Unix(100, 0)})
+	selector := NewRandomReviewerSelector(&fakeRandom{})
+
+	create := NewCreatePullRequestUseCase(prStorage, teamStorage, userStorage, fakeClock{now: time.Unix(100, 0)}, selector, &fakeTransactor{}, &fakeAuditor{}, history, outbox, testLogger())
+	reassign := NewReassignReviewerUseCase(prStorage, teamStorage, userStorage, selector, &fakeTransactor{}, &fakeAuditor{}, history, outbox, testLogger())
+	merge := NewMergePullRequestUseCase(prStorage, fakeClock{now: time.Unix(200, 0)}, &fakeTransactor{}, &fakeAuditor{}, history, outbox, testLogger())
+	get := NewGetPullRequestHistoryUseCase(prStorage, historyStorage, testLogger())
+
+	pr, err := create.Create(ctx, "pr-1", "Feature", "author", []string{"r1"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(pr.Reviewers) != 2 {
+		t.Fatalf("expected 2 reviewers, got %v", pr.Reviewers)
+	}
+	autoReviewer := pr.Reviewers[1]
+	newReviewer := "r2"
+	if autoReviewer == newReviewer {
+		newReviewer = "r3"
+	}
+	if _, _, err := reassign.Reassign(ctx, "pr-1", autoReviewer, &newReviewer, false); err != nil {
+		t.Fatalf("reassign: %v", err)
+	}
+	if _, err := merge.Merge(ctx, "pr-1"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if _, err := merge.Merge(ctx, "pr-1"); err != nil {
+		t.Fatalf("repeated merge: %v", err)
+	}
+
+	entries, err := get.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	want := []domain.PullRequestHistoryEntry{
+		{Event: domain.PRHistoryCreated},
+		{Event: domain.PRHistoryReviewerAssigned, ReviewerID: "r1", Reason: domain.PRHistoryReasonRequired},
+		{Event: domain.PRHistoryReviewerAssigned, ReviewerID: autoReviewer, Reason: domain.PRHistoryReasonAuto},
+		{Event: domain.PRHistoryReviewerReplaced, ReviewerID: newReviewer, PreviousReviewerID: autoReviewer, Reason: domain.PRHistoryReasonManual},
+		{Event: domain.PRHistoryMerged},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), entries)
+	}
+	for i, entry := range entries {
+		got := domain.PullRequestHistoryEntry{Event: entry.Event, ReviewerID: entry.ReviewerID, PreviousReviewerID: entry.PreviousReviewerID, Reason: entry.Reason}
+		if got != want[i] {
+			t.Fatalf("entry %d: expected %+v, got %+v", i, want[i], got)
+		}
+		if entry.PullRequestID != "pr-1" || entry.Actor != "admin" || entry.CreatedAt.IsZero() {
+			t.Fatalf("entry %d: unexpected metadata %+v", i, entry)
+		}
+	}
+
+	if _, err := get.History(ctx, "unknown"); !errors.Is(err, domain.ErrPullRequestNotFound) {
+		t.Fatalf("expected ErrPullRequestNotFound, got %v", err)
+	}
+
+	wantEvents := []string{
+		domain.EventPullRequestCreated,
+		domain.EventReviewerAssigned,
+		domain.EventReviewerAssigned,
+		domain.EventReviewerReplaced,
+		domain.EventPullRequestMerged,
+	}
+	var gotEvents []string
+	for _, message := range outboxStorage.messages {
+		gotEvents = append(gotEvents, message.EventType)
+		if message.AggregateID != "pr-1" || message.Actor != "admin" {
+			t.Fatalf("unexpected outbox message %+v", message)
+		}
+	}
+	if !slices.Equal(gotEvents, wantEvents) {
+		t.Fatalf("expected events %v, got %v", wantEvents, gotEvents)
+	}
+	var replaced domain.ReviewerReplaced
+	if err := json.Unmarshal(outboxStorage.messages[3].Payload, &replaced); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if replaced.OldReviewerID != autoReviewer || replaced.NewReviewerID != newReviewer || replaced.TeamName != "backend" {
+		t.Fatalf("unexpected replaced payload %+v", replaced)
+	}
+}
+
+func TestDeactivationHistory(t *testing.T) {
+	t.Parallel()
+
+	entries := deactivationHistory("pr-1", []ReviewerReplacement{
+		{OldReviewerID: "u1", NewReviewerID: "u3"},
+		{OldReviewerID: "u2", Reason: ReasonNoActiveCandidates},
+	})
+
+	want := []domain.PullRequestHistoryEntry{
+		historyReplaced("pr-1", "u1", "u3", domain.PRHistoryReasonDeactivation),
+		historyRemoved("pr-1", "u2", domain.PRHistoryReasonDeactivation),
+	}
+	if !slices.Equal(entries, want) {
+		t.Fatalf("expected %+v, got %+v", want, entries)
+	}
+}
+
 func TestReassignReviewerUseCase_Reassign(t *testing.T) {
 	t.Parallel()
 
@@ -469,6 +1029,7 @@ func TestReassignReviewerUseCase_Reassign(t *testing.T) {
 		team       *fakeTeamStorage
 		users      *fakeUserStorage
 		desiredNew *string
+		override   bool
 		wantErr    error
 		verify     func(t *testing.T, pr domain.PullRequest, replacedBy string)
 	}{
@@ -610,15 +1171,128 @@ func TestReassignReviewerUseCase_Reassign(t *testing.T) {
 			users:   newFakeUserStorage(oldReviewer, domain.NewUser("candidate", "Charlie", "backend", true)),
 			wantErr: errUpdatePR,
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
+		{
+			name: "required reviewer cannot be replaced without override",
+			prStore: newFakePullRequestStorage(func() domain.PullRequest {
+				pr := domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())
+				_ = pr.AddRequiredReviewer("old")
+				return pr
+			}()),
+			team: newFakeTeamStorage(domain.NewTeam("backend", []domain.User{
+				author,
+				oldReviewer,
+				domain.NewUser("candidate", "Charlie", "backend", true),
+			})),
+			users:   newFakeUserStorage(oldReviewer, domain.NewUser("candidate", "Charlie", "backend", true)),
+			wantErr: domain.ErrReviewerRequired,
+		},
+		{
+			name: "required reviewer replaced with override",
+			prStore: newFakePullRequestStorage(func() domain.PullRequest {
+				pr := domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())
+				_ = pr.AddRequiredReviewer("old")
+				return pr
+			}()),
+			team: newFakeTeamStorage(domain.NewTeam("backend", []domain.User{
+				author,
+				oldReviewer,
+				domain.NewUser("candidate", "Charlie", "backend", true),
+			})),
+			users:    newFakeUserStorage(oldReviewer, domain.NewUser("candidate", "Charlie", "backend", true)),
+			override: true,
+			verify: func(t *testing.T, pr domain.PullRequest, replacedBy string) {
+				t.Helper()
+				if replacedBy != "candidate" {
+					t.Fatalf("expected candidate replacement, got %q", replacedBy)
+				}
+				if len(pr.RequiredReviewers) != 0 {
+					t.Fatalf("expected replacement not to inherit required flag, got %v", pr.RequiredReviewers)
+				}
+			},
+		},
+		{
+			name: "senior policy picks senior replacement",
+			prStore: newFakePullRequestStorage(func() domain.PullRequest {
+				pr := domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())
+				pr.AssignReviewers([]string{"old", "busy"})
+				return pr
+			}()),
+			team: newFakeTeamStorage(seniorPolicyTeam("backend",
+				author,
+				seniorUser("old", "Bob", "backend"),
+				domain.NewUser("busy", "Busy", "backend", true),
+				seniorUser("senior", "Eve", "backend"),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			)),
+			users: newFakeUserStorage(
+				seniorUser("old", "Bob", "backend"),
+				domain.NewUser("busy", "Busy", "backend", true),
+				seniorUser("senior", "Eve", "backend"),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			),
+			verify: func(t *testing.T, pr domain.PullRequest, replacedBy string) {
+				t.Helper()
+				if replacedBy != "senior" {
+					t.Fatalf("expected senior replacement, got %q", replacedBy)
+				}
+			},
+		},
+		{
+			name: "senior policy rejects desired non-senior",
+			prStore: newFakePullRequestStorage(func() domain.PullRequest {
+				pr := domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())
+				pr.AssignReviewers([]string{"old", "busy"})
+				return pr
+			}()),
+			team: newFakeTeamStorage(seniorPolicyTeam("backend",
+				author,
+				seniorUser("old", "Bob", "backend"),
+				domain.NewUser("busy", "Busy", "backend", true),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			)),
+			users: newFakeUserStorage(
+				seniorUser("old", "Bob", "backend"),
+				domain.NewUser("busy", "Busy", "backend", true),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			),
+			desiredNew: stringPtr("middle"),
+			wantErr:    domain.ErrNoSeniorReviewer,
+		},
+		{
+			name: "senior policy allows non-senior when another senior stays",
+			prStore: newFakePullRequestStorage(func() domain.PullRequest {
+				pr := domain.NewPullRequest("pr-1", "Feature", "author", "backend", time.Now())
+				pr.AssignReviewers([]string{"old", "lead"})
+				return pr
+			}()),
+			team: newFakeTeamStorage(seniorPolicyTeam("backend",
+				author,
+				oldReviewer,
+				seniorUser("lead", "Eve", "backend"),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			)),
+			users: newFakeUserStorage(
+				oldReviewer,
+				seniorUser("lead", "Eve", "backend"),
+				domain.NewUser("middle", "Charlie", "backend", true),
+			),
+			desiredNew: stringPtr("middle"),
+			verify: func(t *testing.T, pr domain.PullRequest, replacedBy string) {
+				t.Helper()
+				if replacedBy != "middle" {
+					t.Fatalf("expected middle replacement, got %q", replacedBy)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			uc := NewReassignReviewerUseCase(tt.prStore, tt.team, tt.users, &fakeRandom{}, testLogger())
-			pr, replacedBy, err := uc.Reassign(ctx, "pr-1", "old", tt.desiredNew)
+			uc := NewReassignReviewerUseCase(tt.prStore, tt.team, tt.users, NewRandomReviewerSelector(&fakeRandom{}), &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+			pr, replacedBy, err := uc.Reassign(ctx, "pr-1", "old", tt.desiredNew, tt.override)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
@@ -642,14 +1316,17 @@ func TestSetUserActiveUseCase_SetActive(t *testing.T) {
 		userID    string
 		active    bool
 		configure func(storage *fakeUserStorage)
+		topUpErr  error
 		wantErr   error
+		wantTopUp []string
 		verify    func(t *testing.T, storage *fakeUserStorage, result domain.User)
 	}{
 		{
-			name:   "activate user",
-			users:  []domain.User{domain.NewUser("u1", "Alice", "backend", false)},
-			userID: "u1",
-			active: true,
+			name:      "activate user",
+			users:     []domain.User{domain.NewUser("u1", "Alice", "backend", false)},
+			userID:    "u1",
+			active:    true,
+			wantTopUp: []string{"backend"},
 			verify: func(t *testing.T, storage *fakeUserStorage, result domain.User) {
 				t.Helper()
 				if !storage.users["u1"].IsActive {
@@ -660,6 +1337,26 @@ func TestSetUserActiveUseCase_SetActive(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "top up failure does not fail activation",
+			users:     []domain.User{domain.NewUser("u1", "Alice", "backend", false)},
+			userID:    "u1",
+			active:    true,
+			topUpErr:  errors.New("top up failure"),
+			wantTopUp: []string{"backend"},
+			verify: func(t *testing.T, storage *fakeUserStorage, _ domain.User) {
+				t.Helper()
+				if !storage.users["u1"].IsActive {
+					t.Fatalf("expected user active in storage")
+				}
+			},
+		},
+		{
+			name:   "already active user does not trigger top up",
+			users:  []domain.User{domain.NewUser("u1", "Alice", "backend", true)},
+			userID: "u1",
+			active: true,
+		},
 		{
 			name:   "deactivate user",
 			users:  []domain.User{domain.NewUser("u1", "Alice", "backend", true)},
@@ -703,12 +1400,16 @@ func TestSetUserActiveUseCase_SetActive(t *testing.T) {
 			if tt.configure != nil {
 				tt.configure(userStorage)
 			}
-			uc := NewSetUserActiveUseCase(userStorage, testLogger())
+			topUp := &fakeTopUp{err: tt.topUpErr}
+			uc := NewSetUserActiveUseCase(userStorage, topUp, &fakeTransactor{}, &fakeAuditor{}, &fakeEventRecorder{}, testLogger())
 
 			result, err := uc.SetActive(ctx, tt.userID, tt.active)
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected %v, got %v", tt.wantErr, err)
 			}
+			if len(topUp.teams) != len(tt.wantTopUp) {
+				t.Fatalf("expected top up for %v, got %v", tt.wantTopUp, topUp.teams)
+			}
 			if tt.wantErr == nil && tt.verify != nil {
 				tt.verify(t, userStorage, result)
 			}
@@ -716,6 +1417,135 @@ func TestSetUserActiveUseCase_SetActive(t *testing.T) {
 	}
 }
 
+func TestTopUpReviewersUseCase_TopUpTeam(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := time.Unix(1000, 0)
+
+	openPR := func(id string, reviewers ...string) domain.PullRequest {
+		pr := domain.NewPullRequest(id, "Feature", "author", "backend", base)
+		pr.AssignReviewers(reviewers)
+		return pr
+	}
+	mergedPR := func(id string) domain.PullRequest {
+		pr := openPR(id)
+		pr.MarkMerged(base)
+		return pr
+	}
+
+	members := []domain.User{
+		domain.NewUser("author", "Alice", "backend", true),
+		domain.NewUser("u1", "Bob", "backend", true),
+		domain.NewUser("u2", "Charlie", "backend", true),
+		domain.NewUser("inactive", "Dave", "backend", false),
+	}
+
+	errUpdate := errors.New("update failure")
+
+	tests := []struct {
+		name      string
+		team      domain.Team
+		prs       []domain.PullRequest
+		configure func(prs *fakePullRequestStorage)
+		teamName  string
+		wantErr   error
+		wantCount int
+		want      map[string][]string
+	}{
+		{
+			name:      "fills under-staffed open pull requests",
+			team:      domain.NewTeam("backend", members),
+			prs:       []domain.PullRequest{openPR("empty"), openPR("single", "u1"), openPR("full", "u1", "u2"), mergedPR("merged")},
+			teamName:  "backend",
+			wantCount: 2,
+			want: map[string][]string{
+				"empty":  {"u1", "u2"},
+				"single": {"u1", "u2"},
+				"full":   {"u1", "u2"},
+				"merged": {},
+			},
+		},
+		{
+			name:      "skips pull requests without candidates",
+			team:      domain.NewTeam("backend", members[:2]),
+			prs:       []domain.PullRequest{openPR("single", "u1")},
+			teamName:  "backend",
+			wantCount: 0,
+			want:      map[string][]string{"single": {"u1"}},
+		},
+		{
+			name:      "skips pull requests violating senior policy",
+			team:      seniorPolicyTeam("backend", members...),
+			prs:       []domain.PullRequest{openPR("empty")},
+			teamName:  "backend",
+			wantCount: 0,
+			want:      map[string][]string{"empty": {}},
+		},
+		{
+			name:     "team not found",
+			teamName: "missing",
+			team:     domain.NewTeam("backend", members),
+			wantErr:  domain.ErrTeamNotFound,
+		},
+		{
+			name:     "update error",
+			team:     domain.NewTeam("backend", members),
+			prs:      []domain.PullRequest{openPR("empty")},
+			teamName: "backend",
+			configure: func(prs *fakePullRequestStorage) {
+				prs.updateErr = errUpdate
+			},
+			wantErr: errUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prStorage := newFakePullRequestStorage(tt.prs...)
+			if tt.configure != nil {
+				tt.configure(prStorage)
+			}
+			uc := NewTopUpReviewersUseCase(
+				prStorage,
+				newFakeTeamStorage(tt.team),
+				newFakeUserStorage(tt.team.Users...),
+				NewRandomReviewerSelector(&fakeRandom{}),
+				&fakeTransactor{},
+				&fakeAuditor{},
+				&fakeHistoryRecorder{},
+				&fakeEventRecorder{},
+				testLogger(),
+			)
+
+			count, err := uc.TopUpTeam(ctx, tt.teamName)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if count != tt.wantCount {
+				t.Fatalf("expected %d topped up, got %d", tt.wantCount, count)
+			}
+			for prID, want := range tt.want {
+				got := prStorage.prs[prID].Reviewers
+				if len(got) != len(want) {
+					t.Fatalf("pr %s: expected %v, got %v", prID, want, got)
+				}
+				for _, id := range want {
+					if !contains(got, id) {
+						t.Fatalf("pr %s: expected %v, got %v", prID, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestGetReviewerPullRequestsUseCase_ListByReviewer(t *testing.T) {
 	t.Parallel()
 
@@ -1031,6 +1861,7 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 		users    []domain.User
 		teams    []domain.Team
 		prs      []domain.PullRequest
+		opts     DeactivateOptions
 		wantErr  error
 		verify   func(t *testing.T, result DeactivateResult, userStorage *fakeUserStorage, prStorage *fakePullRequestStorage)
 	}{
@@ -1115,6 +1946,7 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 		{
 			name:     "reassigns PRs when replacement available",
 			teamName: "backend",
+			opts:     DeactivateOptions{UserIDs: []string{"u2"}},
 			users: []domain.User{
 				domain.NewUser("u1", "Alice", "backend", true),
 				domain.NewUser("u2", "Bob", "backend", true),
@@ -1145,7 +1977,96 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 				if len(pr.Reviewers) == 0 || pr.Reviewers[0] == "u2" {
 					t.Fatalf("expected u2 to be replaced, got reviewers %v", pr.Reviewers)
 				}
+				if result.DeactivatedCount != 1 || !userStorage.users["u3"].IsActive || !userStorage.users["u4"].IsActive {
+					t.Fatalf("expected only u2 deactivated, got %+v", result)
+				}
+			},
+		},
+		{
+			name:     "does not replace with users deactivated in the same operation",
+			teamName: "backend",
+			users: []domain.User{
+				domain.NewUser("u1", "Alice", "backend", true),
+				domain.NewUser("u2", "Bob", "backend", true),
+				domain.NewUser("u3", "Charlie", "backend", true),
+			},
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{
+					domain.NewUser("u1", "Alice", "backend", true),
+					domain.NewUser("u2", "Bob", "backend", true),
+					domain.NewUser("u3", "Charlie", "backend", true),
+				}),
+			},
+			prs: []domain.PullRequest{
+				func() domain.PullRequest {
+					pr := domain.NewPullRequest("pr-1", "Fix", "u1", "backend", time.Now())
+					pr.AssignReviewers([]string{"u2"})
+					return pr
+				}(),
+			},
+			verify: func(t *testing.T, result DeactivateResult, userStorage *fakeUserStorage, prStorage *fakePullRequestStorage) {
+				t.Helper()
+				pr, _ := prStorage.GetPullRequest(context.Background(), "pr-1")
+				if len(pr.Reviewers) != 0 {
+					t.Fatalf("expected slot left empty, got %v", pr.Reviewers)
+				}
+			},
+		},
+		{
+			name:     "deactivates all except listed users",
+			teamName: "backend",
+			opts:     DeactivateOptions{ExceptUserIDs: []string{"u1", "u3"}},
+			users: []domain.User{
+				domain.NewUser("u1", "Alice", "backend", true),
+				domain.NewUser("u2", "Bob", "backend", true),
+				domain.NewUser("u3", "Charlie", "backend", true),
+			},
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{
+					domain.NewUser("u1", "Alice", "backend", true),
+					domain.NewUser("u2", "Bob", "backend", true),
+					domain.NewUser("u3", "Charlie", "backend", true),
+				}),
+			},
+			prs: []domain.PullRequest{
+				func() domain.PullRequest {
+					pr := domain.NewPullRequest("pr-1", "Fix", "u1", "backend", time.Now())
+					pr.AssignReviewers([]string{"u2"})
+					return pr
+				}(),
+			},
+			verify: func(t *testing.T, result DeactivateResult, userStorage *fakeUserStorage, prStorage *fakePullRequestStorage) {
+				t.Helper()
+				if result.DeactivatedCount != 1 || userStorage.users["u2"].IsActive {
+					t.Fatalf("expected only u2 deactivated, got %+v", result)
+				}
+				if !userStorage.users["u1"].IsActive || !userStorage.users["u3"].IsActive {
+					t.Fatalf("expected u1 and u3 to stay active")
+				}
+				pr, _ := prStorage.GetPullRequest(context.Background(), "pr-1")
+				if len(pr.Reviewers) != 1 || pr.Reviewers[0] != "u3" {
+					t.Fatalf("expected u2 replaced by u3, got %v", pr.Reviewers)
+				}
+			},
+		},
+		{
+			name:     "both user filters",
+			teamName: "backend",
+			opts:     DeactivateOptions{UserIDs: []string{"u1"}, ExceptUserIDs: []string{"u2"}},
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{domain.NewUser("u1", "Alice", "backend", true)}),
 			},
+			wantErr: domain.ErrInvalidUserFilter,
+		},
+		{
+			name:     "filtered user not in team",
+			teamName: "backend",
+			opts:     DeactivateOptions{UserIDs: []string{"stranger"}},
+			users:    []domain.User{domain.NewUser("u1", "Alice", "backend", true)},
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{domain.NewUser("u1", "Alice", "backend", true)}),
+			},
+			wantErr: domain.ErrUserNotFound,
 		},
 		{
 			name:     "removes reviewer when no replacement available",
@@ -1173,6 +2094,16 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 				if len(pr.Reviewers) != 0 {
 					t.Fatalf("expected reviewer removed, got %v", pr.Reviewers)
 				}
+				if len(result.PullRequests) != 1 {
+					t.Fatalf("expected 1 PR in report, got %+v", result.PullRequests)
+				}
+				report := result.PullRequests[0]
+				if len(report.ReviewersBefore) != 1 || report.ReviewersBefore[0] != "u2" || len(report.ReviewersAfter) != 0 {
+					t.Fatalf("expected before [u2] and empty after, got %+v", report)
+				}
+				if report.Replacements[0].Reason != ReasonNoActiveCandidates {
+					t.Fatalf("expected reason %q, got %+v", ReasonNoActiveCandidates, report.Replacements[0])
+				}
 			},
 		},
 		{
@@ -1204,13 +2135,97 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 			},
 		},
 		{
-			name:     "team not found",
-			teamName: "nonexistent",
-			wantErr:  domain.ErrTeamNotFound,
+			name:     "dry run returns plan without writing",
+			teamName: "backend",
+			opts:     DeactivateOptions{DryRun: true},
+			users: []domain.User{
+				domain.NewUser("u1", "Alice", "backend", true),
+				domain.NewUser("u2", "Bob", "backend", true),
+				domain.NewUser("u3", "Charlie", "frontend", true),
+			},
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{
+					domain.NewUser("u1", "Alice", "backend", true),
+					domain.NewUser("u2", "Bob", "backend", true),
+				}),
+				domain.NewTeam("frontend", []domain.User{
+					domain.NewUser("u3", "Charlie", "frontend", true),
+					domain.NewUser("u4", "Dave", "frontend", true),
+				}),
+			},
+			prs: []domain.PullRequest{
+				func() domain.PullRequest {
+					pr := domain.NewPullRequest("pr-1", "Fix", "u3", "frontend", time.Now())
+					pr.AssignReviewers([]string{"u1"})
+					return pr
+				}(),
+				func() domain.PullRequest {
+					pr := domain.NewPullRequest("pr-2", "Feature", "u1", "backend", time.Now())
+					pr.AssignReviewers([]string{"u2"})
+					return pr
+				}(),
+			},
+			verify: func(t *testing.T, result DeactivateResult, userStorage *fakeUserStorage, prStorage *fakePullRequestStorage) {
+				t.Helper()
+				if !result.DryRun || !result.ReplacementsIndicative || result.DeactivatedCount != 2 || result.ReassignedPRCount != 2 {
+					t.Fatalf("unexpected plan counters: %+v", result)
+				}
+				sort.Strings(result.DeactivatedUserIDs)
+				if len(result.DeactivatedUserIDs) != 2 || result.DeactivatedUserIDs[0] != "u1" || result.DeactivatedUserIDs[1] != "u2" {
+					t.Fatalf("expected u1 and u2 planned, got %v", result.DeactivatedUserIDs)
+				}
+				planned := make(map[string]ReviewerReplacement, len(result.PullRequests))
+				for _, report := range result.PullRequests {
+					planned[report.PullRequestID] = report.Replacements[0]
+				}
+				if got := planned["pr-1"]; got.OldReviewerID != "u1" || got.NewReviewerID != "u4" {
+					t.Fatalf("expected pr-1 u1 -> u4, got %+v", got)
+				}
+				if got := planned["pr-2"]; got.OldReviewerID != "u2" || got.NewReviewerID != "" {
+					t.Fatalf("expected pr-2 u2 without replacement, got %+v", got)
+				}
+				for _, userID := range []string{"u1", "u2"} {
+					if !userStorage.users[userID].IsActive {
+						t.Fatalf("expected user %s untouched in dry run", userID)
+					}
+				}
+				if pr := prStorage.prs["pr-1"]; len(pr.Reviewers) != 1 || pr.Reviewers[0] != "u1" {
+					t.Fatalf("expected pr-1 untouched in dry run, got %v", pr.Reviewers)
+				}
+			},
 		},
-	}
-
-	for _, tt := range tests {
+		{
+			name:     "senior policy without senior replacement keeps PRs untouched",
+			teamName: "platform",
+			users: []domain.User{
+				domain.NewUser("u1", "Alice", "backend", true),
+				seniorUser("s1", "Bob", "platform"),
+				domain.NewUser("u3", "Charlie", "backend", true),
+			},
+			teams: []domain.Team{
+				seniorPolicyTeam("backend",
+					domain.NewUser("u1", "Alice", "backend", true),
+					domain.NewUser("u3", "Charlie", "backend", true),
+				),
+				domain.NewTeam("platform", []domain.User{seniorUser("s1", "Bob", "platform")}),
+			},
+			prs: []domain.PullRequest{
+				func() domain.PullRequest {
+					pr := domain.NewPullRequest("pr-1", "Fix", "u1", "backend", time.Now())
+					pr.AssignReviewers([]string{"s1"})
+					return pr
+				}(),
+			},
+			wantErr: domain.ErrNoSeniorReviewer,
+		},
+		{
+			name:     "team not found",
+			teamName: "nonexistent",
+			wantErr:  domain.ErrTeamNotFound,
+		},
+	}
+
+	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
@@ -1219,232 +2234,4078 @@ func TestDeactivateTeamUsersUseCase_DeactivateTeamUsers(t *testing.T) {
 			teamStorage := newFakeTeamStorage(tt.teams...)
 			prStorage := newFakePullRequestStorage(tt.prs...)
 
-			uc := NewDeactivateTeamUsersUseCase(userStorage, teamStorage, prStorage, &fakeRandom{}, testLogger())
+			operationStorage := newFakeOperationStorage()
 
-			result, err := uc.DeactivateTeamUsers(ctx, tt.teamName)
+			uc := NewDeactivateTeamUsersUseCase(
+				userStorage,
+				teamStorage,
+				prStorage,
+				operationStorage,
+				NewRandomReviewerSelector(&fakeRandom{}),
+				fakeClock{now: time.Unix(1000, 0)},
+				&fakeIDGenerator{},
+				&fakeTransactor{},
+				&fakeAuditor{},
+				&fakeHistoryRecorder{},
+				&fakeEventRecorder{},
+				testLogger(),
+			)
+
+			result, err := uc.DeactivateTeamUsers(ctx, tt.teamName, tt.opts)
 
 			if !errors.Is(err, tt.wantErr) {
 				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
 			}
+			if tt.wantErr != nil {
+				for _, pr := range tt.prs {
+					stored, _ := prStorage.GetPullRequest(ctx, pr.ID)
+					if len(stored.Reviewers) != len(pr.Reviewers) {
+						t.Fatalf("expected PR %s untouched on error, got %v", pr.ID, stored.Reviewers)
+					}
+				}
+			}
 
 			if tt.wantErr == nil && tt.verify != nil {
 				tt.verify(t, result, userStorage, prStorage)
 			}
+
+			operation, recorded := operationStorage.operations[result.OperationID]
+			switch {
+			case tt.wantErr != nil || tt.opts.DryRun || result.DeactivatedCount == 0:
+				if len(operationStorage.operations) != 0 {
+					t.Fatalf("expected no operation recorded, got %v", operationStorage.operations)
+				}
+			case !recorded:
+				t.Fatalf("expected operation %q recorded", result.OperationID)
+			case len(operation.UserIDs) != result.DeactivatedCount || len(operation.PullRequests) != result.ReassignedPRCount:
+				t.Fatalf("operation does not match result: %+v vs %+v", operation, result)
+			}
 		})
 	}
 }
 
-// test helpers
-
-func testLogger() *slog.Logger {
-	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
-}
+func TestRevertOperationUseCase_Revert(t *testing.T) {
+	t.Parallel()
 
-type fakeUserStorage struct {
-	users       map[string]domain.User
-	createErr   error
-	createErrID string
-	getErr      error
-	getErrID    string
-	updateErr   error
-	updateErrID string
-	listErr     error
-}
+	ctx := context.Background()
+	base := time.Unix(1000, 0)
 
-func newFakeUserStorage(users ...domain.User) *fakeUserStorage {
-	m := make(map[string]domain.User, len(users))
-	for _, u := range users {
-		m[u.ID] = u
+	reviewedPR := func(id string, reviewers ...string) domain.PullRequest {
+		pr := domain.NewPullRequest(id, "Feature", "author", "backend", base)
+		pr.AssignReviewers(reviewers)
+		return pr
 	}
-	return &fakeUserStorage{users: m}
-}
 
-func (f *fakeUserStorage) CreateUser(_ context.Context, user domain.User) error {
-	if f.createErr != nil && (f.createErrID == "" || f.createErrID == user.ID) {
-		return f.createErr
+	operation := func() domain.Operation {
+		op := domain.NewOperation("op-1", domain.OperationTeamDeactivation, "backend", base)
+		op.UserIDs = []string{"u1", "u2"}
+		op.PullRequests = []domain.PullRequestSnapshot{
+			{PullRequestID: "restored", ReviewersBefore: []string{"u1", "u3"}, RequiredBefore: []string{"u1"}, ReviewersAfter: []string{"u3"}},
+			{PullRequestID: "changed", ReviewersBefore: []string{"u2"}, ReviewersAfter: []string{"u3"}},
+			{PullRequestID: "merged", ReviewersBefore: []string{"u2"}, ReviewersAfter: []string{}},
+			{PullRequestID: "deleted", ReviewersBefore: []string{"u2"}, ReviewersAfter: []string{}},
+		}
+		return op
 	}
-	f.users[user.ID] = user
-	return nil
-}
 
-func (f *fakeUserStorage) ListUsers(_ context.Context) ([]domain.User, error) {
-	if f.listErr != nil {
-		return nil, f.listErr
-	}
-	result := make([]domain.User, 0, len(f.users))
-	for _, user := range f.users {
-		result = append(result, user)
+	prs := func() []domain.PullRequest {
+		merged := reviewedPR("merged")
+		merged.MarkMerged(base)
+		return []domain.PullRequest{reviewedPR("restored", "u3"), reviewedPR("changed", "u4"), merged}
 	}
-	return result, nil
-}
 
-func (f *fakeUserStorage) GetUser(_ context.Context, id string) (domain.User, error) {
-	if f.getErr != nil && (f.getErrID == "" || f.getErrID == id) {
-		return domain.User{}, f.getErr
-	}
-	user, ok := f.users[id]
-	if !ok {
-		return domain.User{}, domain.ErrUserNotFound
+	users := func() []domain.User {
+		return []domain.User{
+			domain.NewUser("u1", "Alice", "backend", false),
+			domain.NewUser("u2", "Bob", "backend", true),
+		}
 	}
-	return user, nil
-}
 
-func (f *fakeUserStorage) UpdateUser(_ context.Context, user domain.User) error {
-	if _, ok := f.users[user.ID]; !ok {
-		return domain.ErrUserNotFound
-	}
-	if f.updateErr != nil && (f.updateErrID == "" || f.updateErrID == user.ID) {
-		return f.updateErr
+	tests := []struct {
+		name      string
+		operation domain.Operation
+		reverted  bool
+		id        string
+		wantErr   error
+		verify    func(t *testing.T, result RevertResult, users *fakeUserStorage, prs *fakePullRequestStorage, operations *fakeOperationStorage)
+	}{
+		{
+			name:      "reactivates users and restores unchanged open PRs",
+			operation: operation(),
+			id:        "op-1",
+			verify: func(t *testing.T, result RevertResult, users *fakeUserStorage, prs *fakePullRequestStorage, operations *fakeOperationStorage) {
+				t.Helper()
+				if result.ReactivatedCount != 1 || !users.users["u1"].IsActive {
+					t.Fatalf("expected u1 reactivated, got %+v", result)
+				}
+				restored := prs.prs["restored"]
+				if len(restored.Reviewers) != 2 || !restored.HasReviewer("u1") || !restored.IsRequiredReviewer("u1") {
+					t.Fatalf("expected reviewers restored with required flag, got %+v", restored)
+				}
+				if got := prs.prs["changed"].Reviewers; len(got) != 1 || got[0] != "u4" {
+					t.Fatalf("expected changed PR untouched, got %v", got)
+				}
+				skipped := make(map[string]string, len(result.SkippedPRs))
+				for _, item := range result.SkippedPRs {
+					skipped[item.PullRequestID] = item.Reason
+				}
+				if skipped["changed"] != RevertSkipChanged || skipped["merged"] != RevertSkipNotOpen || skipped["deleted"] != RevertSkipNotFound {
+					t.Fatalf("unexpected skipped PRs: %v", skipped)
+				}
+				if operations.operations["op-1"].Status != domain.OperationStatusReverted {
+					t.Fatalf("expected operation marked reverted")
+				}
+			},
+		},
+		{
+			name:      "already reverted",
+			operation: operation(),
+			reverted:  true,
+			id:        "op-1",
+			wantErr:   domain.ErrOperationReverted,
+		},
+		{
+			name:      "operation not found",
+			operation: operation(),
+			id:        "missing",
+			wantErr:   domain.ErrOperationNotFound,
+		},
 	}
-	f.users[user.ID] = user
-	return nil
-}
-
-type fakeTeamStorage struct {
-	teams         map[string]domain.Team
-	getErr        error
-	getErrName    string
-	createErr     error
-	createErrName string
-}
 
-func newFakeTeamStorage(teams ...domain.Team) *fakeTeamStorage {
-	m := make(map[string]domain.Team, len(teams))
-	for _, team := range teams {
-		m[team.Name] = team
-	}
-	return &fakeTeamStorage{teams: m}
-}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
 
-func (f *fakeTeamStorage) CreateTeam(_ context.Context, team domain.Team) error {
-	if f.createErr != nil && (f.createErrName == "" || f.createErrName == team.Name) {
-		return f.createErr
-	}
-	if _, exists := f.teams[team.Name]; exists {
-		return domain.ErrTeamExists
-	}
-	f.teams[team.Name] = team
-	return nil
-}
+			operationStorage := newFakeOperationStorage()
+			if tt.reverted {
+				_ = tt.operation.MarkReverted(base)
+			}
+			operationStorage.operations[tt.operation.ID] = tt.operation
+			userStorage := newFakeUserStorage(users()...)
+			prStorage := newFakePullRequestStorage(prs()...)
 
-func (f *fakeTeamStorage) ListTeams(_ context.Context) ([]domain.Team, error) {
-	result := make([]domain.Team, 0, len(f.teams))
-	for _, team := range f.teams {
-		result = append(result, team)
+			uc := NewRevertOperationUseCase(operationStorage, userStorage, prStorage, fakeClock{now: base}, &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+			result, err := uc.Revert(ctx, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && tt.verify != nil {
+				tt.verify(t, result, userStorage, prStorage, operationStorage)
+			}
+		})
 	}
-	return result, nil
 }
 
-func (f *fakeTeamStorage) GetTeam(_ context.Context, name string) (domain.Team, error) {
-	if f.getErr != nil && (f.getErrName == "" || f.getErrName == name) {
-		return domain.Team{}, f.getErr
-	}
-	team, ok := f.teams[name]
-	if !ok {
-		return domain.Team{}, domain.ErrTeamNotFound
-	}
-	return team, nil
-}
+func TestEnqueueJobUseCase_Enqueue(t *testing.T) {
+	t.Parallel()
 
-type fakePullRequestStorage struct {
-	prs               map[string]domain.PullRequest
-	listErr           error
-	listByReviewerErr error
-	createErr         error
-	createErrID       string
-	getErr            error
-	getErrID          string
-	updateErr         error
-	updateErrID       string
-}
+	ctx := context.Background()
+	jobStorage := newFakeJobStorage()
+	uc := NewEnqueueJobUseCase(jobStorage, fakeClock{now: time.Unix(1000, 0)}, &fakeIDGenerator{}, testLogger())
 
-func newFakePullRequestStorage(prs ...domain.PullRequest) *fakePullRequestStorage {
-	m := make(map[string]domain.PullRequest, len(prs))
-	for _, pr := range prs {
-		m[pr.ID] = pr
+	job, err := uc.Enqueue(ctx, domain.JobTypeTeamRebalance, TeamRebalanceParams{TeamName: "backend", DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	return &fakePullRequestStorage{prs: m}
-}
 
-func (f *fakePullRequestStorage) CreatePullRequest(_ context.Context, pr domain.PullRequest) error {
-	if f.createErr != nil && (f.createErrID == "" || f.createErrID == pr.ID) {
-		return f.createErr
-	}
-	if _, exists := f.prs[pr.ID]; exists {
-		return domain.ErrPullRequestExists
+	stored, err := jobStorage.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("expected job stored: %v", err)
 	}
-	f.prs[pr.ID] = pr
-	return nil
-}
-
-func (f *fakePullRequestStorage) ListPullRequests(_ context.Context) ([]domain.PullRequest, error) {
-	if f.listErr != nil {
-		return nil, f.listErr
+	if stored.Status != domain.JobStatusPending || stored.Type != domain.JobTypeTeamRebalance {
+		t.Fatalf("unexpected job: %+v", stored)
 	}
-	result := make([]domain.PullRequest, 0, len(f.prs))
-	for _, pr := range f.prs {
-		result = append(result, pr)
+	var params TeamRebalanceParams
+	if err := json.Unmarshal(stored.Payload, &params); err != nil || params.TeamName != "backend" || !params.DryRun {
+		t.Fatalf("unexpected payload %s: %v", stored.Payload, err)
 	}
-	return result, nil
 }
 
-func (f *fakePullRequestStorage) GetPullRequest(_ context.Context, id string) (domain.PullRequest, error) {
-	if f.getErr != nil && (f.getErrID == "" || f.getErrID == id) {
-		return domain.PullRequest{}, f.getErr
-	}
-	pr, ok := f.prs[id]
-	if !ok {
-		return domain.PullRequest{}, domain.ErrPullRequestNotFound
-	}
-	return pr, nil
-}
+func TestEnqueueJobUseCase_EnqueueKeepsActor(t *testing.T) {
+	t.Parallel()
 
-func (f *fakePullRequestStorage) UpdatePullRequest(_ context.Context, pr domain.PullRequest) error {
-	if _, ok := f.prs[pr.ID]; !ok {
-		return domain.ErrPullRequestNotFound
+	ctx := context.Background()
+	jobStorage := newFakeJobStorage()
+	uc := NewEnqueueJobUseCase(jobStorage, fakeClock{now: time.Unix(1000, 0)}, &fakeIDGenerator{}, testLogger())
+
+	job, err := uc.Enqueue(WithActor(ctx, "admin:alice"), domain.JobTypeTeamRebalance, TeamRebalanceParams{TeamName: "backend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if f.updateErr != nil && (f.updateErrID == "" || f.updateErrID == pr.ID) {
-		return f.updateErr
+	if job.Actor != "admin:alice" {
+		t.Fatalf("expected actor admin:alice, got %q", job.Actor)
 	}
-	f.prs[pr.ID] = pr
-	return nil
-}
 
-func (f *fakePullRequestStorage) ListPullRequestsByReviewer(_ context.Context, reviewerID string) ([]domain.PullRequest, error) {
-	if f.listByReviewerErr != nil {
-		return nil, f.listByReviewerErr
+	job, err = uc.Enqueue(ctx, domain.JobTypeTeamRebalance, TeamRebalanceParams{TeamName: "backend"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	result := make([]domain.PullRequest, 0)
-	for _, pr := range f.prs {
-		for _, reviewer := range pr.Reviewers {
-			if reviewer == reviewerID {
-				result = append(result, pr)
-				break
-			}
-		}
+	if job.Actor != domain.ActorSystem {
+		t.Fatalf("expected system actor, got %q", job.Actor)
 	}
-	return result, nil
 }
 
-type fakeClock struct {
-	now time.Time
-}
+func TestJobWorker_RunOnce(t *testing.T) {
+	t.Parallel()
 
-func (f fakeClock) Now() time.Time {
-	return f.now
-}
+	now := time.Unix(10000, 0)
+	const timeout = 50 * time.Millisecond
+	errHandler := errors.New("handler failure")
 
-type fakeRandom struct {
-	calls int
-}
+	pendingJob := func(jobType string) domain.Job {
+		return domain.NewJob("job-1", jobType, "admin", []byte(`{}`), now.Add(-time.Minute))
+	}
+	runningJob := func(heartbeat time.Time) domain.Job {
+		job := pendingJob("test")
+		job.Status = domain.JobStatusRunning
+		job.Attempts = 1
+		job.StartedAt = &heartbeat
+		job.HeartbeatAt = &heartbeat
+		return job
+	}
 
-func (f *fakeRandom) Shuffle(n int, swap func(i, j int)) {
-	f.calls++
-	if n > 1 {
-		swap(0, n-1)
+	handlers := map[string]JobFunc{
+		"test": func(ctx context.Context, _ []byte) (any, error) {
+			tracker := startProgress(ctx, 2)
+			tracker.step()
+			tracker.step()
+			return map[string]int{"moved": 2}, nil
+		},
+		"failing": func(context.Context, []byte) (any, error) {
+			return nil, errHandler
+		},
+		"slow": func(ctx context.Context, _ []byte) (any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+		"panicking": func(context.Context, []byte) (any, error) {
+			panic("boom")
+		},
 	}
-}
 
-func stringPtr(s string) *string {
-	return &s
+	tests := []struct {
+		name          string
+		job           *domain.Job
+		cancel        bool
+		wantProcessed bool
+		wantStatus    string
+		wantError     string
+		verify        func(t *testing.T, job domain.Job, storage *fakeJobStorage)
+	}{
+		{
+			name:          "empty queue",
+			wantProcessed: false,
+		},
+		{
+			name:          "succeeds with result and progress",
+			job:           func() *domain.Job { job := pendingJob("test"); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusSucceeded,
+			verify: func(t *testing.T, job domain.Job, storage *fakeJobStorage) {
+				t.Helper()
+				if string(job.Result) != `{"moved":2}` {
+					t.Fatalf("unexpected result %s", job.Result)
+				}
+				if job.Done != 2 || job.Total != 2 || job.Attempts != 1 || job.FinishedAt == nil {
+					t.Fatalf("unexpected job state %+v", job)
+				}
+				// три отметки прогресса (0, 1, 2) и итог
+				if storage.updates != 4 {
+					t.Fatalf("expected 4 updates, got %d", storage.updates)
+				}
+			},
+		},
+		{
+			name:          "handler error fails job",
+			job:           func() *domain.Job { job := pendingJob("failing"); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusFailed,
+			wantError:     errHandler.Error(),
+		},
+		{
+			name:          "unknown type fails job",
+			job:           func() *domain.Job { job := pendingJob("unknown"); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusFailed,
+			wantError:     `неизвестный тип задачи "unknown"`,
+		},
+		{
+			name:          "timeout fails job",
+			job:           func() *domain.Job { job := pendingJob("slow"); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusFailed,
+			wantError:     "превышено время выполнения 50ms: context deadline exceeded",
+		},
+		{
+			name:          "panic fails job",
+			job:           func() *domain.Job { job := pendingJob("panicking"); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusFailed,
+			wantError:     "паника в обработчике задачи: boom",
+		},
+		{
+			name: "gives up after max attempts",
+			job: func() *domain.Job {
+				job := runningJob(now.Add(-time.Hour))
+				job.Attempts = maxJobAttempts
+				return &job
+			}(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusFailed,
+			wantError:     "задача не завершилась за 3 попытки",
+		},
+		{
+			name:          "resumes abandoned running job",
+			job:           func() *domain.Job { job := runningJob(now.Add(-time.Hour)); return &job }(),
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusSucceeded,
+			verify: func(t *testing.T, job domain.Job, _ *fakeJobStorage) {
+				t.Helper()
+				if job.Attempts != 2 {
+					t.Fatalf("expected second attempt, got %d", job.Attempts)
+				}
+			},
+		},
+		{
+			name:          "skips running job with fresh heartbeat",
+			job:           func() *domain.Job { job := runningJob(now.Add(-time.Second)); return &job }(),
+			wantProcessed: false,
+			wantStatus:    domain.JobStatusRunning,
+		},
+		{
+			name:          "shutdown leaves job running",
+			job:           func() *domain.Job { job := pendingJob("slow"); return &job }(),
+			cancel:        true,
+			wantProcessed: true,
+			wantStatus:    domain.JobStatusRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := newFakeJobStorage()
+			if tt.job != nil {
+				storage.jobs[tt.job.ID] = *tt.job
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				time.AfterFunc(10*time.Millisecond, cancel)
+			}
+
+			worker := NewJobWorker(storage, handlers, fakeClock{now: now}, timeout, time.Millisecond, testLogger())
+			processed, err := worker.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if processed != tt.wantProcessed {
+				t.Fatalf("expected processed=%v, got %v", tt.wantProcessed, processed)
+			}
+			if tt.job == nil {
+				return
+			}
+
+			job := storage.jobs[tt.job.ID]
+			if job.Status != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s (%s)", tt.wantStatus, job.Status, job.Error)
+			}
+			if job.Error != tt.wantError {
+				t.Fatalf("expected error %q, got %q", tt.wantError, job.Error)
+			}
+			if tt.verify != nil {
+				tt.verify(t, job, storage)
+			}
+		})
+	}
+}
+
+func TestRebalanceTeamUseCase_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := time.Unix(1000, 0)
+
+	reviewedPR := func(id, authorID, teamName string, offset int, reviewers ...string) domain.PullRequest {
+		pr := domain.NewPullRequest(id, "Feature", authorID, teamName, base.Add(time.Duration(offset)*time.Minute))
+		pr.AssignReviewers(reviewers)
+		return pr
+	}
+
+	backend := domain.NewTeam("backend", []domain.User{
+		domain.NewUser("u1", "Alice", "backend", true),
+		domain.NewUser("u2", "Bob", "backend", true),
+		domain.NewUser("u3", "Charlie", "backend", true),
+		domain.NewUser("gone", "Dave", "backend", false),
+	})
+	frontend := domain.NewTeam("frontend", []domain.User{domain.NewUser("author", "Eve", "frontend", true)})
+
+	overloaded := func() []domain.PullRequest {
+		return []domain.PullRequest{
+			reviewedPR("p1", "author", "frontend", 1, "u1", "u2"),
+			reviewedPR("p2", "author", "frontend", 2, "u1"),
+			reviewedPR("p3", "author", "frontend", 3, "u1", "gone"),
+			reviewedPR("p4", "author", "frontend", 4, "u1"),
+		}
+	}
+
+	errUpdate := errors.New("update failure")
+
+	member := func(id string, weight float64) domain.User {
+		user := domain.NewUser(id, id, "backend", true)
+		user.ReviewWeight = weight
+		return user
+	}
+	away := member("away", 1)
+	awayFrom, awayUntil := base.Add(-time.Hour), base.Add(time.Hour)
+	away.AbsentFrom, away.AbsentUntil = &awayFrom, &awayUntil
+
+	reviewLoads := func(prs *fakePullRequestStorage) map[string]int {
+		loads := make(map[string]int)
+		for _, pr := range prs.prs {
+			for _, reviewerID := range pr.Reviewers {
+				loads[reviewerID]++
+			}
+		}
+		return loads
+	}
+
+	tests := []struct {
+		name      string
+		teams     []domain.Team
+		prs       []domain.PullRequest
+		teamName  string
+		dryRun    bool
+		configure func(prs *fakePullRequestStorage)
+		wantErr   error
+		wantMoves int
+		verify    func(t *testing.T, result RebalanceResult, prs *fakePullRequestStorage)
+	}{
+		{
+			name:      "moves reviews with minimum swaps",
+			teams:     []domain.Team{backend, frontend},
+			prs:       overloaded(),
+			teamName:  "backend",
+			wantMoves: 2,
+			verify: func(t *testing.T, result RebalanceResult, prs *fakePullRequestStorage) {
+				t.Helper()
+				loads := make(map[string]int)
+				for _, pr := range prs.prs {
+					for _, reviewerID := range pr.Reviewers {
+						loads[reviewerID]++
+					}
+				}
+				if loads["u1"] != 2 || loads["u2"] != 1 || loads["u3"] != 2 {
+					t.Fatalf("expected balanced loads, got %v", loads)
+				}
+				if loads["gone"] != 1 {
+					t.Fatalf("expected inactive reviewer untouched, got %v", loads)
+				}
+				for _, load := range result.Loads {
+					if load.UserID == "u1" && (load.Before != 4 || load.After != 2) {
+						t.Fatalf("expected u1 load 4 -> 2, got %+v", load)
+					}
+				}
+			},
+		},
+		{
+			name: "targets are proportional to weight",
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{member("double", 2), member("normal", 1), member("busy", 1)}),
+				frontend,
+			},
+			prs: func() []domain.PullRequest {
+				prs := make([]domain.PullRequest, 0, 8)
+				for i := 0; i < 8; i++ {
+					prs = append(prs, reviewedPR(fmt.Sprintf("p%d", i), "author", "frontend", i, "busy"))
+				}
+				return prs
+			}(),
+			teamName:  "backend",
+			wantMoves: 6,
+			verify: func(t *testing.T, _ RebalanceResult, prs *fakePullRequestStorage) {
+				t.Helper()
+				loads := reviewLoads(prs)
+				if loads["double"] != 4 || loads["normal"] != 2 || loads["busy"] != 2 {
+					t.Fatalf("expected loads 4/2/2 by weight, got %v", loads)
+				}
+			},
+		},
+		{
+			name: "zero weight and absent members receive nothing",
+			teams: []domain.Team{
+				domain.NewTeam("backend", []domain.User{member("disabled", 0), away, member("present", 1)}),
+				frontend,
+			},
+			prs: []domain.PullRequest{
+				reviewedPR("p1", "author", "frontend", 1, "disabled"),
+				reviewedPR("p2", "author", "frontend", 2, "disabled"),
+			},
+			teamName:  "backend",
+			wantMoves: 2,
+			verify: func(t *testing.T, _ RebalanceResult, prs *fakePullRequestStorage) {
+				t.Helper()
+				loads := reviewLoads(prs)
+				if loads["present"] != 2 || loads["disabled"] != 0 || loads["away"] != 0 {
+					t.Fatalf("expected reviews moved to present only, got %v", loads)
+				}
+			},
+		},
+		{
+			name:      "dry run does not write",
+			teams:     []domain.Team{backend, frontend},
+			prs:       overloaded(),
+			teamName:  "backend",
+			dryRun:    true,
+			wantMoves: 2,
+			verify: func(t *testing.T, result RebalanceResult, prs *fakePullRequestStorage) {
+				t.Helper()
+				if !result.DryRun {
+					t.Fatalf("expected dry run result")
+				}
+				for _, id := range []string{"p1", "p2", "p3", "p4"} {
+					if !contains(prs.prs[id].Reviewers, "u1") {
+						t.Fatalf("expected %s untouched, got %v", id, prs.prs[id].Reviewers)
+					}
+				}
+			},
+		},
+		{
+			name:  "respects author exclusion",
+			teams: []domain.Team{domain.NewTeam("backend", backend.Users[:2])},
+			prs: []domain.PullRequest{
+				reviewedPR("p1", "u2", "backend", 1, "u1"),
+				reviewedPR("p2", "u2", "backend", 2, "u1"),
+			},
+			teamName:  "backend",
+			wantMoves: 0,
+		},
+		{
+			name:  "keeps required reviewers",
+			teams: []domain.Team{backend, frontend},
+			prs: func() []domain.PullRequest {
+				prs := overloaded()
+				for i := range prs {
+					prs[i].RequiredReviewers = []string{"u1"}
+				}
+				return prs
+			}(),
+			teamName:  "backend",
+			wantMoves: 0,
+		},
+		{
+			name: "keeps the only senior reviewer",
+			teams: []domain.Team{seniorPolicyTeam("backend",
+				seniorUser("u1", "Alice", "backend"),
+				domain.NewUser("u2", "Bob", "backend", true),
+			)},
+			prs: []domain.PullRequest{
+				reviewedPR("p1", "author", "backend", 1, "u1"),
+				reviewedPR("p2", "author", "backend", 2, "u1"),
+			},
+			teamName:  "backend",
+			wantMoves: 0,
+		},
+		{
+			name:     "team not found",
+			teams:    []domain.Team{backend},
+			teamName: "missing",
+			wantErr:  domain.ErrTeamNotFound,
+		},
+		{
+			name:     "update error",
+			teams:    []domain.Team{backend, frontend},
+			prs:      overloaded(),
+			teamName: "backend",
+			configure: func(prs *fakePullRequestStorage) {
+				prs.updateErr = errUpdate
+			},
+			wantErr: errUpdate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var users []domain.User
+			for _, team := range tt.teams {
+				users = append(users, team.Users...)
+			}
+			prStorage := newFakePullRequestStorage(tt.prs...)
+			if tt.configure != nil {
+				tt.configure(prStorage)
+			}
+			uc := NewRebalanceTeamUseCase(newFakeTeamStorage(tt.teams...), newFakeUserStorage(users...), prStorage, fakeClock{now: base}, &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+
+			result, err := uc.Rebalance(ctx, tt.teamName, tt.dryRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(result.Moves) != tt.wantMoves {
+				t.Fatalf("expected %d moves, got %+v", tt.wantMoves, result.Moves)
+			}
+			if tt.verify != nil {
+				tt.verify(t, result, prStorage)
+			}
+		})
+	}
+}
+
+func TestHistoryReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	base := time.Unix(1000, 0)
+
+	pastPR := func(id string, offset int, reviewers ...string) domain.PullRequest {
+		pr := domain.NewPullRequest(id, "Past", "author", "backend", base.Add(time.Duration(offset)*time.Minute))
+		pr.AssignReviewers(reviewers)
+		return pr
+	}
+
+	candidates := []domain.User{
+		domain.NewUser("frequent", "Bob", "backend", true),
+		domain.NewUser("sometimes", "Charlie", "backend", true),
+		domain.NewUser("fresh", "Dave", "backend", true),
+	}
+
+	errList := errors.New("list failure")
+
+	pairingTests := []struct {
+		name   string
+		prs    *fakePullRequestStorage
+		window int
+		want   map[string]int
+	}{
+		{
+			name: "counts recent pairings",
+			prs: newFakePullRequestStorage(
+				pastPR("p1", 1, "frequent", "sometimes"),
+				pastPR("p2", 2, "frequent"),
+				pastPR("p3", 3, "frequent"),
+			),
+			window: 10,
+			want:   map[string]int{"frequent": 3, "sometimes": 1},
+		},
+		{
+			name: "ignores pull requests outside window",
+			prs: newFakePullRequestStorage(
+				pastPR("old-1", 1, "fresh"),
+				pastPR("old-2", 2, "fresh"),
+				pastPR("recent", 3, "frequent", "sometimes"),
+			),
+			window: 1,
+			want:   map[string]int{"frequent": 1, "sometimes": 1},
+		},
+		{
+			name: "ignores the pull request being assigned",
+			prs: newFakePullRequestStorage(
+				pastPR("pr-new", 5, "fresh", "sometimes"),
+				pastPR("p1", 1, "frequent"),
+			),
+			window: 1,
+			want:   map[string]int{"frequent": 1},
+		},
+	}
+
+	for _, tt := range pairingTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pr := domain.NewPullRequest("pr-new", "New", "author", "backend", base.Add(time.Hour))
+			selector := NewHistoryReviewerSelector(tt.prs, &fakeRandom{}, tt.window, testLogger())
+
+			counts, err := selector.pairingCounts(ctx, pr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(counts) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, counts)
+			}
+			for id, want := range tt.want {
+				if counts[id] != want {
+					t.Fatalf("expected %v, got %v", tt.want, counts)
+				}
+			}
+		})
+	}
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		store := newFakePullRequestStorage()
+		store.listErr = errList
+		pr := domain.NewPullRequest("pr-new", "New", "author", "backend", base)
+		selector := NewHistoryReviewerSelector(store, random.New(rand.New(rand.NewSource(7))), 10, testLogger())
+
+		if _, err := selector.Select(ctx, pr, candidates, 1); !errors.Is(err, errList) {
+			t.Fatalf("expected %v, got %v", errList, err)
+		}
+	})
+
+	t.Run("distribution follows pairing penalty", func(t *testing.T) {
+		t.Parallel()
+
+		const trials = 20000
+		store := newFakePullRequestStorage(
+			pastPR("p1", 1, "frequent", "sometimes"),
+			pastPR("p2", 2, "frequent"),
+			pastPR("p3", 3, "frequent"),
+		)
+		pr := domain.NewPullRequest("pr-new", "New", "author", "backend", base.Add(time.Hour))
+		selector := NewHistoryReviewerSelector(store, random.New(rand.New(rand.NewSource(42))), 10, testLogger())
+
+		// веса 1/(1+n): fresh 1, sometimes 1/2, frequent 1/4
+		want := map[string]float64{"fresh": 1 / 1.75, "sometimes": 0.5 / 1.75, "frequent": 0.25 / 1.75}
+
+		counts := make(map[string]int, len(candidates))
+		for i := 0; i < trials; i++ {
+			selected, err := selector.Select(ctx, pr, candidates, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			counts[selected[0].ID]++
+		}
+
+		for id, share := range want {
+			got := float64(counts[id]) / trials
+			if math.Abs(got-share) > 0.02 {
+				t.Fatalf("expected %s share %.3f, got %.3f (counts %v)", id, share, got, counts)
+			}
+		}
+	})
+
+	t.Run("seeded random selects deterministically", func(t *testing.T) {
+		t.Parallel()
+
+		pr := domain.NewPullRequest("pr-new", "New", "author", "backend", base)
+		pick := func() string {
+			selector := NewHistoryReviewerSelector(newFakePullRequestStorage(), random.New(rand.New(rand.NewSource(42))), 10, testLogger())
+			selected, err := selector.Select(ctx, pr, candidates, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return selected[0].ID
+		}
+
+		first := pick()
+		for i := 0; i < 5; i++ {
+			if got := pick(); got != first {
+				t.Fatalf("expected deterministic choice %q, got %q", first, got)
+			}
+		}
+	})
+}
+
+func TestWorkingHoursReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	// 09:00 UTC: 12:00 в Москве и 19:00 во Владивостоке
+	now := time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)
+
+	scheduled := func(id, timezone string, start, end int) domain.User {
+		user := domain.NewUser(id, id, "backend", true)
+		user.Timezone = timezone
+		user.WorkStartHour = start
+		user.WorkEndHour = end
+		return user
+	}
+
+	moscow := scheduled("moscow", "Europe/Moscow", 10, 19)
+	vladivostok := scheduled("vladivostok", "Asia/Vladivostok", 10, 19)
+	nightOwl := scheduled("night", "Asia/Vladivostok", 18, 2)
+	anytime := domain.NewUser("anytime", "anytime", "backend", true)
+
+	tests := []struct {
+		name       string
+		candidates []domain.User
+		count      int
+		want       []string
+	}{
+		{
+			name:       "prefers reviewers within working hours",
+			candidates: []domain.User{vladivostok, moscow},
+			count:      1,
+			want:       []string{"moscow"},
+		},
+		{
+			name:       "supports working hours across midnight",
+			candidates: []domain.User{vladivostok, nightOwl},
+			count:      1,
+			want:       []string{"night"},
+		},
+		{
+			name:       "falls back to off-hours reviewers",
+			candidates: []domain.User{vladivostok, moscow},
+			count:      2,
+			want:       []string{"moscow", "vladivostok"},
+		},
+		{
+			name:       "default schedule is always working",
+			candidates: []domain.User{vladivostok, anytime},
+			count:      1,
+			want:       []string{"anytime"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			selector := NewWorkingHoursReviewerSelector(NewRandomReviewerSelector(&fakeRandom{}), fakeClock{now: now})
+			selected, err := selector.Select(ctx, domain.PullRequest{}, tt.candidates, tt.count)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(selected) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, selected)
+			}
+			for i, id := range tt.want {
+				if selected[i].ID != id {
+					t.Fatalf("expected %v, got %v", tt.want, selected)
+				}
+			}
+		})
+	}
+
+	t.Run("ranks candidates with one inner call", func(t *testing.T) {
+		t.Parallel()
+
+		inner := &countingReviewerSelector{next: NewRandomReviewerSelector(&fakeRandom{})}
+		selector := NewWorkingHoursReviewerSelector(inner, fakeClock{now: now})
+		selected, err := selector.Select(ctx, domain.PullRequest{}, []domain.User{vladivostok, moscow, anytime}, 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if inner.calls != 1 {
+			t.Fatalf("expected one inner call, got %d", inner.calls)
+		}
+		if len(selected) != 3 || selected[2].ID != "vladivostok" {
+			t.Fatalf("expected off-hours reviewer last, got %v", selected)
+		}
+	})
+}
+
+// countingReviewerSelector считает вызовы вложенной стратегии.
+type countingReviewerSelector struct {
+	next  ReviewerSelector
+	calls int
+}
+
+func (s *countingReviewerSelector) Select(ctx context.Context, pr domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	s.calls++
+	return s.next.Select(ctx, pr, candidates, count)
+}
+
+func TestAvailableReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, time.July, 3, 12, 0, 0, 0, time.UTC)
+	from, until := now.Add(-24*time.Hour), now.Add(24*time.Hour)
+
+	absent := domain.NewUser("absent", "absent", "backend", true)
+	absent.AbsentFrom, absent.AbsentUntil = &from, &until
+	returned := domain.NewUser("returned", "returned", "backend", true)
+	returnedUntil := now
+	returned.AbsentFrom, returned.AbsentUntil = &from, &returnedUntil
+	present := domain.NewUser("present", "present", "backend", true)
+
+	rotations := newFakeRotationStorage()
+	selector := NewAvailableReviewerSelector(NewRoundRobinReviewerSelector(rotations, testLogger()), fakeClock{now: now})
+
+	selected, err := selector.Select(ctx, domain.PullRequest{}, []domain.User{absent, returned, present}, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != 2 || selected[0].ID != "present" || selected[1].ID != "returned" {
+		t.Fatalf("expected present and returned, got %v", selected)
+	}
+
+	// ротация пропускает отсутствующего, а не держит для него место
+	if err := selector.RecordSelection(ctx, domain.PullRequest{}, selected[:1]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selected, err = selector.Select(ctx, domain.PullRequest{}, []domain.User{absent, returned, present}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != 1 || selected[0].ID != "returned" {
+		t.Fatalf("expected returned after present, got %v", selected)
+	}
+
+	selected, err = selector.Select(ctx, domain.PullRequest{}, []domain.User{absent}, 1)
+	if err != nil || len(selected) != 0 {
+		t.Fatalf("expected no reviewers, got %v, %v", selected, err)
+	}
+
+	disabled := domain.NewUser("disabled", "disabled", "backend", true)
+	disabled.ReviewWeight = 0
+	strategies := map[string]ReviewerSelector{
+		"random":      NewRandomReviewerSelector(random.New(rand.New(rand.NewSource(1)))),
+		"round_robin": NewRoundRobinReviewerSelector(newFakeRotationStorage(), testLogger()),
+		"history":     NewHistoryReviewerSelector(newFakePullRequestStorage(), &fakeRandom{}, 10, testLogger()),
+	}
+	for name, strategy := range strategies {
+		selector := NewAvailableReviewerSelector(strategy, fakeClock{now: now})
+		selected, err := selector.Select(ctx, domain.PullRequest{AuthorID: "author"}, []domain.User{disabled, present}, 2)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(selected) != 1 || selected[0].ID != "present" {
+			t.Fatalf("%s: expected zero weight excluded, got %v", name, selected)
+		}
+	}
+}
+
+func TestRandomReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	weighted := func(id string, weight float64) domain.User {
+		user := domain.NewUser(id, id, "backend", true)
+		user.ReviewWeight = weight
+		return user
+	}
+
+	t.Run("equal weights shuffle uniformly", func(t *testing.T) {
+		t.Parallel()
+
+		selector := NewRandomReviewerSelector(&fakeRandom{})
+		selected, err := selector.Select(ctx, domain.PullRequest{}, []domain.User{weighted("a", 1), weighted("b", 1), weighted("c", 1)}, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(selected) != 1 || selected[0].ID != "c" {
+			t.Fatalf("expected shuffled c, got %v", selected)
+		}
+	})
+
+	t.Run("distribution follows weights when they differ", func(t *testing.T) {
+		t.Parallel()
+
+		const trials = 20000
+		selector := NewRandomReviewerSelector(random.New(rand.New(rand.NewSource(7))))
+		candidates := []domain.User{weighted("normal", 1), weighted("triple", 3)}
+
+		counts := make(map[string]int, len(candidates))
+		for i := 0; i < trials; i++ {
+			selected, err := selector.Select(ctx, domain.PullRequest{}, candidates, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			counts[selected[0].ID]++
+		}
+
+		if got := float64(counts["triple"]) / trials; math.Abs(got-0.75) > 0.02 {
+			t.Fatalf("expected triple share 0.75, got %.3f (counts %v)", got, counts)
+		}
+	})
+}
+
+func TestWeightedReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	weighted := func(id string, weight float64) domain.User {
+		user := domain.NewUser(id, id, "backend", true)
+		user.ReviewWeight = weight
+		return user
+	}
+
+	t.Run("never selects zero weight", func(t *testing.T) {
+		t.Parallel()
+
+		selector := NewWeightedReviewerSelector(random.New(rand.New(rand.NewSource(1))))
+		candidates := []domain.User{weighted("normal", 1), weighted("disabled", 0)}
+		for i := 0; i < 1000; i++ {
+			selected, err := selector.Select(ctx, domain.PullRequest{}, candidates, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(selected) != 1 || selected[0].ID != "normal" {
+				t.Fatalf("expected only normal, got %v", selected)
+			}
+		}
+	})
+
+	t.Run("distribution follows weights", func(t *testing.T) {
+		t.Parallel()
+
+		const trials = 20000
+		selector := NewWeightedReviewerSelector(random.New(rand.New(rand.NewSource(42))))
+		candidates := []domain.User{weighted("part-time", 0.5), weighted("normal", 1), weighted("double", 2)}
+		want := map[string]float64{"part-time": 0.5 / 3.5, "normal": 1 / 3.5, "double": 2 / 3.5}
+
+		counts := make(map[string]int, len(candidates))
+		for i := 0; i < trials; i++ {
+			selected, err := selector.Select(ctx, domain.PullRequest{}, candidates, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			counts[selected[0].ID]++
+		}
+
+		for id, share := range want {
+			got := float64(counts[id]) / trials
+			if math.Abs(got-share) > 0.02 {
+				t.Fatalf("expected %s share %.3f, got %.3f (counts %v)", id, share, got, counts)
+			}
+		}
+	})
+
+	t.Run("selects distinct reviewers", func(t *testing.T) {
+		t.Parallel()
+
+		selector := NewWeightedReviewerSelector(random.New(rand.New(rand.NewSource(3))))
+		candidates := []domain.User{weighted("a", 5), weighted("b", 1), weighted("c", 1)}
+		for i := 0; i < 1000; i++ {
+			selected, err := selector.Select(ctx, domain.PullRequest{}, candidates, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(selected) != 2 || selected[0].ID == selected[1].ID {
+				t.Fatalf("expected two distinct reviewers, got %v", selected)
+			}
+		}
+	})
+}
+
+func TestRoundRobinReviewerSelector_Select(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	members := func(ids ...string) []domain.User {
+		users := make([]domain.User, 0, len(ids))
+		for _, id := range ids {
+			users = append(users, domain.NewUser(id, id, "backend", true))
+		}
+		return users
+	}
+
+	errRotation := errors.New("rotation failure")
+
+	tests := []struct {
+		name       string
+		last       string
+		err        error
+		candidates []domain.User
+		count      int
+		wantErr    error
+		want       []string
+		wantLast   string
+	}{
+		{
+			name:       "starts from the beginning",
+			candidates: members("u3", "u1", "u2"),
+			count:      2,
+			want:       []string{"u1", "u2"},
+			wantLast:   "u2",
+		},
+		{
+			name:       "continues after cursor and wraps",
+			last:       "u2",
+			candidates: members("u1", "u2", "u3"),
+			count:      2,
+			want:       []string{"u3", "u1"},
+			wantLast:   "u1",
+		},
+		{
+			name:       "skips users missing from candidates",
+			last:       "u1",
+			candidates: members("u1", "u4"),
+			count:      1,
+			want:       []string{"u4"},
+			wantLast:   "u4",
+		},
+		{
+			name:       "no candidates keeps cursor",
+			last:       "u1",
+			candidates: nil,
+			count:      2,
+			want:       nil,
+			wantLast:   "u1",
+		},
+		{
+			name:       "storage error",
+			err:        errRotation,
+			candidates: members("u1"),
+			count:      1,
+			wantErr:    errRotation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rotations := newFakeRotationStorage()
+			rotations.cursors["backend"] = tt.last
+			rotations.err = tt.err
+
+			selector := NewRoundRobinReviewerSelector(rotations, testLogger())
+			selected, err := selector.Select(ctx, domain.PullRequest{}, tt.candidates, tt.count)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(selected) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, selected)
+			}
+			for i, id := range tt.want {
+				if selected[i].ID != id {
+					t.Fatalf("expected %v, got %v", tt.want, selected)
+				}
+			}
+			if got := rotations.cursors["backend"]; got != tt.last {
+				t.Fatalf("expected cursor untouched before record, got %q", got)
+			}
+			if err := selector.RecordSelection(ctx, domain.PullRequest{}, selected); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := rotations.cursors["backend"]; got != tt.wantLast {
+				t.Fatalf("expected cursor %q, got %q", tt.wantLast, got)
+			}
+		})
+	}
+
+	t.Run("dry run moves only its own cursor", func(t *testing.T) {
+		t.Parallel()
+
+		rotations := newFakeRotationStorage()
+		rotations.cursors["backend"] = "u1"
+		selector := NewRoundRobinReviewerSelector(rotations, testLogger())
+		dryCtx := withDryRun(ctx)
+
+		// план по нескольким PR идёт по очереди, как реальный запуск
+		for _, want := range []string{"u2", "u3", "u1"} {
+			selected, err := selector.Select(dryCtx, domain.PullRequest{}, members("u1", "u2", "u3"), 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(selected) != 1 || selected[0].ID != want {
+				t.Fatalf("expected %s, got %v", want, selected)
+			}
+			if err := selector.RecordSelection(dryCtx, domain.PullRequest{}, selected); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if got := rotations.cursors["backend"]; got != "u1" {
+			t.Fatalf("expected stored cursor untouched, got %q", got)
+		}
+
+		selected, err := selector.Select(withDryRun(ctx), domain.PullRequest{}, members("u1", "u2", "u3"), 1)
+		if err != nil || len(selected) != 1 || selected[0].ID != "u2" {
+			t.Fatalf("expected new dry run to start from stored cursor, got %v, %v", selected, err)
+		}
+	})
+
+	t.Run("senior policy moves cursor once by in-turn reviewers", func(t *testing.T) {
+		t.Parallel()
+
+		rotations := newFakeRotationStorage()
+		rotations.cursors["backend"] = "u1"
+		selector := NewRoundRobinReviewerSelector(rotations, testLogger())
+
+		senior := seniorUser("u1", "u1", "backend")
+		candidates := append([]domain.User{senior}, members("u2", "u3", "u4")...)
+		team := seniorPolicyTeam("backend", candidates...)
+		users := newFakeUserStorage(candidates...)
+
+		for _, want := range [][]string{{"u2", "u1"}, {"u3", "u1"}} {
+			selected, err := selectWithSeniorPolicy(ctx, selector, users, team, domain.PullRequest{}, candidates, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(selected) != 2 || selected[0].ID != want[0] || selected[1].ID != want[1] {
+				t.Fatalf("expected %v, got %v", want, selected)
+			}
+			// senior добавлен вне очереди: курсор встаёт на выбранного по очереди
+			if got := rotations.cursors["backend"]; got != want[0] {
+				t.Fatalf("expected cursor %q, got %q", want[0], got)
+			}
+		}
+	})
+
+	t.Run("concurrent selections rotate evenly", func(t *testing.T) {
+		t.Parallel()
+
+		const rounds = 10
+		candidates := members("u1", "u2", "u3", "u4")
+		selector := NewRoundRobinReviewerSelector(newFakeRotationStorage(), testLogger())
+
+		var (
+			mu     sync.Mutex
+			tx     sync.Mutex // блокировка строки ротации до конца транзакции
+			counts = make(map[string]int)
+			wg     sync.WaitGroup
+		)
+		for i := 0; i < rounds*len(candidates); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tx.Lock()
+				defer tx.Unlock()
+				selected, err := selector.Select(ctx, domain.PullRequest{}, candidates, 1)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if err := selector.RecordSelection(ctx, domain.PullRequest{}, selected); err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				mu.Lock()
+				counts[selected[0].ID]++
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		for _, candidate := range candidates {
+			if counts[candidate.ID] != rounds {
+				t.Fatalf("expected each reviewer %d times, got %v", rounds, counts)
+			}
+		}
+	})
+}
+
+// test helpers
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{}))
+}
+
+type fakeUserStorage struct {
+	users       map[string]domain.User
+	createErr   error
+	createErrID string
+	getErr      error
+	getErrID    string
+	updateErr   error
+	updateErrID string
+	listErr     error
+}
+
+func newFakeUserStorage(users ...domain.User) *fakeUserStorage {
+	m := make(map[string]domain.User, len(users))
+	for _, u := range users {
+		m[u.ID] = u
+	}
+	return &fakeUserStorage{users: m}
+}
+
+func (f *fakeUserStorage) CreateUser(_ context.Context, user domain.User) error {
+	if f.createErr != nil && (f.createErrID == "" || f.createErrID == user.ID) {
+		return f.createErr
+	}
+	f.users[user.ID] = user
+	return nil
+}
+
+func (f *fakeUserStorage) ListUsers(_ context.Context) ([]domain.User, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	result := make([]domain.User, 0, len(f.users))
+	for _, user := range f.users {
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+func (f *fakeUserStorage) GetUser(_ context.Context, id string) (domain.User, error) {
+	if f.getErr != nil && (f.getErrID == "" || f.getErrID == id) {
+		return domain.User{}, f.getErr
+	}
+	user, ok := f.users[id]
+	if !ok {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeUserStorage) LockUser(ctx context.Context, id string) (domain.User, error) {
+	return f.GetUser(ctx, id)
+}
+
+func (f *fakeUserStorage) UpdateUser(_ context.Context, user domain.User) error {
+	if _, ok := f.users[user.ID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	if f.updateErr != nil && (f.updateErrID == "" || f.updateErrID == user.ID) {
+		return f.updateErr
+	}
+	f.users[user.ID] = user
+	return nil
+}
+
+type fakeTeamStorage struct {
+	teams         map[string]domain.Team
+	getErr        error
+	getErrName    string
+	createErr     error
+	createErrName string
+	updateErr     error
+}
+
+func newFakeTeamStorage(teams ...domain.Team) *fakeTeamStorage {
+	m := make(map[string]domain.Team, len(teams))
+	for _, team := range teams {
+		m[team.Name] = team
+	}
+	return &fakeTeamStorage{teams: m}
+}
+
+func (f *fakeTeamStorage) CreateTeam(_ context.Context, team domain.Team) error {
+	if f.createErr != nil && (f.createErrName == "" || f.createErrName == team.Name) {
+		return f.createErr
+	}
+	if _, exists := f.teams[team.Name]; exists {
+		return domain.ErrTeamExists
+	}
+	f.teams[team.Name] = team
+	return nil
+}
+
+func (f *fakeTeamStorage) ListTeams(_ context.Context) ([]domain.Team, error) {
+	result := make([]domain.Team, 0, len(f.teams))
+	for _, team := range f.teams {
+		result = append(result, team)
+	}
+	return result, nil
+}
+
+func (f *fakeTeamStorage) UpdateTeam(_ context.Context, team domain.Team) error {
+	if f.updateErr != nil {
+		return f.updateErr
+	}
+	if _, ok := f.teams[team.Name]; !ok {
+		return domain.ErrTeamNotFound
+	}
+	f.teams[team.Name] = team
+	return nil
+}
+
+func (f *fakeTeamStorage) GetTeam(_ context.Context, name string) (domain.Team, error) {
+	if f.getErr != nil && (f.getErrName == "" || f.getErrName == name) {
+		return domain.Team{}, f.getErr
+	}
+	team, ok := f.teams[name]
+	if !ok {
+		return domain.Team{}, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+type fakePullRequestStorage struct {
+	prs               map[string]domain.PullRequest
+	listErr           error
+	listByReviewerErr error
+	createErr         error
+	createErrID       string
+	getErr            error
+	getErrID          string
+	updateErr         error
+	updateErrID       string
+}
+
+func newFakePullRequestStorage(prs ...domain.PullRequest) *fakePullRequestStorage {
+	m := make(map[string]domain.PullRequest, len(prs))
+	for _, pr := range prs {
+		m[pr.ID] = pr
+	}
+	return &fakePullRequestStorage{prs: m}
+}
+
+func (f *fakePullRequestStorage) CreatePullRequest(_ context.Context, pr domain.PullRequest) error {
+	if f.createErr != nil && (f.createErrID == "" || f.createErrID == pr.ID) {
+		return f.createErr
+	}
+	if _, exists := f.prs[pr.ID]; exists {
+		return domain.ErrPullRequestExists
+	}
+	f.prs[pr.ID] = pr
+	return nil
+}
+
+func (f *fakePullRequestStorage) ListPullRequests(_ context.Context) ([]domain.PullRequest, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	result := make([]domain.PullRequest, 0, len(f.prs))
+	for _, pr := range f.prs {
+		result = append(result, pr)
+	}
+	return result, nil
+}
+
+func (f *fakePullRequestStorage) GetPullRequest(_ context.Context, id string) (domain.PullRequest, error) {
+	if f.getErr != nil && (f.getErrID == "" || f.getErrID == id) {
+		return domain.PullRequest{}, f.getErr
+	}
+	pr, ok := f.prs[id]
+	if !ok {
+		return domain.PullRequest{}, domain.ErrPullRequestNotFound
+	}
+	return pr, nil
+}
+
+func (f *fakePullRequestStorage) UpdatePullRequest(_ context.Context, pr domain.PullRequest) error {
+	if _, ok := f.prs[pr.ID]; !ok {
+		return domain.ErrPullRequestNotFound
+	}
+	if f.updateErr != nil && (f.updateErrID == "" || f.updateErrID == pr.ID) {
+		return f.updateErr
+	}
+	f.prs[pr.ID] = pr
+	return nil
+}
+
+func (f *fakePullRequestStorage) ListPullRequestsByReviewer(_ context.Context, reviewerID string) ([]domain.PullRequest, error) {
+	if f.listByReviewerErr != nil {
+		return nil, f.listByReviewerErr
+	}
+	result := make([]domain.PullRequest, 0)
+	for _, pr := range f.prs {
+		for _, reviewer := range pr.Reviewers {
+			if reviewer == reviewerID {
+				result = append(result, pr)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakePullRequestStorage) ListRecentPullRequestsByAuthor(_ context.Context, authorID string, limit int) ([]domain.PullRequest, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	result := make([]domain.PullRequest, 0)
+	for _, pr := range f.prs {
+		if pr.AuthorID == authorID {
+			result = append(result, pr)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (f *fakePullRequestStorage) ListOpenPullRequestsByTeam(_ context.Context, teamName string) ([]domain.PullRequest, error) {
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	result := make([]domain.PullRequest, 0)
+	for _, pr := range f.prs {
+		if pr.TeamName == teamName && pr.Status == domain.PRStatusOpen {
+			result = append(result, pr)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+type fakeTopUp struct {
+	teams []string
+	err   error
+}
+
+func (f *fakeTopUp) TopUpTeam(_ context.Context, teamName string) (int, error) {
+	f.teams = append(f.teams, teamName)
+	return 0, f.err
+}
+
+type fakeOperationStorage struct {
+	operations map[string]domain.Operation
+}
+
+func newFakeOperationStorage() *fakeOperationStorage {
+	return &fakeOperationStorage{operations: make(map[string]domain.Operation)}
+}
+
+func (f *fakeOperationStorage) CreateOperation(_ context.Context, operation domain.Operation) error {
+	f.operations[operation.ID] = operation
+	return nil
+}
+
+func (f *fakeOperationStorage) LockOperation(_ context.Context, id string) (domain.Operation, error) {
+	operation, ok := f.operations[id]
+	if !ok {
+		return domain.Operation{}, domain.ErrOperationNotFound
+	}
+	return operation, nil
+}
+
+func (f *fakeOperationStorage) UpdateOperation(_ context.Context, operation domain.Operation) error {
+	if _, ok := f.operations[operation.ID]; !ok {
+		return domain.ErrOperationNotFound
+	}
+	f.operations[operation.ID] = operation
+	return nil
+}
+
+type fakeJobStorage struct {
+	jobs    map[string]domain.Job
+	updates int
+}
+
+func newFakeJobStorage() *fakeJobStorage {
+	return &fakeJobStorage{jobs: make(map[string]domain.Job)}
+}
+
+func (f *fakeJobStorage) CreateJob(_ context.Context, job domain.Job) error {
+	f.jobs[job.ID] = job
+	return nil
+}
+
+func (f *fakeJobStorage) GetJob(_ context.Context, id string) (domain.Job, error) {
+	job, ok := f.jobs[id]
+	if !ok {
+		return domain.Job{}, domain.ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (f *fakeJobStorage) ClaimJob(_ context.Context, now, staleBefore time.Time) (domain.Job, bool, error) {
+	for id, job := range f.jobs {
+		stale := job.Status == domain.JobStatusRunning && job.HeartbeatAt != nil && job.HeartbeatAt.Before(staleBefore)
+		if job.Status != domain.JobStatusPending && !stale {
+			continue
+		}
+		job.Status = domain.JobStatusRunning
+		job.Attempts++
+		if job.StartedAt == nil {
+			job.StartedAt = &now
+		}
+		job.HeartbeatAt = &now
+		f.jobs[id] = job
+		return job, true, nil
+	}
+	return domain.Job{}, false, nil
+}
+
+func (f *fakeJobStorage) UpdateJob(_ context.Context, job domain.Job) error {
+	stored, ok := f.jobs[job.ID]
+	if !ok || stored.Attempts != job.Attempts {
+		return domain.ErrJobLeaseLost
+	}
+	f.jobs[job.ID] = job
+	f.updates++
+	return nil
+}
+
+type fakeTransactor struct {
+	calls int
+}
+
+func (f *fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.calls++
+	return fn(ctx)
+}
+
+type fakeAuditEntry struct {
+	actor    string
+	action   string
+	entityID string
+}
+
+type fakeAuditor struct {
+	entries []fakeAuditEntry
+}
+
+func (f *fakeAuditor) Record(ctx context.Context, action, _, entityID string, _, _ any) error {
+	f.entries = append(f.entries, fakeAuditEntry{actor: ActorFromContext(ctx), action: action, entityID: entityID})
+	return nil
+}
+
+func (f *fakeAuditor) actions() []string {
+	var actions []string
+	for _, entry := range f.entries {
+		actions = append(actions, entry.action)
+	}
+	return actions
+}
+
+type fakeHistoryRecorder struct {
+	entries []domain.PullRequestHistoryEntry
+}
+
+func (f *fakeHistoryRecorder) Record(_ context.Context, entries ...domain.PullRequestHistoryEntry) error {
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+type fakeEventRecorder struct {
+	events []domain.Event
+}
+
+func (f *fakeEventRecorder) Record(_ context.Context, events ...domain.Event) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+type fakeOutboxStorage struct {
+	messages  []domain.OutboxMessage
+	claimErr  error
+	delivered map[int64]time.Time
+	failed    map[int64]string
+}
+
+func (f *fakeOutboxStorage) AddOutboxMessages(_ context.Context, messages []domain.OutboxMessage) error {
+	for _, message := range messages {
+		message.ID = int64(len(f.messages) + 1)
+		f.messages = append(f.messages, message)
+	}
+	return nil
+}
+
+func (f *fakeOutboxStorage) ClaimOutboxMessages(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxMessage, error) {
+	if f.claimErr != nil {
+		return nil, f.claimErr
+	}
+	var claimed []domain.OutboxMessage
+	for i := range f.messages {
+		message := &f.messages[i]
+		if message.DeliveredAt != nil || message.NextAttemptAt.After(now) || len(claimed) == limit {
+			continue
+		}
+		message.Attempts++
+		message.NextAttemptAt = leaseUntil
+		claimed = append(claimed, *message)
+	}
+	return claimed, nil
+}
+
+func (f *fakeOutboxStorage) MarkOutboxDelivered(_ context.Context, id int64, deliveredAt time.Time) error {
+	if f.delivered == nil {
+		f.delivered = make(map[int64]time.Time)
+	}
+	f.delivered[id] = deliveredAt
+	f.messages[id-1].DeliveredAt = &deliveredAt
+	return nil
+}
+
+func (f *fakeOutboxStorage) MarkOutboxFailed(_ context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	if f.failed == nil {
+		f.failed = make(map[int64]string)
+	}
+	f.failed[id] = lastError
+	f.messages[id-1].NextAttemptAt = nextAttemptAt
+	f.messages[id-1].LastError = lastError
+	return nil
+}
+
+type fakePublisher struct {
+	err       error
+	published []int64
+}
+
+func (f *fakePublisher) Publish(_ context.Context, message domain.OutboxMessage) error {
+	f.published = append(f.published, message.ID)
+	return f.err
+}
+
+type fakeWebhookStorage struct {
+	subscriptions []domain.WebhookSubscription
+	deliveries    []domain.WebhookDelivery
+}
+
+func (f *fakeWebhookStorage) CreateWebhook(_ context.Context, subscription domain.WebhookSubscription) error {
+	f.subscriptions = append(f.subscriptions, subscription)
+	return nil
+}
+
+func (f *fakeWebhookStorage) GetWebhook(_ context.Context, id string) (domain.WebhookSubscription, error) {
+	for _, subscription := range f.subscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+	return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+}
+
+func (f *fakeWebhookStorage) ListWebhooks(_ context.Context) ([]domain.WebhookSubscription, error) {
+	return f.subscriptions, nil
+}
+
+func (f *fakeWebhookStorage) DeleteWebhook(_ context.Context, id string) error {
+	for i, subscription := range f.subscriptions {
+		if subscription.ID == id {
+			f.subscriptions = slices.Delete(f.subscriptions, i, i+1)
+			return nil
+		}
+	}
+	return domain.ErrWebhookNotFound
+}
+
+func (f *fakeWebhookStorage) CreateWebhookDeliveries(_ context.Context, deliveries []domain.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		duplicate := slices.ContainsFunc(f.deliveries, func(existing domain.WebhookDelivery) bool {
+			return existing.SubscriptionID == delivery.SubscriptionID && existing.MessageID == delivery.MessageID
+		})
+		if duplicate {
+			continue
+		}
+		delivery.ID = int64(len(f.deliveries) + 1)
+		f.deliveries = append(f.deliveries, delivery)
+	}
+	return nil
+}
+
+func (f *fakeWebhookStorage) ClaimWebhookDeliveries(_ context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var claimed []domain.WebhookDelivery
+	for i := range f.deliveries {
+		delivery := &f.deliveries[i]
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || len(claimed) == limit {
+			continue
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (f *fakeWebhookStorage) UpdateWebhookDelivery(_ context.Context, delivery domain.WebhookDelivery) error {
+	f.deliveries[delivery.ID-1] = delivery
+	return nil
+}
+
+func (f *fakeWebhookStorage) ListWebhookDeliveries(_ context.Context, subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	for _, delivery := range slices.Backward(f.deliveries) {
+		if delivery.SubscriptionID == subscriptionID && len(deliveries) < limit {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+type fakeWebhookSender struct {
+	status  int
+	err     error
+	headers []map[string]string
+}
+
+func (f *fakeWebhookSender) Send(_ context.Context, _ string, _ []byte, headers map[string]string) (int, error) {
+	f.headers = append(f.headers, headers)
+	return f.status, f.err
+}
+
+// fakeIdentityResolver ключ "provider:login"
+type fakeIdentityResolver map[string]string
+
+func (f fakeIdentityResolver) ResolveUser(_ context.Context, provider, login string) (string, error) {
+	if userID, ok := f[provider+":"+login]; ok {
+		return userID, nil
+	}
+	return "", domain.ErrIdentityNotFound
+}
+
+func (f fakeIdentityResolver) ResolveLogin(_ context.Context, provider, userID string) (string, error) {
+	for key, id := range f {
+		if login, ok := strings.CutPrefix(key, provider+":"); ok && id == userID {
+			return login, nil
+		}
+	}
+	return "", domain.ErrIdentityNotFound
+}
+
+type fakeUserIdentityStorage struct {
+	identities []domain.UserIdentity
+}
+
+func (f *fakeUserIdentityStorage) CreateUserIdentity(_ context.Context, identity domain.UserIdentity) error {
+	for _, existing := range f.identities {
+		if existing.Provider == identity.Provider &&
+			(existing.UserID == identity.UserID || strings.EqualFold(existing.Handle, identity.Handle)) {
+			return domain.ErrIdentityExists
+		}
+	}
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+func (f *fakeUserIdentityStorage) GetUserIdentity(_ context.Context, id string) (domain.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.ID == id {
+			return identity, nil
+		}
+	}
+	return domain.UserIdentity{}, domain.ErrIdentityNotFound
+}
+
+func (f *fakeUserIdentityStorage) FindUserIdentity(_ context.Context, provider, handle string) (domain.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && strings.EqualFold(identity.Handle, handle) {
+			return identity, nil
+		}
+	}
+	return domain.UserIdentity{}, domain.ErrIdentityNotFound
+}
+
+func (f *fakeUserIdentityStorage) ListUserIdentities(_ context.Context, filter domain.UserIdentityFilter) ([]domain.UserIdentity, error) {
+	var identities []domain.UserIdentity
+	for _, identity := range f.identities {
+		if (filter.UserID == "" || identity.UserID == filter.UserID) && (filter.Provider == "" || identity.Provider == filter.Provider) {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func (f *fakeUserIdentityStorage) UpdateUserIdentity(_ context.Context, identity domain.UserIdentity) error {
+	for i, existing := range f.identities {
+		if existing.ID != identity.ID && existing.Provider == identity.Provider && strings.EqualFold(existing.Handle, identity.Handle) {
+			return domain.ErrIdentityExists
+		}
+		if existing.ID == identity.ID {
+			f.identities[i] = identity
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserIdentityStorage) DeleteUserIdentity(_ context.Context, id string) error {
+	for i, identity := range f.identities {
+		if identity.ID == id {
+			f.identities = slices.Delete(f.identities, i, i+1)
+			return nil
+		}
+	}
+	return domain.ErrIdentityNotFound
+}
+
+type fakeReviewerPublisher struct {
+	err       error
+	requested []string
+	removed   []string
+}
+
+func (f *fakeReviewerPublisher) RequestReviewers(_ context.Context, repository string, number int, logins []string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.requested = append(f.requested, fmt.Sprintf("%s#%d:%s", repository, number, strings.Join(logins, ",")))
+	return nil
+}
+
+func (f *fakeReviewerPublisher) RemoveReviewRequests(_ context.Context, repository string, number int, logins []string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.removed = append(f.removed, fmt.Sprintf("%s#%d:%s", repository, number, strings.Join(logins, ",")))
+	return nil
+}
+
+type fakeNotifier struct {
+	provider string
+	err      error
+	sent     []string
+	received []domain.Notification
+}
+
+func (f *fakeNotifier) Provider() string {
+	return f.provider
+}
+
+// Notify записывает уведомление как "handle:kind:title:author:other"
+func (f *fakeNotifier) Notify(_ context.Context, handle string, n domain.Notification) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, strings.Join([]string{handle, n.Kind, n.Title, n.AuthorName, n.OtherReviewerName}, ":"))
+	f.received = append(f.received, n)
+	return nil
+}
+
+type fakeNotificationDeliveryStorage struct {
+	deliveries []domain.NotificationDelivery
+}
+
+func (f *fakeNotificationDeliveryStorage) ListNotificationDeliveries(_ context.Context, messageID int64) ([]domain.NotificationDelivery, error) {
+	var result []domain.NotificationDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.MessageID == messageID {
+			result = append(result, delivery)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNotificationDeliveryStorage) CreateNotificationDelivery(_ context.Context, delivery domain.NotificationDelivery) error {
+	f.deliveries = append(f.deliveries, delivery)
+	return nil
+}
+
+type fakeReviewReminderStorage struct {
+	reviews  []domain.OverdueReview
+	reminded map[string]bool
+}
+
+func reminderKey(review domain.OverdueReview) string {
+	return review.PullRequestID + ":" + review.ReviewerID + ":" + review.AssignedAt.String()
+}
+
+func (f *fakeReviewReminderStorage) ListOverdueReviews(_ context.Context, assignedBefore time.Time, limit int) ([]domain.OverdueReview, error) {
+	var reviews []domain.OverdueReview
+	for _, review := range f.reviews {
+		if review.AssignedAt.Before(assignedBefore) && !f.reminded[reminderKey(review)] && len(reviews) < limit {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews, nil
+}
+
+func (f *fakeReviewReminderStorage) MarkReviewReminded(_ context.Context, review domain.OverdueReview, _ time.Time) (bool, error) {
+	if f.reminded[reminderKey(review)] {
+		return false, nil
+	}
+	f.reminded[reminderKey(review)] = true
+	return true, nil
+}
+
+type fakeReviewDigestStorage map[string]bool
+
+func (f fakeReviewDigestStorage) MarkDigestSent(_ context.Context, userID string, localDate, _ time.Time) (bool, error) {
+	key := userID + ":" + localDate.Format(time.DateOnly)
+	if f[key] {
+		return false, nil
+	}
+	f[key] = true
+	return true, nil
+}
+
+// fakeEventLogStorage журнал в порядке потока: закоммиченные сообщения встают в конец
+// при нумерации, поэтому id, выданный через reserve, может оказаться после больших id.
+type fakeEventLogStorage struct {
+	messages  []domain.OutboxMessage
+	committed []domain.OutboxMessage
+	lastID    int64
+}
+
+func (f *fakeEventLogStorage) add(event domain.Event) {
+	f.commit(f.reserve(), event)
+}
+
+// reserve выдаёт id сообщению незавершённой транзакции; в журнале его нет до commit.
+func (f *fakeEventLogStorage) reserve() int64 {
+	f.lastID++
+	return f.lastID
+}
+
+func (f *fakeEventLogStorage) commit(id int64, event domain.Event) {
+	payload, _ := json.Marshal(event)
+	f.committed = append(f.committed, domain.OutboxMessage{
+		ID:          id,
+		EventType:   event.EventType(),
+		AggregateID: event.AggregateID(),
+		Payload:     payload,
+	})
+}
+
+func (f *fakeEventLogStorage) SequenceOutboxMessages(_ context.Context, limit int) (int, error) {
+	sequenced := min(limit, len(f.committed))
+	f.messages = append(f.messages, f.committed[:sequenced]...)
+	f.committed = f.committed[sequenced:]
+	return sequenced, nil
+}
+
+func (f *fakeEventLogStorage) position(id int64) int {
+	return slices.IndexFunc(f.messages, func(message domain.OutboxMessage) bool { return message.ID == id })
+}
+
+func (f *fakeEventLogStorage) ListOutboxMessagesAfter(_ context.Context, afterID, untilID int64, limit int) ([]domain.OutboxMessage, error) {
+	from, until := 0, len(f.messages)
+	if afterID != 0 {
+		if from = f.position(afterID) + 1; from == 0 {
+			return nil, domain.ErrEventNotFound
+		}
+	}
+	if untilID != 0 {
+		until = f.position(untilID) + 1
+	}
+	if from >= until {
+		return nil, nil
+	}
+	return slices.Clone(f.messages[from:min(until, from+limit)]), nil
+}
+
+func (f *fakeEventLogStorage) LastOutboxMessageID(_ context.Context) (int64, error) {
+	if len(f.messages) == 0 {
+		return 0, nil
+	}
+	return f.messages[len(f.messages)-1].ID, nil
+}
+
+type fakeHistoryStorage struct {
+	entries []domain.PullRequestHistoryEntry
+}
+
+func (f *fakeHistoryStorage) AddPullRequestHistory(_ context.Context, entries []domain.PullRequestHistoryEntry) error {
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeHistoryStorage) ListPullRequestHistory(_ context.Context, prID string) ([]domain.PullRequestHistoryEntry, error) {
+	var result []domain.PullRequestHistoryEntry
+	for _, entry := range f.entries {
+		if entry.PullRequestID == prID {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
+
+type fakeAuditStorage struct {
+	events []domain.AuditEvent
+	filter domain.AuditFilter
+}
+
+func (f *fakeAuditStorage) CreateAuditEvent(_ context.Context, event domain.AuditEvent) error {
+	event.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeAuditStorage) ListAuditEvents(_ context.Context, filter domain.AuditFilter) ([]domain.AuditEvent, error) {
+	f.filter = filter
+	return f.events, nil
+}
+
+type fakeIDGenerator struct {
+	next int
+}
+
+func (f *fakeIDGenerator) NewID() string {
+	f.next++
+	return fmt.Sprintf("id-%d", f.next)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f fakeClock) Now() time.Time {
+	return f.now
+}
+
+type fakeRandom struct {
+	calls int
+}
+
+func (f *fakeRandom) Shuffle(n int, swap func(i, j int)) {
+	f.calls++
+	if n > 1 {
+		swap(0, n-1)
+	}
+}
+
+type fakeRotationStorage struct {
+	mu      sync.Mutex
+	cursors map[string]string
+	err     error
+}
+
+func newFakeRotationStorage() *fakeRotationStorage {
+	return &fakeRotationStorage{cursors: make(map[string]string)}
+}
+
+func (f *fakeRotationStorage) GetRotation(_ context.Context, teamName string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.cursors[teamName], nil
+}
+
+func (f *fakeRotationStorage) LockRotation(ctx context.Context, teamName string) (string, error) {
+	return f.GetRotation(ctx, teamName)
+}
+
+func (f *fakeRotationStorage) SetRotation(_ context.Context, teamName, lastUserID string) error {
+	f.mu.Lock()
+	f.cursors[teamName] = lastUserID
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeRandom) Float64() float64 {
+	return 0.5
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func seniorUser(id, name, teamName string) domain.User {
+	user := domain.NewUser(id, name, teamName, true)
+	user.Level = domain.LevelSenior
+	return user
+}
+
+func seniorPolicyTeam(name string, users ...domain.User) domain.Team {
+	team := domain.NewTeam(name, users)
+	team.RequireSeniorReviewer = true
+	return team
+}
+
+func TestAuditTrail_Record(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithActor(context.Background(), "user:bob")
+	storage := &fakeAuditStorage{}
+	trail := NewAuditTrail(storage, fakeClock{now: time.Unix(500, 0)})
+
+	before := domain.NewUser("u1", "Bob", "backend", true)
+	after := before
+	after.IsActive = false
+	if err := trail.Record(ctx, domain.AuditUserDeactivated, domain.AuditEntityUser, "u1", before, after); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := trail.Record(ctx, domain.AuditTeamCreated, domain.AuditEntityTeam, "backend", nil, domain.NewTeam("backend", nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(storage.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(storage.events))
+	}
+	event := storage.events[0]
+	if event.Actor != "user:bob" || event.Action != domain.AuditUserDeactivated || !event.CreatedAt.Equal(time.Unix(500, 0)) {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	var state domain.User
+	if err := json.Unmarshal(event.After, &state); err != nil || state.ID != "u1" || state.IsActive {
+		t.Fatalf("unexpected after state %s: %v", event.After, err)
+	}
+	if storage.events[1].Before != nil {
+		t.Fatalf("expected no before state on creation, got %s", storage.events[1].Before)
+	}
+}
+
+func TestListAuditEventsUseCase_List(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	from := time.Unix(200, 0)
+	to := time.Unix(100, 0)
+
+	tests := []struct {
+		name      string
+		filter    domain.AuditFilter
+		wantLimit int
+		wantErr   error
+	}{
+		{
+			name:      "default limit",
+			filter:    domain.AuditFilter{EntityType: domain.AuditEntityPullRequest},
+			wantLimit: defaultAuditLimit,
+		},
+		{
+			name:      "explicit limit",
+			filter:    domain.AuditFilter{Limit: 10},
+			wantLimit: 10,
+		},
+		{
+			name:    "limit above maximum",
+			filter:  domain.AuditFilter{Limit: maxAuditLimit + 1},
+			wantErr: domain.ErrInvalidAuditFilter,
+		},
+		{
+			name:    "from after to",
+			filter:  domain.AuditFilter{From: &from, To: &to},
+			wantErr: domain.ErrInvalidAuditFilter,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &fakeAuditStorage{}
+			uc := NewListAuditEventsUseCase(storage, testLogger())
+
+			_, err := uc.List(ctx, tt.filter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && storage.filter.Limit != tt.wantLimit {
+				t.Fatalf("expected limit %d, got %d", tt.wantLimit, storage.filter.Limit)
+			}
+		})
+	}
+}
+
+func TestOutboxRelay_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	errPublish := errors.New("publish failure")
+
+	pending := func(count int, attempts int) *fakeOutboxStorage {
+		storage := &fakeOutboxStorage{}
+		for i := 0; i < count; i++ {
+			storage.messages = append(storage.messages, domain.OutboxMessage{
+				ID:            int64(i + 1),
+				EventType:     domain.EventReviewerAssigned,
+				Attempts:      attempts,
+				NextAttemptAt: now,
+			})
+		}
+		return storage
+	}
+
+	tests := []struct {
+		name       string
+		storage    *fakeOutboxStorage
+		publishers []*fakePublisher
+		batchSize  int
+		wantCount  int
+		wantErr    error
+		verify     func(t *testing.T, storage *fakeOutboxStorage, publishers []*fakePublisher)
+	}{
+		{
+			name:       "delivers to every publisher",
+			storage:    pending(2, 0),
+			publishers: []*fakePublisher{{}, {}},
+			batchSize:  10,
+			wantCount:  2,
+			verify: func(t *testing.T, storage *fakeOutboxStorage, publishers []*fakePublisher) {
+				t.Helper()
+				for _, publisher := range publishers {
+					if !slices.Equal(publisher.published, []int64{1, 2}) {
+						t.Fatalf("expected both messages published, got %v", publisher.published)
+					}
+				}
+				if len(storage.delivered) != 2 || len(storage.failed) != 0 {
+					t.Fatalf("expected 2 delivered, got delivered=%v failed=%v", storage.delivered, storage.failed)
+				}
+			},
+		},
+		{
+			name:       "respects batch size",
+			storage:    pending(3, 0),
+			publishers: []*fakePublisher{{}},
+			batchSize:  2,
+			wantCount:  2,
+			verify: func(t *testing.T, storage *fakeOutboxStorage, _ []*fakePublisher) {
+				t.Helper()
+				if storage.messages[2].DeliveredAt != nil {
+					t.Fatalf("expected third message left for the next batch")
+				}
+			},
+		},
+		{
+			name:       "failed publisher schedules retry with backoff",
+			storage:    pending(1, 2),
+			publishers: []*fakePublisher{{}, {err: errPublish}},
+			batchSize:  10,
+			wantCount:  1,
+			verify: func(t *testing.T, storage *fakeOutboxStorage, _ []*fakePublisher) {
+				t.Helper()
+				message := storage.messages[0]
+				if message.DeliveredAt != nil {
+					t.Fatalf("expected message not delivered")
+				}
+				if message.Attempts != 3 || message.LastError != errPublish.Error() {
+					t.Fatalf("unexpected bookkeeping %+v", message)
+				}
+				if want := now.Add(4 * time.Second); !message.NextAttemptAt.Equal(want) {
+					t.Fatalf("expected next attempt at %v, got %v", want, message.NextAttemptAt)
+				}
+			},
+		},
+		{
+			name: "message scheduled later is skipped",
+			storage: func() *fakeOutboxStorage {
+				s := pending(1, 1)
+				s.messages[0].NextAttemptAt = now.Add(time.Minute)
+				return s
+			}(),
+			publishers: []*fakePublisher{{}},
+			batchSize:  10,
+			wantCount:  0,
+		},
+		{
+			name:       "claim error",
+			storage:    &fakeOutboxStorage{claimErr: errPublish},
+			publishers: []*fakePublisher{{}},
+			batchSize:  10,
+			wantErr:    errPublish,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			publishers := make([]EventPublisher, 0, len(tt.publishers))
+			for _, publisher := range tt.publishers {
+				publishers = append(publishers, publisher)
+			}
+			relay := NewOutboxRelay(tt.storage, publishers, fakeClock{now: now}, tt.batchSize, time.Millisecond, testLogger())
+
+			count, err := relay.RunOnce(ctx)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if count != tt.wantCount {
+				t.Fatalf("expected %d claimed, got %d", tt.wantCount, count)
+			}
+			if tt.verify != nil {
+				tt.verify(t, tt.storage, tt.publishers)
+			}
+		})
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 5, want: 16 * time.Second},
+		{attempts: 50, want: outboxMaxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Fatalf("attempts %d: expected %v, got %v", tt.attempts, tt.want, got)
+		}
+	}
+}
+
+func TestCreateWebhookUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name       string
+		url        string
+		secret     string
+		eventTypes []string
+		wantErr    error
+		wantTypes  []string
+	}{
+		{
+			name:       "valid subscription dedupes event types",
+			url:        "https://example.com/hook",
+			secret:     "s3cret",
+			eventTypes: []string{domain.EventPullRequestMerged, domain.EventPullRequestMerged, domain.EventUserDeactivated},
+			wantTypes:  []string{domain.EventPullRequestMerged, domain.EventUserDeactivated},
+		},
+		{
+			name:       "unsupported scheme",
+			url:        "ftp://example.com/hook",
+			secret:     "s3cret",
+			eventTypes: []string{domain.EventPullRequestMerged},
+			wantErr:    domain.ErrInvalidWebhook,
+		},
+		{
+			name:       "missing secret",
+			url:        "https://example.com/hook",
+			eventTypes: []string{domain.EventPullRequestMerged},
+			wantErr:    domain.ErrInvalidWebhook,
+		},
+		{
+			name:       "unknown event type",
+			url:        "https://example.com/hook",
+			secret:     "s3cret",
+			eventTypes: []string{"pull_request.closed"},
+			wantErr:    domain.ErrInvalidWebhook,
+		},
+		{
+			name:    "no event types",
+			url:     "https://example.com/hook",
+			secret:  "s3cret",
+			wantErr: domain.ErrInvalidWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &fakeWebhookStorage{}
+			audit := &fakeAuditor{}
+			uc := NewCreateWebhookUseCase(storage, fakeClock{now: now}, &fakeIDGenerator{}, &fakeTransactor{}, audit, testLogger())
+
+			subscription, err := uc.Create(ctx, tt.url, tt.secret, tt.eventTypes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if len(storage.subscriptions) != 0 || len(audit.entries) != 0 {
+					t.Fatalf("expected nothing stored, got %v and audit %v", storage.subscriptions, audit.entries)
+				}
+				return
+			}
+			if subscription.ID != "id-1" || !slices.Equal(subscription.EventTypes, tt.wantTypes) {
+				t.Fatalf("unexpected subscription %+v", subscription)
+			}
+			if len(storage.subscriptions) != 1 {
+				t.Fatalf("expected subscription stored, got %v", storage.subscriptions)
+			}
+			if !slices.Equal(audit.actions(), []string{domain.AuditWebhookCreated}) || audit.entries[0].entityID != subscription.ID {
+				t.Fatalf("expected webhook.created audit, got %+v", audit.entries)
+			}
+		})
+	}
+}
+
+func TestDeleteWebhookUseCase_Delete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	subscription := domain.WebhookSubscription{ID: "w1", URL: "https://example.com/hook", Secret: "s3cret"}
+
+	tests := []struct {
+		name        string
+		id          string
+		wantErr     error
+		wantActions []string
+	}{
+		{
+			name:        "deletes and audits",
+			id:          "w1",
+			wantActions: []string{domain.AuditWebhookDeleted},
+		},
+		{
+			name:    "unknown subscription",
+			id:      "missing",
+			wantErr: domain.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &fakeWebhookStorage{subscriptions: []domain.WebhookSubscription{subscription}}
+			audit := &fakeAuditor{}
+			uc := NewDeleteWebhookUseCase(storage, &fakeTransactor{}, audit, testLogger())
+
+			err := uc.Delete(ctx, tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(audit.actions(), tt.wantActions) {
+				t.Fatalf("expected audit %v, got %v", tt.wantActions, audit.actions())
+			}
+			if tt.wantErr == nil && len(storage.subscriptions) != 0 {
+				t.Fatalf("expected subscription deleted, got %v", storage.subscriptions)
+			}
+		})
+	}
+}
+
+func TestWebhookFanout_Publish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	storage := &fakeWebhookStorage{subscriptions: []domain.WebhookSubscription{
+		{ID: "merged", EventTypes: []string{domain.EventPullRequestMerged}},
+		{ID: "users", EventTypes: []string{domain.EventUserDeactivated}},
+		{ID: "all", EventTypes: domain.EventTypes},
+	}}
+	fanout := NewWebhookFanout(storage, fakeClock{now: now}, testLogger())
+
+	message := domain.OutboxMessage{
+		ID:          7,
+		EventType:   domain.EventPullRequestMerged,
+		AggregateID: "pr-1",
+		Payload:     []byte(`{"pull_request_id":"pr-1"}`),
+		Actor:       "admin",
+		CreatedAt:   now,
+	}
+	if err := fanout.Publish(ctx, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// повторная публикация того же сообщения не создаёт дублей
+	if err := fanout.Publish(ctx, message); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var subscriptions []string
+	for _, delivery := range storage.deliveries {
+		subscriptions = append(subscriptions, delivery.SubscriptionID)
+		if delivery.Status != domain.WebhookDeliveryPending || !delivery.NextAttemptAt.Equal(now) {
+			t.Fatalf("expected pending delivery due now, got %+v", delivery)
+		}
+	}
+	if !slices.Equal(subscriptions, []string{"merged", "all"}) {
+		t.Fatalf("expected deliveries for merged and all, got %v", subscriptions)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(storage.deliveries[0].Payload, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload["id"] != 7.0 || payload["type"] != domain.EventPullRequestMerged || payload["actor"] != "admin" {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+	if data, _ := payload["data"].(map[string]any); data["pull_request_id"] != "pr-1" {
+		t.Fatalf("expected event data in payload, got %v", payload["data"])
+	}
+}
+
+func TestWebhookDispatcher_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	errSend := errors.New("получатель ответил 500")
+	payload := []byte(`{"id":1}`)
+
+	pending := func(attempts int) *fakeWebhookStorage {
+		return &fakeWebhookStorage{
+			subscriptions: []domain.WebhookSubscription{{ID: "hook", URL: "https://example.com/hook", Secret: "s3cret"}},
+			deliveries: []domain.WebhookDelivery{{
+				ID:             1,
+				SubscriptionID: "hook",
+				MessageID:      1,
+				EventType:      domain.EventPullRequestMerged,
+				Payload:        payload,
+				Status:         domain.WebhookDeliveryPending,
+				Attempts:       attempts,
+				NextAttemptAt:  now,
+			}},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		storage   *fakeWebhookStorage
+		sender    *fakeWebhookSender
+		wantCount int
+		verify    func(t *testing.T, delivery domain.WebhookDelivery, sender *fakeWebhookSender)
+	}{
+		{
+			name:      "signed delivery succeeds",
+			storage:   pending(0),
+			sender:    &fakeWebhookSender{status: 200},
+			wantCount: 1,
+			verify: func(t *testing.T, delivery domain.WebhookDelivery, sender *fakeWebhookSender) {
+				t.Helper()
+				if delivery.Status != domain.WebhookDeliverySucceeded || delivery.ResponseStatus != 200 || delivery.DeliveredAt == nil {
+					t.Fatalf("expected succeeded delivery, got %+v", delivery)
+				}
+				headers := sender.headers[0]
+				if !signature.Verify("s3cret", payload, headers[WebhookSignatureHeader]) {
+					t.Fatalf("invalid signature %q", headers[WebhookSignatureHeader])
+				}
+				if headers[WebhookEventHeader] != domain.EventPullRequestMerged || headers[WebhookDeliveryHeader] != "1" {
+					t.Fatalf("unexpected headers %v", headers)
+				}
+			},
+		},
+		{
+			name:      "failure schedules retry with backoff",
+			storage:   pending(2),
+			sender:    &fakeWebhookSender{status: 500, err: errSend},
+			wantCount: 1,
+			verify: func(t *testing.T, delivery domain.WebhookDelivery, _ *fakeWebhookSender) {
+				t.Helper()
+				if delivery.Status != domain.WebhookDeliveryPending || delivery.Attempts != 3 {
+					t.Fatalf("expected pending delivery, got %+v", delivery)
+				}
+				if delivery.ResponseStatus != 500 || delivery.LastError != errSend.Error() {
+					t.Fatalf("unexpected bookkeeping %+v", delivery)
+				}
+				if want := now.Add(4 * webhookBaseBackoff); !delivery.NextAttemptAt.Equal(want) {
+					t.Fatalf("expected next attempt at %v, got %v", want, delivery.NextAttemptAt)
+				}
+			},
+		},
+		{
+			name:      "last attempt marks delivery failed",
+			storage:   pending(maxWebhookAttempts - 1),
+			sender:    &fakeWebhookSender{err: errSend},
+			wantCount: 1,
+			verify: func(t *testing.T, delivery domain.WebhookDelivery, _ *fakeWebhookSender) {
+				t.Helper()
+				if delivery.Status != domain.WebhookDeliveryFailed {
+					t.Fatalf("expected failed delivery, got %+v", delivery)
+				}
+			},
+		},
+		{
+			name: "delivery of deleted subscription is skipped",
+			storage: func() *fakeWebhookStorage {
+				s := pending(0)
+				s.subscriptions = nil
+				return s
+			}(),
+			sender:    &fakeWebhookSender{status: 200},
+			wantCount: 1,
+			verify: func(t *testing.T, _ domain.WebhookDelivery, sender *fakeWebhookSender) {
+				t.Helper()
+				if len(sender.headers) != 0 {
+					t.Fatalf("expected nothing sent, got %d requests", len(sender.headers))
+				}
+			},
+		},
+		{
+			name: "delivery scheduled later is skipped",
+			storage: func() *fakeWebhookStorage {
+				s := pending(1)
+				s.deliveries[0].NextAttemptAt = now.Add(time.Minute)
+				return s
+			}(),
+			sender:    &fakeWebhookSender{status: 200},
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dispatcher := NewWebhookDispatcher(tt.storage, tt.sender, fakeClock{now: now}, 10, time.Millisecond, testLogger())
+
+			count, err := dispatcher.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.wantCount {
+				t.Fatalf("expected %d claimed, got %d", tt.wantCount, count)
+			}
+			if tt.verify != nil {
+				tt.verify(t, tt.storage.deliveries[0], tt.sender)
+			}
+		})
+	}
+}
+
+func TestSyncPullRequestUseCase_Sync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	users := []domain.User{
+		domain.NewUser("author", "Alice", "backend", true),
+		domain.NewUser("r1", "Bob", "backend", true),
+		domain.NewUser("r2", "Carol", "backend", true),
+	}
+	event := func(action string) ExternalPullRequestEvent {
+		return ExternalPullRequestEvent{
+			Provider:      domain.IdentityProviderGitHub,
+			Action:        action,
+			PullRequestID: GitHubPullRequestID("acme/api", 42),
+			Title:         "Feature",
+			AuthorLogin:   "Alice-GH",
+		}
+	}
+	existing := domain.NewPullRequest("acme/api#42", "Feature", "author", "backend", time.Unix(1, 0))
+	draft := existing
+	draft.Status = domain.PRStatusDraft
+	closed := existing
+	closed.Status = domain.PRStatusClosed
+
+	tests := []struct {
+		name       string
+		initialPRs []domain.PullRequest
+		event      ExternalPullRequestEvent
+		wantResult string
+		wantErr    error
+		wantID     string
+		wantStatus string
+	}{
+		{
+			name:       "opened creates pull request for mapped author",
+			event:      event(ExternalActionOpened),
+			wantResult: SyncResultCreated,
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:       "redelivered opened is ignored",
+			initialPRs: []domain.PullRequest{existing},
+			event:      event(ExternalActionOpened),
+			wantResult: SyncResultIgnored,
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name: "draft is not created until ready for review",
+			event: func() ExternalPullRequestEvent {
+				e := event(ExternalActionOpened)
+				e.Draft = true
+				return e
+			}(),
+			wantResult: SyncResultIgnored,
+		},
+		{
+			name:       "ready for review creates pull request",
+			event:      event(ExternalActionReadyForReview),
+			wantResult: SyncResultCreated,
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:       "ready for review resumes draft",
+			initialPRs: []domain.PullRequest{draft},
+			event:      event(ExternalActionReadyForReview),
+			wantResult: SyncResultReady,
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:       "converted to draft pauses review",
+			initialPRs: []domain.PullRequest{existing},
+			event:      event(ExternalActionConvertedToDraft),
+			wantResult: SyncResultDraft,
+			wantStatus: domain.PRStatusDraft,
+		},
+		{
+			name:       "converted to draft of unknown pull request is ignored",
+			event:      event(ExternalActionConvertedToDraft),
+			wantResult: SyncResultIgnored,
+		},
+		{
+			name:       "merged merges pull request",
+			initialPRs: []domain.PullRequest{existing},
+			event:      event(ExternalActionMerged),
+			wantResult: SyncResultMerged,
+			wantStatus: domain.PRStatusMerged,
+		},
+		{
+			name:       "closed closes pull request",
+			initialPRs: []domain.PullRequest{existing},
+			event:      event(ExternalActionClosed),
+			wantResult: SyncResultClosed,
+			wantStatus: domain.PRStatusClosed,
+		},
+		{
+			name:       "reopened reopens closed pull request",
+			initialPRs: []domain.PullRequest{closed},
+			event:      event(ExternalActionReopened),
+			wantResult: SyncResultReopened,
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:       "ready for review does not reopen closed pull request",
+			initialPRs: []domain.PullRequest{closed},
+			event:      event(ExternalActionReadyForReview),
+			wantResult: SyncResultIgnored,
+			wantStatus: domain.PRStatusClosed,
+		},
+		{
+			name:       "close of unknown pull request is ignored",
+			event:      event(ExternalActionClosed),
+			wantResult: SyncResultIgnored,
+		},
+		{
+			name:       "merge of unknown pull request is ignored",
+			event:      event(ExternalActionMerged),
+			wantResult: SyncResultIgnored,
+		},
+		{
+			name: "reopened merge request from gitlab is created",
+			event: ExternalPullRequestEvent{
+				Provider:      domain.IdentityProviderGitLab,
+				Action:        ExternalActionReopened,
+				PullRequestID: GitLabMergeRequestID("acme/api", 42),
+				Title:         "Feature",
+				AuthorLogin:   "alice",
+			},
+			wantResult: SyncResultCreated,
+			wantID:     "acme/api!42",
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name: "merge request without known author is not created",
+			event: ExternalPullRequestEvent{
+				Provider:      domain.IdentityProviderGitLab,
+				Action:        ExternalActionReopened,
+				PullRequestID: GitLabMergeRequestID("acme/api", 42),
+				Title:         "Feature",
+			},
+			wantResult: SyncResultIgnored,
+			wantID:     "acme/api!42",
+		},
+		{
+			name: "unknown author",
+			event: func() ExternalPullRequestEvent {
+				e := event(ExternalActionOpened)
+				e.AuthorLogin = "stranger"
+				return e
+			}(),
+			wantErr: domain.ErrIdentityNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prStorage := newFakePullRequestStorage(tt.initialPRs...)
+			clock := fakeClock{now: time.Unix(100, 0)}
+			create := NewCreatePullRequestUseCase(prStorage, newFakeTeamStorage(domain.NewTeam("backend", users)), newFakeUserStorage(users...), clock,
+				NewRandomReviewerSelector(&fakeRandom{}), &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+			merge := NewMergePullRequestUseCase(prStorage, clock, &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, &fakeEventRecorder{}, testLogger())
+			identities := fakeIdentityResolver{
+				domain.IdentityProviderGitHub + ":Alice-GH": "author",
+				domain.IdentityProviderGitLab + ":alice":    "author",
+			}
+			lifecycle := NewPullRequestLifecycleUseCase(prStorage, &fakeTopUp{}, &fakeTransactor{}, &fakeAuditor{}, &fakeHistoryRecorder{}, testLogger())
+			uc := NewSyncPullRequestUseCase(create, merge, lifecycle, identities, testLogger())
+
+			result, err := uc.Sync(ctx, tt.event)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if result != tt.wantResult {
+				t.Fatalf("expected result %q, got %q", tt.wantResult, result)
+			}
+
+			wantID := tt.wantID
+			if wantID == "" {
+				wantID = "acme/api#42"
+			}
+			pr, err := prStorage.GetPullRequest(ctx, wantID)
+			if tt.wantStatus == "" {
+				if !errors.Is(err, domain.ErrPullRequestNotFound) {
+					t.Fatalf("expected no pull request, got %+v", pr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected pull request, got %v", err)
+			}
+			if pr.Status != tt.wantStatus || pr.AuthorID != "author" {
+				t.Fatalf("unexpected pull request %+v", pr)
+			}
+		})
+	}
+}
+
+func TestPullRequestLifecycleUseCase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	open := domain.NewPullRequest("p1", "Feature", "author", "backend", time.Unix(1, 0))
+	open.AssignReviewers([]string{"r1", "r2"})
+	draft := open
+	draft.Status = domain.PRStatusDraft
+	shortDraft := draft
+	shortDraft.Reviewers = []string{"r1"}
+	merged := open
+	merged.MarkMerged(time.Unix(2, 0))
+	closed := open
+	closed.Status = domain.PRStatusClosed
+
+	draftCall := func(uc *PullRequestLifecycleUseCase, id string) error {
+		_, err := uc.ConvertToDraft(ctx, id)
+		return err
+	}
+	readyCall := func(uc *PullRequestLifecycleUseCase, id string) error {
+		_, err := uc.MarkReady(ctx, id)
+		return err
+	}
+	closeCall := func(uc *PullRequestLifecycleUseCase, id string) error {
+		_, err := uc.Close(ctx, id)
+		return err
+	}
+	reopenCall := func(draft bool) func(uc *PullRequestLifecycleUseCase, id string) error {
+		return func(uc *PullRequestLifecycleUseCase, id string) error {
+			_, err := uc.Reopen(ctx, id, draft)
+			return err
+		}
+	}
+
+	tests := []struct {
+		name        string
+		pr          domain.PullRequest
+		call        func(uc *PullRequestLifecycleUseCase, id string) error
+		wantErr     error
+		wantStatus  string
+		wantActions []string
+		wantHistory []string
+		wantTopUp   []string
+	}{
+		{
+			name:        "draft keeps reviewers",
+			pr:          open,
+			call:        draftCall,
+			wantStatus:  domain.PRStatusDraft,
+			wantActions: []string{domain.AuditPullRequestDrafted},
+			wantHistory: []string{domain.PRHistoryConvertedToDraft},
+		},
+		{
+			name:       "repeated draft changes nothing",
+			pr:         draft,
+			call:       draftCall,
+			wantStatus: domain.PRStatusDraft,
+		},
+		{
+			name:        "ready resumes review",
+			pr:          draft,
+			call:        readyCall,
+			wantStatus:  domain.PRStatusOpen,
+			wantActions: []string{domain.AuditPullRequestReady},
+			wantHistory: []string{domain.PRHistoryReadyForReview},
+		},
+		{
+			name:        "ready tops up reviewers lost while paused",
+			pr:          shortDraft,
+			call:        readyCall,
+			wantStatus:  domain.PRStatusOpen,
+			wantActions: []string{domain.AuditPullRequestReady},
+			wantHistory: []string{domain.PRHistoryReadyForReview},
+			wantTopUp:   []string{"backend"},
+		},
+		{
+			name:       "merged cannot become draft",
+			pr:         merged,
+			call:       draftCall,
+			wantErr:    domain.ErrPullRequestMerged,
+			wantStatus: domain.PRStatusMerged,
+		},
+		{
+			name:       "closed cannot become ready",
+			pr:         closed,
+			call:       readyCall,
+			wantErr:    domain.ErrPullRequestClosed,
+			wantStatus: domain.PRStatusClosed,
+		},
+		{
+			name:        "close keeps reviewers",
+			pr:          open,
+			call:        closeCall,
+			wantStatus:  domain.PRStatusClosed,
+			wantActions: []string{domain.AuditPullRequestClosed},
+			wantHistory: []string{domain.PRHistoryClosed},
+		},
+		{
+			name:       "merged cannot be closed",
+			pr:         merged,
+			call:       closeCall,
+			wantErr:    domain.ErrPullRequestMerged,
+			wantStatus: domain.PRStatusMerged,
+		},
+		{
+			name:        "reopen resumes review",
+			pr:          closed,
+			call:        reopenCall(false),
+			wantStatus:  domain.PRStatusOpen,
+			wantActions: []string{domain.AuditPullRequestReopened},
+			wantHistory: []string{domain.PRHistoryReopened},
+		},
+		{
+			name:        "reopen as draft keeps review paused",
+			pr:          closed,
+			call:        reopenCall(true),
+			wantStatus:  domain.PRStatusDraft,
+			wantActions: []string{domain.AuditPullRequestReopened},
+			wantHistory: []string{domain.PRHistoryReopened},
+		},
+		{
+			name:       "reopen of open pull request changes nothing",
+			pr:         open,
+			call:       reopenCall(false),
+			wantStatus: domain.PRStatusOpen,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			prStorage := newFakePullRequestStorage(tt.pr)
+			topUp := &fakeTopUp{}
+			audit := &fakeAuditor{}
+			history := &fakeHistoryRecorder{}
+			uc := NewPullRequestLifecycleUseCase(prStorage, topUp, &fakeTransactor{}, audit, history, testLogger())
+
+			if err := tt.call(uc, tt.pr.ID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			stored, _ := prStorage.GetPullRequest(ctx, tt.pr.ID)
+			if stored.Status != tt.wantStatus || !slices.Equal(stored.Reviewers, tt.pr.Reviewers) {
+				t.Fatalf("unexpected pull request %+v", stored)
+			}
+			if !slices.Equal(audit.actions(), tt.wantActions) {
+				t.Fatalf("expected audit %v, got %v", tt.wantActions, audit.actions())
+			}
+			var events []string
+			for _, entry := range history.entries {
+				events = append(events, entry.Event)
+			}
+			if !slices.Equal(events, tt.wantHistory) {
+				t.Fatalf("expected history %v, got %v", tt.wantHistory, events)
+			}
+			if !slices.Equal(topUp.teams, tt.wantTopUp) {
+				t.Fatalf("expected top up %v, got %v", tt.wantTopUp, topUp.teams)
+			}
+		})
+	}
+}
+
+func TestReviewerSync_Publish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errUnavailable := errors.New("GitHub ответил 502")
+	identities := fakeIdentityResolver{
+		domain.IdentityProviderGitHub + ":bob-gh":   "r1",
+		domain.IdentityProviderGitHub + ":carol-gh": "r2",
+	}
+	message := func(event domain.Event, attempts int) domain.OutboxMessage {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return domain.OutboxMessage{ID: 1, EventType: event.EventType(), AggregateID: event.AggregateID(), Payload: payload, Attempts: attempts}
+	}
+
+	tests := []struct {
+		name          string
+		message       domain.OutboxMessage
+		publishErr    error
+		wantErr       error
+		wantRequested []string
+		wantRemoved   []string
+		wantAudit     []string
+	}{
+		{
+			name:          "assigned reviewer is requested",
+			message:       message(domain.ReviewerAssigned{PullRequestID: "acme/api#7", ReviewerID: "r1"}, 1),
+			wantRequested: []string{"acme/api#7:bob-gh"},
+		},
+		{
+			name:          "replaced reviewer is swapped",
+			message:       message(domain.ReviewerReplaced{PullRequestID: "acme/api#7", OldReviewerID: "r1", NewReviewerID: "r2", Reason: domain.PRHistoryReasonDeactivation}, 1),
+			wantRequested: []string{"acme/api#7:carol-gh"},
+			wantRemoved:   []string{"acme/api#7:bob-gh"},
+		},
+		{
+			name:        "removed reviewer request is withdrawn",
+			message:     message(domain.ReviewerRemoved{PullRequestID: "acme/api#7", ReviewerID: "r2"}, 1),
+			wantRemoved: []string{"acme/api#7:carol-gh"},
+		},
+		{
+			name:          "reviewer without github login is skipped",
+			message:       message(domain.ReviewerReplaced{PullRequestID: "acme/api#7", OldReviewerID: "r3", NewReviewerID: "r1"}, 1),
+			wantRequested: []string{"acme/api#7:bob-gh"},
+		},
+		{
+			name:    "pull request not from github is skipped",
+			message: message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 1),
+		},
+		{
+			name:    "other events are skipped",
+			message: message(domain.PullRequestMerged{PullRequestID: "acme/api#7"}, 1),
+		},
+		{
+			name:       "temporary failure is retried",
+			message:    message(domain.ReviewerAssigned{PullRequestID: "acme/api#7", ReviewerID: "r1"}, 1),
+			publishErr: errUnavailable,
+			wantErr:    errUnavailable,
+		},
+		{
+			name:       "rejected request is reported",
+			message:    message(domain.ReviewerAssigned{PullRequestID: "acme/api#7", ReviewerID: "r1"}, 1),
+			publishErr: fmt.Errorf("%w: 422", domain.ErrReviewRequestRejected),
+			wantAudit:  []string{domain.AuditReviewRequestFailed},
+		},
+		{
+			name:       "exhausted retries are reported",
+			message:    message(domain.ReviewerAssigned{PullRequestID: "acme/api#7", ReviewerID: "r1"}, maxReviewerSyncAttempts),
+			publishErr: errUnavailable,
+			wantAudit:  []string{domain.AuditReviewRequestFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			publisher := &fakeReviewerPublisher{err: tt.publishErr}
+			audit := &fakeAuditor{}
+			sync := NewReviewerSync(publisher, identities, audit, testLogger())
+
+			err := sync.Publish(ctx, tt.message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(publisher.requested, tt.wantRequested) || !slices.Equal(publisher.removed, tt.wantRemoved) {
+				t.Fatalf("expected requested %v removed %v, got %v %v", tt.wantRequested, tt.wantRemoved, publisher.requested, publisher.removed)
+			}
+			if !slices.Equal(audit.actions(), tt.wantAudit) {
+				t.Fatalf("expected audit %v, got %v", tt.wantAudit, audit.actions())
+			}
+		})
+	}
+}
+
+func TestCreateUserIdentityUseCase_Create(t *testing.T) {
+	t.Parallel()
+
+	ctx := WithActor(context.Background(), "admin")
+	now := time.Unix(1000, 0)
+	linked := domain.UserIdentity{ID: "existing", UserID: "u1", Provider: domain.IdentityProviderGitHub, Handle: "Alice-GH"}
+
+	tests := []struct {
+		name      string
+		userID    string
+		provider  string
+		handle    string
+		wantErr   error
+		wantAudit []string
+	}{
+		{
+			name:      "links identity",
+			userID:    "u2",
+			provider:  domain.IdentityProviderGitHub,
+			handle:    " bob-gh ",
+			wantAudit: []string{domain.AuditIdentityAdded},
+		},
+		{
+			name:      "same handle in another provider",
+			userID:    "u2",
+			provider:  domain.IdentityProviderGitLab,
+			handle:    "alice-gh",
+			wantAudit: []string{domain.AuditIdentityAdded},
+		},
+		{
+			name:     "handle is taken case-insensitively",
+			userID:   "u2",
+			provider: domain.IdentityProviderGitHub,
+			handle:   "alice-gh",
+			wantErr:  domain.ErrIdentityExists,
+		},
+		{
+			name:     "user already linked in provider",
+			userID:   "u1",
+			provider: domain.IdentityProviderGitHub,
+			handle:   "alice-second",
+			wantErr:  domain.ErrIdentityExists,
+		},
+		{
+			name:      "valid email",
+			userID:    "u2",
+			provider:  domain.IdentityProviderEmail,
+			handle:    "bob@example.com",
+			wantAudit: []string{domain.AuditIdentityAdded},
+		},
+		{
+			name:     "invalid email",
+			userID:   "u2",
+			provider: domain.IdentityProviderEmail,
+			handle:   "Bob <bob@example.com>",
+			wantErr:  domain.ErrInvalidIdentity,
+		},
+		{
+			name:     "unknown provider",
+			userID:   "u2",
+			provider: "bitbucket",
+			handle:   "bob",
+			wantErr:  domain.ErrInvalidIdentity,
+		},
+		{
+			name:     "unknown user",
+			userID:   "ghost",
+			provider: domain.IdentityProviderGitHub,
+			handle:   "ghost",
+			wantErr:  domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &fakeUserIdentityStorage{identities: []domain.UserIdentity{linked}}
+			users := newFakeUserStorage(domain.NewUser("u1", "Alice", "backend", true), domain.NewUser("u2", "Bob", "backend", true))
+			tx := &fakeTransactor{}
+			audit := &fakeAuditor{}
+			uc := NewCreateUserIdentityUseCase(storage, users, fakeClock{now: now}, &fakeIDGenerator{}, tx, audit, testLogger())
+
+			identity, err := uc.Create(ctx, tt.userID, tt.provider, tt.handle)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(audit.actions(), tt.wantAudit) {
+				t.Fatalf("expected audit %v, got %v", tt.wantAudit, audit.actions())
+			}
+			if tt.wantErr != nil {
+				if len(storage.identities) != 1 {
+					t.Fatalf("expected no new identity, got %v", storage.identities)
+				}
+				return
+			}
+			if identity.Handle != strings.TrimSpace(tt.handle) || identity.UserID != tt.userID || len(storage.identities) != 2 {
+				t.Fatalf("unexpected identity %+v", identity)
+			}
+			if tx.calls != 1 || audit.entries[0].entityID != tt.userID || audit.entries[0].actor != "admin" {
+				t.Fatalf("expected audited transaction, got calls=%d entries=%v", tx.calls, audit.entries)
+			}
+		})
+	}
+}
+
+func TestIdentityLookup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	lookup := NewIdentityLookup(&fakeUserIdentityStorage{identities: []domain.UserIdentity{
+		{ID: "1", UserID: "u1", Provider: domain.IdentityProviderGitHub, Handle: "Alice-GH"},
+		{ID: "2", UserID: "u1", Provider: domain.IdentityProviderGitLab, Handle: "alice"},
+	}})
+
+	if userID, err := lookup.ResolveUser(ctx, domain.IdentityProviderGitHub, "alice-gh"); err != nil || userID != "u1" {
+		t.Fatalf("expected u1, got %q, %v", userID, err)
+	}
+	if _, err := lookup.ResolveUser(ctx, domain.IdentityProviderSlack, "alice"); !errors.Is(err, domain.ErrIdentityNotFound) {
+		t.Fatalf("expected identity not found, got %v", err)
+	}
+	if login, err := lookup.ResolveLogin(ctx, domain.IdentityProviderGitLab, "u1"); err != nil || login != "alice" {
+		t.Fatalf("expected alice, got %q, %v", login, err)
+	}
+	if _, err := lookup.ResolveLogin(ctx, domain.IdentityProviderEmail, "u1"); !errors.Is(err, domain.ErrIdentityNotFound) {
+		t.Fatalf("expected identity not found, got %v", err)
+	}
+}
+
+func TestReviewNotifier_Publish(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	identities := fakeIdentityResolver{
+		domain.IdentityProviderSlack + ":bob":   "r1",
+		domain.IdentityProviderSlack + ":carol": "r2",
+	}
+	message := func(event domain.Event, attempts int) domain.OutboxMessage {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return domain.OutboxMessage{ID: 1, EventType: event.EventType(), AggregateID: event.AggregateID(), Payload: payload, Attempts: attempts}
+	}
+
+	tests := []struct {
+		name      string
+		message   domain.OutboxMessage
+		delivered []domain.NotificationDelivery
+		notifyErr error
+		wantErr   bool
+		wantSent  []string
+	}{
+		{
+			name:     "assigned reviewer is notified",
+			message:  message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 1),
+			wantSent: []string{"bob:assigned:Feature:Alice:"},
+		},
+		{
+			name:    "both reviewers of replacement are notified",
+			message: message(domain.ReviewerReplaced{PullRequestID: "pr-1", OldReviewerID: "r1", NewReviewerID: "r2"}, 1),
+			wantSent: []string{
+				"carol:reassigned:Feature:Alice:Bob",
+				"bob:unassigned:Feature:Alice:Carol",
+			},
+		},
+		{
+			name:     "removed reviewer is notified",
+			message:  message(domain.ReviewerRemoved{PullRequestID: "pr-1", ReviewerID: "r2"}, 1),
+			wantSent: []string{"carol:unassigned:Feature:Alice:"},
+		},
+		{
+			name:    "reviewer without identity is skipped",
+			message: message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r3"}, 1),
+		},
+		{
+			name:      "delivered notification is not sent twice",
+			message:   message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 2),
+			delivered: []domain.NotificationDelivery{{MessageID: 1, UserID: "r1", Provider: domain.IdentityProviderSlack}},
+		},
+		{
+			name:      "redelivered replacement notifies only the pending recipient",
+			message:   message(domain.ReviewerReplaced{PullRequestID: "pr-1", OldReviewerID: "r1", NewReviewerID: "r2"}, 2),
+			delivered: []domain.NotificationDelivery{{MessageID: 1, UserID: "r2", Provider: domain.IdentityProviderSlack}},
+			wantSent:  []string{"bob:unassigned:Feature:Alice:Carol"},
+		},
+		{
+			name:    "unknown pull request is skipped",
+			message: message(domain.ReviewerAssigned{PullRequestID: "missing", ReviewerID: "r1"}, 1),
+		},
+		{
+			name:    "other events are skipped",
+			message: message(domain.PullRequestMerged{PullRequestID: "pr-1"}, 1),
+		},
+		{
+			name:      "channel failure is retried",
+			message:   message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 1),
+			notifyErr: errors.New("slack ответил 500"),
+			wantErr:   true,
+		},
+		{
+			name:      "channel failure after last attempt is dropped",
+			message:   message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, maxNotificationAttempts),
+			notifyErr: errors.New("slack ответил 500"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pr := domain.NewPullRequest("pr-1", "Feature", "a1", "backend", time.Unix(1000, 0))
+			users := newFakeUserStorage(
+				domain.NewUser("a1", "Alice", "backend", true),
+				domain.NewUser("r1", "Bob", "backend", true),
+				domain.NewUser("r2", "Carol", "backend", true),
+			)
+			notifier := &fakeNotifier{provider: domain.IdentityProviderSlack, err: tt.notifyErr}
+			deliveries := &fakeNotificationDeliveryStorage{deliveries: tt.delivered}
+			uc := NewReviewNotifier([]Notifier{notifier}, identities, newFakePullRequestStorage(pr), users, deliveries,
+				fakeClock{now: time.Unix(2000, 0)}, testLogger())
+
+			if err := uc.Publish(ctx, tt.message); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(notifier.sent, tt.wantSent) {
+				t.Fatalf("expected %v, got %v", tt.wantSent, notifier.sent)
+			}
+			if got := len(deliveries.deliveries) - len(tt.delivered); got != len(tt.wantSent) {
+				t.Fatalf("expected %d deliveries recorded, got %d", len(tt.wantSent), got)
+			}
+		})
+	}
+
+	t.Run("retry resends only to the failed channel", func(t *testing.T) {
+		t.Parallel()
+
+		pr := domain.NewPullRequest("pr-1", "Feature", "a1", "backend", time.Unix(1000, 0))
+		users := newFakeUserStorage(domain.NewUser("a1", "Alice", "backend", true), domain.NewUser("r1", "Bob", "backend", true))
+		resolver := fakeIdentityResolver{
+			domain.IdentityProviderSlack + ":bob":          "r1",
+			domain.IdentityProviderEmail + ":bob@acme.dev": "r1",
+		}
+		slack := &fakeNotifier{provider: domain.IdentityProviderSlack}
+		email := &fakeNotifier{provider: domain.IdentityProviderEmail, err: errors.New("smtp недоступен")}
+		deliveries := &fakeNotificationDeliveryStorage{}
+		uc := NewReviewNotifier([]Notifier{slack, email}, resolver, newFakePullRequestStorage(pr), users, deliveries,
+			fakeClock{now: time.Unix(2000, 0)}, testLogger())
+
+		if err := uc.Publish(ctx, message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 1)); err == nil {
+			t.Fatal("expected email failure to be returned for retry")
+		}
+		email.err = nil
+		if err := uc.Publish(ctx, message(domain.ReviewerAssigned{PullRequestID: "pr-1", ReviewerID: "r1"}, 2)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(slack.sent) != 1 || len(email.sent) != 1 {
+			t.Fatalf("expected one message per channel, got slack %v, email %v", slack.sent, email.sent)
+		}
+	})
+}
+
+func TestOverdueReviewNotifier_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	storage := &fakeReviewReminderStorage{
+		reviews: []domain.OverdueReview{
+			{PullRequestID: "pr-1", Title: "Old", AuthorID: "a1", ReviewerID: "r1", AssignedAt: now.Add(-50 * time.Hour)},
+			{PullRequestID: "pr-2", Title: "Fresh", AuthorID: "a1", ReviewerID: "r1", AssignedAt: now.Add(-time.Hour)},
+			{PullRequestID: "pr-3", Title: "Silent", AuthorID: "a1", ReviewerID: "r2", AssignedAt: now.Add(-72 * time.Hour)},
+		},
+		reminded: map[string]bool{},
+	}
+	users := newFakeUserStorage(domain.NewUser("a1", "Alice", "backend", true), domain.NewUser("r1", "Bob", "backend", true))
+	identities := fakeIdentityResolver{domain.IdentityProviderEmail + ":bob@example.com": "r1"}
+	notifier := &fakeNotifier{provider: domain.IdentityProviderEmail}
+	uc := NewOverdueReviewNotifier(storage, []Notifier{notifier}, identities, users, fakeClock{now: now}, 48*time.Hour, time.Minute, testLogger())
+
+	processed, err := uc.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// pr-3 тоже отмечен: у ревьюера нет email, но напоминать позже незачем
+	if processed != 2 || len(storage.reminded) != 2 {
+		t.Fatalf("expected 2 overdue reviews reminded, got %d, %v", processed, storage.reminded)
+	}
+	if want := []string{"bob@example.com:overdue:Old:Alice:"}; !slices.Equal(notifier.sent, want) {
+		t.Fatalf("expected %v, got %v", want, notifier.sent)
+	}
+
+	processed, err = uc.RunOnce(ctx)
+	if err != nil || processed != 0 || len(notifier.sent) != 1 {
+		t.Fatalf("expected no second reminder, got %d, %v, %v", processed, err, notifier.sent)
+	}
+}
+
+func TestSetUserDigestUseCase_SetDigest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		subscribed  bool
+		userID      string
+		enabled     bool
+		wantErr     error
+		wantEnabled bool
+		wantAudit   []string
+	}{
+		{
+			name:        "opt in",
+			userID:      "u1",
+			enabled:     true,
+			wantEnabled: true,
+			wantAudit:   []string{domain.AuditUserDigestUpdated},
+		},
+		{
+			name:       "opt out",
+			subscribed: true,
+			userID:     "u1",
+			wantAudit:  []string{domain.AuditUserDigestUpdated},
+		},
+		{
+			name:        "unchanged is not audited",
+			subscribed:  true,
+			userID:      "u1",
+			enabled:     true,
+			wantEnabled: true,
+		},
+		{
+			name:    "unknown user",
+			userID:  "ghost",
+			enabled: true,
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := domain.NewUser("u1", "Alice", "backend", true)
+			user.DigestEnabled = tt.subscribed
+			storage := newFakeUserStorage(user)
+			audit := &fakeAuditor{}
+			uc := NewSetUserDigestUseCase(storage, &fakeTransactor{}, audit, testLogger())
+
+			result, err := uc.SetDigest(ctx, tt.userID, tt.enabled)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(audit.actions(), tt.wantAudit) {
+				t.Fatalf("expected audit %v, got %v", tt.wantAudit, audit.actions())
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if result.DigestEnabled != tt.wantEnabled || storage.users["u1"].DigestEnabled != tt.wantEnabled {
+				t.Fatalf("expected digest enabled %v, got %v", tt.wantEnabled, storage.users["u1"].DigestEnabled)
+			}
+		})
+	}
+}
+
+func TestSetUserAbsenceUseCase_SetAbsence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	from := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, time.July, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		absent      bool
+		now         time.Time
+		userID      string
+		from        *time.Time
+		until       *time.Time
+		wantErr     error
+		wantAbsence bool
+		wantAudit   []string
+		wantTopUp   bool
+	}{
+		{
+			name:        "plan absence",
+			userID:      "u1",
+			from:        &from,
+			until:       &until,
+			wantAbsence: true,
+			wantAudit:   []string{domain.AuditUserAbsenceUpdated},
+		},
+		{
+			name:      "clear absence tops up team",
+			absent:    true,
+			userID:    "u1",
+			wantAudit: []string{domain.AuditUserAbsenceUpdated},
+			wantTopUp: true,
+		},
+		{
+			name:      "clear ended absence tops up team",
+			absent:    true,
+			now:       until.Add(time.Hour),
+			userID:    "u1",
+			wantAudit: []string{domain.AuditUserAbsenceUpdated},
+			wantTopUp: true,
+		},
+		{
+			name:      "cancel future absence",
+			absent:    true,
+			now:       from.Add(-time.Hour),
+			userID:    "u1",
+			wantAudit: []string{domain.AuditUserAbsenceUpdated},
+		},
+		{
+			name:        "unchanged is not audited",
+			absent:      true,
+			userID:      "u1",
+			from:        &from,
+			until:       &until,
+			wantAbsence: true,
+		},
+		{
+			name:    "until before from",
+			userID:  "u1",
+			from:    &until,
+			until:   &from,
+			wantErr: domain.ErrInvalidAbsence,
+		},
+		{
+			name:    "only one bound",
+			userID:  "u1",
+			from:    &from,
+			wantErr: domain.ErrInvalidAbsence,
+		},
+		{
+			name:    "unknown user",
+			userID:  "ghost",
+			from:    &from,
+			until:   &until,
+			wantErr: domain.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := domain.NewUser("u1", "Alice", "backend", true)
+			if tt.absent {
+				absentFrom, absentUntil := from, until
+				user.AbsentFrom, user.AbsentUntil = &absentFrom, &absentUntil
+			}
+			now := tt.now
+			if now.IsZero() {
+				now = from.Add(24 * time.Hour)
+			}
+			storage := newFakeUserStorage(user)
+			audit := &fakeAuditor{}
+			topUp := &fakeTopUp{}
+			uc := NewSetUserAbsenceUseCase(storage, topUp, fakeClock{now: now}, &fakeTransactor{}, audit, testLogger())
+
+			result, err := uc.SetAbsence(ctx, tt.userID, tt.from, tt.until)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if !slices.Equal(audit.actions(), tt.wantAudit) {
+				t.Fatalf("expected audit %v, got %v", tt.wantAudit, audit.actions())
+			}
+			if got := len(topUp.teams) > 0; got != tt.wantTopUp {
+				t.Fatalf("expected top up %v, got %v", tt.wantTopUp, topUp.teams)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			stored := storage.users["u1"]
+			if got := stored.IsAbsentAt(from); got != tt.wantAbsence || result.IsAbsentAt(from) != tt.wantAbsence {
+				t.Fatalf("expected absent %v, got %v", tt.wantAbsence, got)
+			}
+			if stored.IsAbsentAt(until) {
+				t.Fatal("expected absence to end at absent_until")
+			}
+		})
+	}
+}
+
+func TestAbsenceWatcher_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+	absentUser := func(id string, from, until time.Time) domain.User {
+		user := domain.NewUser(id, id, "backend", true)
+		user.AbsentFrom, user.AbsentUntil = &from, &until
+		return user
+	}
+
+	storage := newFakeUserStorage(
+		absentUser("ended", now.Add(-48*time.Hour), now.Add(-time.Hour)),
+		absentUser("ending-now", now.Add(-48*time.Hour), now),
+		absentUser("away", now.Add(-time.Hour), now.Add(time.Hour)),
+		domain.NewUser("present", "present", "backend", true),
+	)
+	topUp := &fakeTopUp{}
+	absences := NewSetUserAbsenceUseCase(storage, topUp, fakeClock{now: now}, &fakeTransactor{}, &fakeAuditor{}, testLogger())
+	watcher := NewAbsenceWatcher(storage, absences, fakeClock{now: now}, time.Minute, testLogger())
+
+	cleared, err := watcher.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleared != 2 {
+		t.Fatalf("expected 2 cleared absences, got %d", cleared)
+	}
+	for _, id := range []string{"ended", "ending-now"} {
+		if storage.users[id].AbsentUntil != nil {
+			t.Fatalf("expected absence of %s cleared", id)
+		}
+	}
+	if storage.users["away"].AbsentUntil == nil {
+		t.Fatal("expected ongoing absence kept")
+	}
+	if len(topUp.teams) != 2 {
+		t.Fatalf("expected top up per returned user, got %v", topUp.teams)
+	}
+
+	cleared, err = watcher.RunOnce(ctx)
+	if err != nil || cleared != 0 {
+		t.Fatalf("expected nothing to clear, got %d, %v", cleared, err)
+	}
+}
+
+func TestAbsenceWatcher_KeepsExtendedAbsence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+	user := domain.NewUser("u1", "u1", "backend", true)
+	from, ended, extended := now.Add(-48*time.Hour), now.Add(-time.Hour), now.Add(24*time.Hour)
+	user.AbsentFrom, user.AbsentUntil = &from, &ended
+	snapshot := user
+
+	// между выборкой кандидатов и снятием отсутствие продлили
+	user.AbsentUntil = &extended
+	storage := &staleUserStorage{fakeUserStorage: newFakeUserStorage(user), snapshot: []domain.User{snapshot}}
+	topUp := &fakeTopUp{}
+	audit := &fakeAuditor{}
+	absences := NewSetUserAbsenceUseCase(storage, topUp, fakeClock{now: now}, &fakeTransactor{}, audit, testLogger())
+	watcher := NewAbsenceWatcher(storage, absences, fakeClock{now: now}, time.Minute, testLogger())
+
+	cleared, err := watcher.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cleared != 0 {
+		t.Fatalf("expected nothing cleared, got %d", cleared)
+	}
+	if got := storage.users["u1"].AbsentUntil; got == nil || !got.Equal(extended) {
+		t.Fatalf("expected extended absence kept, got %v", got)
+	}
+	if len(audit.entries) != 0 || len(topUp.teams) != 0 {
+		t.Fatalf("expected no audit and top up, got %v, %v", audit.actions(), topUp.teams)
+	}
+}
+
+// staleUserStorage отдаёт в ListUsers заранее снятый список, как если бы он устарел к моменту изменения.
+type staleUserStorage struct {
+	*fakeUserStorage
+	snapshot []domain.User
+}
+
+func (s *staleUserStorage) ListUsers(context.Context) ([]domain.User, error) {
+	return s.snapshot, nil
+}
+
+func TestDigestScheduler_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Date(2025, 1, 10, 6, 30, 0, 0, time.UTC)
+
+	user := func(id, name, timezone string, digest bool) domain.User {
+		u := domain.NewUser(id, name, "backend", true)
+		u.Timezone = timezone
+		u.DigestEnabled = digest
+		return u
+	}
+	users := newFakeUserStorage(
+		user("a1", "Alice", "UTC", false),
+		user("r1", "Bob", "Europe/Moscow", true),
+		user("r2", "Carol", "UTC", true),
+		user("r3", "Dave", "Europe/Moscow", false),
+	)
+	pr := func(id string, age time.Duration, status string, reviewers ...string) domain.PullRequest {
+		p := domain.NewPullRequest(id, "Title "+id, "a1", "backend", now.Add(-age))
+		p.Status = status
+		p.Reviewers = reviewers
+		return p
+	}
+	prs := newFakePullRequestStorage(
+		pr("pr-1", 100*time.Hour, domain.PRStatusOpen, "r1"),
+		pr("pr-2", 30*time.Hour, domain.PRStatusOpen, "r1"),
+		pr("pr-3", 2*time.Hour, domain.PRStatusOpen, "r1", "r2", "r3"),
+		pr("pr-4", 200*time.Hour, domain.PRStatusMerged, "r1"),
+		pr("pr-5", 80*time.Hour, domain.PRStatusOpen, "r1"),
+		pr("pr-6", 50*time.Hour, domain.PRStatusDraft, "r1"),
+	)
+	identities := fakeIdentityResolver{
+		domain.IdentityProviderEmail + ":bob@example.com":   "r1",
+		domain.IdentityProviderEmail + ":carol@example.com": "r2",
+		domain.IdentityProviderEmail + ":dave@example.com":  "r3",
+	}
+	digests := fakeReviewDigestStorage{}
+	notifier := &fakeNotifier{provider: domain.IdentityProviderEmail}
+	scheduler := func(now time.Time) *DigestScheduler {
+		return NewDigestScheduler(users, prs, digests, []Notifier{notifier}, identities, fakeClock{now: now}, 9, time.Minute, testLogger())
+	}
+
+	// 09:30 в Москве, 06:30 в UTC: дайджест только у Боба
+	sent, err := scheduler(now).RunOnce(ctx)
+	if err != nil || sent != 1 {
+		t.Fatalf("expected 1 digest, got %d, %v", sent, err)
+	}
+	if want := []string{"bob@example.com:digest:::"}; !slices.Equal(notifier.sent, want) {
+		t.Fatalf("expected %v, got %v", want, notifier.sent)
+	}
+
+	var got []string
+	for _, group := range notifier.received[0].Digest {
+		for _, item := range group.Items {
+			got = append(got, fmt.Sprintf("%s:%s:%s:%s", group.Age, item.PullRequestID, item.AuthorName, item.Age))
+		}
+	}
+	want := []string{
+		"stale:pr-1:Alice:100h0m0s",
+		"stale:pr-5:Alice:80h0m0s",
+		"waiting:pr-2:Alice:30h0m0s",
+		"fresh:pr-3:Alice:2h0m0s",
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected groups %v, got %v", want, got)
+	}
+
+	// повторная проверка в тот же день ничего не шлёт
+	if sent, err := scheduler(now.Add(time.Minute)).RunOnce(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected no repeated digest, got %d, %v", sent, err)
+	}
+
+	// в 09:10 UTC дошла очередь Кэрол, а у Боба та же локальная дата
+	if sent, err := scheduler(now.Add(160 * time.Minute)).RunOnce(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected digest for Carol, got %d, %v", sent, err)
+	}
+	if len(notifier.sent) != 2 || notifier.sent[1] != "carol@example.com:digest:::" {
+		t.Fatalf("expected digest for Carol, got %v", notifier.sent)
+	}
+}
+
+func TestEventStream_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		filter  domain.EventFilter
+		wantIDs []int64
+	}{
+		{
+			name:    "no filter receives everything",
+			wantIDs: []int64{1, 2, 3, 4},
+		},
+		{
+			name:    "team filter",
+			filter:  domain.EventFilter{TeamName: "backend"},
+			wantIDs: []int64{1, 2, 4},
+		},
+		{
+			name:    "user filter matches reviewers, author and deactivated user",
+			filter:  domain.EventFilter{UserID: "u2"},
+			wantIDs: []int64{1, 2, 4},
+		},
+		{
+			name:    "user filter matches old reviewer of replacement",
+			filter:  domain.EventFilter{UserID: "u3"},
+			wantIDs: []int64{2},
+		},
+		{
+			name:    "team and user filters combine",
+			filter:  domain.EventFilter{TeamName: "frontend", UserID: "u2"},
+			wantIDs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage := &fakeEventLogStorage{}
+			stream := NewEventStream(storage, time.Millisecond, testLogger())
+			sub, err := stream.Subscribe(ctx, tt.filter, 0)
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			defer sub.Close()
+
+			storage.add(domain.ReviewerAssigned{PullRequestID: "pr-1", TeamName: "backend", ReviewerID: "u2"})
+			storage.add(domain.ReviewerReplaced{PullRequestID: "pr-1", TeamName: "backend", OldReviewerID: "u3", NewReviewerID: "u2"})
+			storage.add(domain.PullRequestMerged{PullRequestID: "pr-2", TeamName: "frontend", AuthorID: "u5"})
+			storage.add(domain.UserDeactivated{UserID: "u2", TeamName: "backend"})
+
+			read, err := stream.RunOnce(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if read != 4 {
+				t.Fatalf("expected 4 events read, got %d", read)
+			}
+
+			var got []int64
+			for len(sub.Events) > 0 {
+				got = append(got, (<-sub.Events).ID)
+			}
+			if !slices.Equal(got, tt.wantIDs) {
+				t.Fatalf("expected events %v, got %v", tt.wantIDs, got)
+			}
+		})
+	}
+}
+
+func TestEventStream_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("starts from the end of the log", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &fakeEventLogStorage{}
+		storage.add(domain.UserActivated{UserID: "u1", TeamName: "backend"})
+		stream := NewEventStream(storage, time.Millisecond, testLogger())
+
+		sub, err := stream.Subscribe(ctx, domain.EventFilter{}, 0)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		defer sub.Close()
+
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(sub.Backlog) != 0 || len(sub.Events) != 0 {
+			t.Fatalf("expected no old events, got backlog %d and %d live", len(sub.Backlog), len(sub.Events))
+		}
+	})
+
+	t.Run("resumes after last event id", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &fakeEventLogStorage{}
+		stream := NewEventStream(storage, time.Millisecond, testLogger())
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < eventStreamBatchSize+5; i++ {
+			storage.add(domain.UserActivated{UserID: fmt.Sprintf("u%d", i), TeamName: "backend"})
+		}
+		storage.add(domain.UserActivated{UserID: "other", TeamName: "frontend"})
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		storage.add(domain.UserActivated{UserID: "late", TeamName: "backend"})
+
+		sub, err := stream.Subscribe(ctx, domain.EventFilter{TeamName: "backend"}, 3)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		defer sub.Close()
+
+		if len(sub.Backlog) != eventStreamBatchSize+2 {
+			t.Fatalf("expected %d backlog events, got %d", eventStreamBatchSize+2, len(sub.Backlog))
+		}
+		if first, last := sub.Backlog[0].ID, sub.Backlog[len(sub.Backlog)-1].ID; first != 4 || last != int64(eventStreamBatchSize+5) {
+			t.Fatalf("expected backlog 4..%d, got %d..%d", eventStreamBatchSize+5, first, last)
+		}
+
+		// событие после курсора приходит уже живым, без дубля в backlog
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if message := <-sub.Events; message.ID != int64(eventStreamBatchSize+7) {
+			t.Fatalf("expected live event %d, got %d", eventStreamBatchSize+7, message.ID)
+		}
+	})
+
+	t.Run("event committed out of id order is not skipped", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &fakeEventLogStorage{}
+		stream := NewEventStream(storage, time.Millisecond, testLogger())
+		live, err := stream.Subscribe(ctx, domain.EventFilter{}, 0)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		defer live.Close()
+
+		slowTx := storage.reserve()
+		storage.add(domain.UserActivated{UserID: "fast", TeamName: "backend"})
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		storage.commit(slowTx, domain.UserActivated{UserID: "slow", TeamName: "backend"})
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []int64
+		for len(live.Events) > 0 {
+			got = append(got, (<-live.Events).ID)
+		}
+		if want := []int64{2, 1}; !slices.Equal(got, want) {
+			t.Fatalf("expected live events %v, got %v", want, got)
+		}
+
+		// клиент, получивший только событие 2, дочитывает позже закоммиченное 1
+		resumed, err := stream.Subscribe(ctx, domain.EventFilter{}, 2)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		defer resumed.Close()
+		if len(resumed.Backlog) != 1 || resumed.Backlog[0].ID != 1 {
+			t.Fatalf("expected backlog with event 1, got %+v", resumed.Backlog)
+		}
+	})
+
+	t.Run("unknown last event id resets the client", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &fakeEventLogStorage{}
+		storage.add(domain.UserActivated{UserID: "u1", TeamName: "backend"})
+		stream := NewEventStream(storage, time.Millisecond, testLogger())
+
+		for _, lastEventID := range []int64{1, 42} {
+			sub, err := stream.Subscribe(ctx, domain.EventFilter{}, lastEventID)
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			if want := lastEventID == 42; sub.Reset != want || len(sub.Backlog) != 0 {
+				t.Fatalf("last event id %d: expected reset %v, got %v with backlog %d", lastEventID, want, sub.Reset, len(sub.Backlog))
+			}
+			sub.Close()
+		}
+	})
+
+	t.Run("slow subscriber is disconnected", func(t *testing.T) {
+		t.Parallel()
+
+		storage := &fakeEventLogStorage{}
+		stream := NewEventStream(storage, time.Millisecond, testLogger())
+		slow, err := stream.Subscribe(ctx, domain.EventFilter{}, 0)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		for i := 0; i <= eventStreamBuffer; i++ {
+			storage.add(domain.UserActivated{UserID: "u1", TeamName: "backend"})
+		}
+		if _, err := stream.RunOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		received := 0
+		for range slow.Events {
+			received++
+		}
+		if received != eventStreamBuffer {
+			t.Fatalf("expected %d buffered events before disconnect, got %d", eventStreamBuffer, received)
+		}
+		if stream.Subscribers() != 0 {
+			t.Fatalf("expected slow subscriber removed, got %d", stream.Subscribers())
+		}
+		slow.Close()
+	})
+
+	t.Run("close ends subscriptions and rejects new ones", func(t *testing.T) {
+		t.Parallel()
+
+		stream := NewEventStream(&fakeEventLogStorage{}, time.Millisecond, testLogger())
+		sub, err := stream.Subscribe(ctx, domain.EventFilter{}, 0)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		other, err := stream.Subscribe(ctx, domain.EventFilter{}, 0)
+		if err != nil {
+			t.Fatalf("subscribe: %v", err)
+		}
+		other.Close()
+		if stream.Subscribers() != 1 {
+			t.Fatalf("expected 1 subscriber after unsubscribe, got %d", stream.Subscribers())
+		}
+
+		stream.Close()
+		if _, ok := <-sub.Events; ok {
+			t.Fatal("expected events channel closed")
+		}
+		if _, err := stream.Subscribe(ctx, domain.EventFilter{}, 0); !errors.Is(err, domain.ErrEventStreamClosed) {
+			t.Fatalf("expected ErrEventStreamClosed, got %v", err)
+		}
+	})
 }
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+	"github.com/che1nov/Pr-reviewer-assignment-service/pkg/signature"
+)
+
+// Заголовки исходящих вебхуков
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature-256"
+)
+
+const (
+	// maxWebhookAttempts после стольких неудачных попыток доставка помечается FAILED
+	maxWebhookAttempts = 8
+	// webhookLease на сколько откладывается забранная доставка
+	webhookLease = time.Minute
+	// webhookBaseBackoff и webhookMaxBackoff границы паузы между попытками
+	webhookBaseBackoff = 5 * time.Second
+	webhookMaxBackoff  = time.Hour
+)
+
+// WebhookDispatcher отправляет доставки вебхуков, подписывая тело HMAC-SHA256 секретом подписки.
+// Неудачные попытки повторяются с экспоненциальной паузой; каждая доставка остаётся в журнале.
+type WebhookDispatcher struct {
+	webhooks  WebhookStorage
+	sender    WebhookSender
+	clock     ClockAdapter
+	batchSize int
+	poll      time.Duration
+	log       *slog.Logger
+}
+
+func NewWebhookDispatcher(
+	webhookStorage WebhookStorage,
+	sender WebhookSender,
+	clock ClockAdapter,
+	batchSize int,
+	pollInterval time.Duration,
+	log *slog.Logger,
+) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhooks:  webhookStorage,
+		sender:    sender,
+		clock:     clock,
+		batchSize: batchSize,
+		poll:      pollInterval,
+		log:       log,
+	}
+}
+
+// Run отправляет доставки, пока не отменён ctx; когда отправлять нечего, ждёт poll.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		sent, err := d.RunOnce(ctx)
+		if err != nil {
+			d.log.ErrorContext(ctx, "ошибка получения доставок вебхуков", "error", err)
+		}
+		if sent > 0 && sent == d.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(d.poll):
+		}
+	}
+}
+
+// RunOnce забирает одну пачку доставок и отправляет их. Возвращает размер пачки.
+func (d *WebhookDispatcher) RunOnce(ctx context.Context) (int, error) {
+	now := d.clock.Now()
+	deliveries, err := d.webhooks.ClaimWebhookDeliveries(ctx, now, now.Add(webhookLease), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[string]domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return len(deliveries), nil
+		}
+
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.webhooks.GetWebhook(ctx, delivery.SubscriptionID)
+			if errors.Is(err, domain.ErrWebhookNotFound) {
+				// подписку удалили вместе с доставками
+				continue
+			}
+			if err != nil {
+				d.log.ErrorContext(ctx, "ошибка получения подписки на вебхук", "webhook_id", delivery.SubscriptionID, "error", err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		d.send(ctx, subscription, delivery)
+	}
+	return len(deliveries), nil
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, subscription domain.WebhookSubscription, delivery domain.WebhookDelivery) {
+	headers := map[string]string{
+		WebhookEventHeader:     delivery.EventType,
+		WebhookDeliveryHeader:  strconv.FormatInt(delivery.ID, 10),
+		WebhookSignatureHeader: signature.Sign(subscription.Secret, delivery.Payload),
+	}
+
+	status, err := d.sender.Send(ctx, subscription.URL, delivery.Payload, headers)
+	if err == nil {
+		delivery.Succeed(status, d.clock.Now())
+		d.log.InfoContext(ctx, "вебхук доставлен", "delivery_id", delivery.ID, "webhook_id", subscription.ID, "status", status)
+	} else {
+		var next *time.Time
+		if delivery.Attempts < maxWebhookAttempts {
+			at := d.clock.Now().Add(exponentialBackoff(delivery.Attempts, webhookBaseBackoff, webhookMaxBackoff))
+			next = &at
+		}
+		delivery.Retry(status, err, next)
+		d.log.WarnContext(ctx, "не удалось доставить вебхук",
+			"delivery_id", delivery.ID,
+			"webhook_id", subscription.ID,
+			"attempts", delivery.Attempts,
+			"status", delivery.Status,
+			"error", err,
+		)
+	}
+
+	if err := d.webhooks.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		d.log.ErrorContext(ctx, "ошибка сохранения доставки вебхука", "delivery_id", delivery.ID, "error", err)
+	}
+}
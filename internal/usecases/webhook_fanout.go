@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// webhookPayload тело запроса вебхука; id совпадает с id сообщения outbox и годится для отбрасывания повторов.
+type webhookPayload struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	Actor      string          `json:"actor"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// WebhookFanout EventPublisher, который ставит событие в очередь доставки каждой подходящей подписке.
+// Сами запросы отправляет WebhookDispatcher, поэтому медленный получатель не задерживает outbox.
+type WebhookFanout struct {
+	webhooks WebhookStorage
+	clock    ClockAdapter
+	log      *slog.Logger
+}
+
+func NewWebhookFanout(webhookStorage WebhookStorage, clock ClockAdapter, log *slog.Logger) *WebhookFanout {
+	return &WebhookFanout{
+		webhooks: webhookStorage,
+		clock:    clock,
+		log:      log,
+	}
+}
+
+func (f *WebhookFanout) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	subscriptions, err := f.webhooks.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:         message.ID,
+		Type:       message.EventType,
+		Actor:      message.Actor,
+		OccurredAt: message.CreatedAt,
+		Data:       message.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := f.clock.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(message.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			MessageID:      message.ID,
+			EventType:      message.EventType,
+			Payload:        payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	f.log.DebugContext(ctx, "событие поставлено в очередь вебхуков", "id", message.ID, "event_type", message.EventType, "deliveries", len(deliveries))
+	return f.webhooks.CreateWebhookDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// WeightedReviewerSelector выбирает ревьюеров случайно пропорционально ReviewWeight.
+// Кандидаты с весом 0 не выбираются никогда.
+type WeightedReviewerSelector struct {
+	rand RandomAdapter
+}
+
+func NewWeightedReviewerSelector(random RandomAdapter) *WeightedReviewerSelector {
+	return &WeightedReviewerSelector{rand: random}
+}
+
+// Select делает взвешенную выборку без возвращения по ReviewWeight.
+func (s *WeightedReviewerSelector) Select(_ context.Context, _ domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	return firstUsers(weightedOrder(s.rand, candidates, reviewWeight), count), nil
+}
+
+func reviewWeight(user domain.User) float64 {
+	return user.ReviewWeight
+}
+
+// weightedOrder упорядочивает кандидатов случайно с вероятностью быть выше пропорционально
+// весу (алгоритм Efraimidis-Spirakis): каждому назначается ключ ln(u)/w, порядок по убыванию
+// ключа. Кандидаты с весом 0 и меньше в результат не попадают.
+func weightedOrder(random RandomAdapter, candidates []domain.User, weight func(domain.User) float64) []domain.User {
+	type keyed struct {
+		user domain.User
+		key  float64
+	}
+
+	pool := make([]keyed, 0, len(candidates))
+	for _, candidate := range candidates {
+		w := weight(candidate)
+		if w <= 0 {
+			continue
+		}
+		pool = append(pool, keyed{
+			user: candidate,
+			key:  math.Log(random.Float64()) / w,
+		})
+	}
+
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].key > pool[j].key
+	})
+
+	ordered := make([]domain.User, 0, len(pool))
+	for _, item := range pool {
+		ordered = append(ordered, item.user)
+	}
+	return ordered
+}
@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/che1nov/Pr-reviewer-assignment-service/internal/domain"
+)
+
+// WorkingHoursReviewerSelector сначала выбирает среди тех, у кого сейчас рабочее время,
+// и добирает остальных, если таких не хватает.
+type WorkingHoursReviewerSelector struct {
+	next  ReviewerSelector
+	clock ClockAdapter
+}
+
+func NewWorkingHoursReviewerSelector(next ReviewerSelector, clock ClockAdapter) *WorkingHoursReviewerSelector {
+	return &WorkingHoursReviewerSelector{
+		next:  next,
+		clock: clock,
+	}
+}
+
+// Select упорядочивает кандидатов вложенной стратегией один раз и ставит вперёд тех,
+// у кого сейчас рабочее время; внутри групп порядок стратегии сохраняется.
+func (s *WorkingHoursReviewerSelector) Select(ctx context.Context, pr domain.PullRequest, candidates []domain.User, count int) ([]domain.User, error) {
+	ranked, err := s.next.Select(ctx, pr, candidates, len(candidates))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	slices.SortStableFunc(ranked, func(a, b domain.User) int {
+		return cmp.Compare(workingRank(a, now), workingRank(b, now))
+	})
+	return firstUsers(ranked, count), nil
+}
+
+// RecordSelection передаёт итоговый выбор вложенной стратегии.
+func (s *WorkingHoursReviewerSelector) RecordSelection(ctx context.Context, pr domain.PullRequest, selected []domain.User) error {
+	return recordSelection(ctx, s.next, pr, selected)
+}
+
+// workingRank 0 для тех, у кого сейчас рабочее время, иначе 1.
+func workingRank(user domain.User, now time.Time) int {
+	if user.IsWorkingAt(now) {
+		return 0
+	}
+	return 1
+}
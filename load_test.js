@@ -134,7 +134,7 @@ export default function () {
         });
 
         const deactivateSuccess = check(deactivateRes, {
-          'deactivate successful': (r) => r.status === 200,
+          'deactivate accepted': (r) => r.status === 202,
           'deactivate time < 100ms': (r) => r.timings.duration < 100,
         });
         
@@ -0,0 +1,19 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Generator выдаёт случайные идентификаторы из 32 hex-символов.
+type Generator struct{}
+
+func New() *Generator {
+	return &Generator{}
+}
+
+func (Generator) NewID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -16,3 +16,11 @@ func (a *Adapter) Shuffle(n int, swap func(i, j int)) {
 	}
 	a.rnd.Shuffle(n, swap)
 }
+
+// Float64 возвращает псевдослучайное число в [0, 1).
+func (a *Adapter) Float64() float64 {
+	if a == nil || a.rnd == nil {
+		return 0
+	}
+	return a.rnd.Float64()
+}
@@ -0,0 +1,26 @@
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Prefix префикс подписи в заголовке, как у GitHub: sha256=<hex>.
+const Prefix = "sha256="
+
+// Sign возвращает подпись body в формате sha256=<hex HMAC-SHA256>.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return Prefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify сравнивает подпись за постоянное время.
+func Verify(secret string, body []byte, signature string) bool {
+	if secret == "" || !strings.HasPrefix(signature, Prefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}